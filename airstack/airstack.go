@@ -3,7 +3,6 @@ package airstack
 import (
 	"bytes"
 	"context"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -171,67 +170,33 @@ func (a *Airstack) NumHoldersByTokenAnkrAPI(tokenAddress, blockchain string) (ui
 	return uint32(holderCount.(float64)), nil
 }
 
-func ValidateFrameMessage(msg []byte, apikey string) {
-	framePkg := &FrameSignaturePacket{}
-	if err := json.Unmarshal(msg, framePkg); err != nil {
-		log.Warn("failed to unmarshal frame signature packet:", err)
-		return
+// ReportFrameMessage sends the signed frame message provided to the Airstack
+// hubs to be validated, which registers it in the Airstack frame analytics. It
+// implements the framevalidator.Reporter interface.
+func (a *Airstack) ReportFrameMessage(ctx context.Context, messageBytes []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, validateFrameEndpoint, bytes.NewBuffer(messageBytes))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
 	}
-	go func() {
-		msgData, err := hex.DecodeString(framePkg.TrustedData.MessageBytes)
-		if err != nil {
-			log.Warn("failed to decode message bytes:", err)
-			return
-		}
-		req, err := http.NewRequest(http.MethodPost, validateFrameEndpoint, bytes.NewBuffer(msgData))
-		if err != nil {
-			log.Warn("error creating request:", err)
-			return
-		}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("x-airstack-hubs", a.ApiKey())
 
-		req.Header.Set("Content-Type", "application/octet-stream")
-		req.Header.Set("x-airstack-hubs", apikey)
-
-		res, err := http.DefaultClient.Do(req)
-		if err != nil {
-			log.Warn("error sending request:", err)
-			return
-		}
-		defer res.Body.Close()
-		airstackResponse := make(map[string]interface{})
-		if err := json.NewDecoder(res.Body).Decode(&airstackResponse); err != nil {
-			log.Warn("error decoding response:", err)
-			return
-		}
-		if res.StatusCode != http.StatusOK {
-			log.Warnw("airstack API returned unexpected status",
-				"code", res.StatusCode,
-				"url", req.URL.String(),
-				"authorization", req.Header.Get("x-airstack-hubs"),
-				"apikey", apikey,
-				"response", fmt.Sprintf("%+v", airstackResponse),
-				"msg size", len(msgData),
-			)
-			return
-		}
-		isValid, ok := airstackResponse["valid"]
-		if !ok {
-			log.Warn("isValid field missing in response")
-			log.Debugw("airstack response", "response", fmt.Sprintf("%+v", airstackResponse))
-			return
-		}
-		if valid, ok := isValid.(bool); !ok || !valid {
-			log.Warn("invalid frame message")
-			log.Debugw("airstack response", "response", fmt.Sprintf("%+v", airstackResponse))
-			return
-		}
-		log.Infow("frame message validated by airstack")
-	}()
-}
-
-// FrameSignaturePacket mirrors the JSON structure received by the Frame server.
-type FrameSignaturePacket struct {
-	TrustedData struct {
-		MessageBytes string `json:"messageBytes"`
-	} `json:"trustedData"`
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer res.Body.Close()
+	airstackResponse := make(map[string]interface{})
+	if err := json.NewDecoder(res.Body).Decode(&airstackResponse); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("airstack API returned unexpected status %d: %+v", res.StatusCode, airstackResponse)
+	}
+	if valid, ok := airstackResponse["valid"].(bool); !ok || !valid {
+		log.Debugw("airstack response", "response", fmt.Sprintf("%+v", airstackResponse))
+		return fmt.Errorf("invalid frame message")
+	}
+	log.Debugw("frame message validated by airstack")
+	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/vocdoni/vote-frame/farcasterapi/warpcast"
+	"github.com/vocdoni/vote-frame/features"
 	"github.com/vocdoni/vote-frame/mongo"
 	"go.vocdoni.io/dvote/httprouter"
 	"go.vocdoni.io/dvote/httprouter/apirest"
@@ -200,6 +201,10 @@ func (v *vocdoniHandler) sendAnnouncementsHandler(msg *apirest.APIdata, ctx *htt
 	if !isAdmin {
 		return ctx.Send([]byte("user is not an admin of the community"), http.StatusForbidden)
 	}
+	// check if the user has enough reputation to send announcements
+	if !features.IsAllowedInCommunity(features.SEND_ANNOUNCEMENTS, communityID, accessProfile.Reputation) {
+		return ctx.Send([]byte("user does not have enough reputation to send announcements"), http.StatusForbidden)
+	}
 	// check if the last community announcement is older than the default time span
 	if dbCommunity.LastAnnouncement.Add(DefaultAnnouncementTimeSpan).After(time.Now()) {
 		return ctx.Send([]byte("last announcement was less than 24 hours ago"), http.StatusBadRequest)
@@ -14,15 +14,29 @@ import (
 // defaultCoolDown is the default time to wait between casts
 const defaultCoolDown = time.Second * 10
 
-// muteRequestContent is the content of a cast that requests to mute a user that
-// created a poll and wants to avoid notifications from that user.
-const muteRequestContent = "mute"
+// defaultStreamInterval is the default time to wait for new events when the
+// mentions are streamed and there are no new events
+const defaultStreamInterval = time.Second * 2
+
+// CursorStore interface defines the storage of the event cursor used by the
+// bot to stream the mentions, to resume the stream after a restart without
+// dropping or duplicating mentions.
+type CursorStore interface {
+	// Load returns the stored event ID, or zero if there is no one.
+	Load() (uint64, error)
+	// Save stores the event ID provided.
+	Save(eventID uint64) error
+}
 
 // BotConfig is the configuration definition for the bot, it includes the API
-// instance and the cool down time between casts (default is 10 seconds)
+// instance and the cool down time between casts (default is 10 seconds). If
+// the cursor store is set and the API implements the
+// farcasterapi.MentionsStreamer interface, the bot streams the mentions from
+// the API events instead of polling the last mentions.
 type BotConfig struct {
 	API      farcasterapi.API
 	CoolDown time.Duration
+	Cursor   CursorStore
 }
 
 // Bot struct represents a bot that listens for new casts and sends them to a
@@ -36,6 +50,7 @@ type Bot struct {
 	cancel   context.CancelFunc
 	coolDown time.Duration
 	lastCast uint64
+	cursor   CursorStore
 	Messages chan *farcasterapi.APIMessage
 }
 
@@ -52,6 +67,7 @@ func New(config BotConfig) (*Bot, error) {
 		api:      config.API,
 		coolDown: config.CoolDown,
 		lastCast: uint64(time.Now().Unix()),
+		cursor:   config.Cursor,
 		Messages: make(chan *farcasterapi.APIMessage),
 	}
 	// retrieve the bot user data from the API
@@ -67,8 +83,14 @@ func New(config BotConfig) (*Bot, error) {
 // Start function starts the bot, it listens for new casts and sends them to the
 // Messages channel. It does this in a goroutine to avoid blocking the main and
 // every cool down time.
+// If the bot has a cursor store and the API supports it, the mentions are
+// streamed from the API events instead.
 func (b *Bot) Start(ctx context.Context) {
 	b.ctx, b.cancel = context.WithCancel(ctx)
+	if streamer, ok := b.api.(farcasterapi.MentionsStreamer); ok && b.cursor != nil {
+		go b.streamMentions(streamer)
+		return
+	}
 	go func() {
 		ticker := time.NewTicker(b.coolDown)
 		defer ticker.Stop()
@@ -96,6 +118,72 @@ func (b *Bot) Start(ctx context.Context) {
 	}()
 }
 
+// streamMentions function streams the mentions of the bot from the API events,
+// starting from the stored event cursor, and sends them to the Messages
+// channel. The cursor is stored after every mention sent and after every page
+// of events, so the stream can be resumed after a restart. It waits for new
+// events only when the last page is empty, to catch up as fast as possible.
+// It must run in a goroutine.
+func (b *Bot) streamMentions(streamer farcasterapi.MentionsStreamer) {
+	cursor, err := b.cursor.Load()
+	if err != nil {
+		log.Warnw("error loading mentions cursor, streaming from now", "error", err)
+	}
+	ticker := time.NewTicker(defaultStreamInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		default:
+		}
+		mentions, next, err := streamer.MentionEvents(b.ctx, cursor)
+		if err != nil {
+			log.Errorw(err, "error retrieving new mentions")
+			if !b.wait(ticker) {
+				return
+			}
+			continue
+		}
+		for _, mention := range mentions {
+			select {
+			case <-b.ctx.Done():
+				return
+			case b.Messages <- mention.Message:
+			}
+			b.saveCursor(mention.EventID + 1)
+		}
+		if next == cursor {
+			// no new events, wait for the next ones
+			if !b.wait(ticker) {
+				return
+			}
+			continue
+		}
+		cursor = next
+		b.saveCursor(cursor)
+	}
+}
+
+// wait function waits for the next tick of the ticker provided. It returns
+// false if the bot context is done before, so the caller must stop.
+func (b *Bot) wait(ticker *time.Ticker) bool {
+	select {
+	case <-b.ctx.Done():
+		return false
+	case <-ticker.C:
+		return true
+	}
+}
+
+// saveCursor function stores the event cursor provided, logging the error if
+// it fails, since the stream can continue and it will be stored again later.
+func (b *Bot) saveCursor(eventID uint64) {
+	if err := b.cursor.Save(eventID); err != nil {
+		log.Warnw("error saving mentions cursor", "eventID", eventID, "error", err)
+	}
+}
+
 // Stop function stops the bot and its goroutine, and closes the Messages channel.
 func (b *Bot) Stop() {
 	if err := b.api.Stop(); err != nil {
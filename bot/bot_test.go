@@ -0,0 +1,79 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"github.com/vocdoni/vote-frame/farcasterapi/hub"
+	"github.com/vocdoni/vote-frame/farcasterapi/hub/hubtest"
+)
+
+const testBotFID = 1000
+
+type memCursor struct {
+	mtx     sync.Mutex
+	eventID uint64
+}
+
+func (m *memCursor) Load() (uint64, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.eventID, nil
+}
+
+func (m *memCursor) Save(eventID uint64) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.eventID = eventID
+	return nil
+}
+
+func receiveMessage(c *qt.C, b *Bot) *farcasterapi.APIMessage {
+	select {
+	case msg := <-b.Messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		c.Fatal("timeout waiting for message")
+	}
+	return nil
+}
+
+func TestStreamMentions(t *testing.T) {
+	c := qt.New(t)
+
+	fakeHub := hubtest.NewFakeHub(1)
+	defer fakeHub.Close()
+	api, err := hub.NewHubAPI(fakeHub.Endpoint(), nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(api.SetFarcasterUser(testBotFID, "00"), qt.IsNil)
+
+	cursor := &memCursor{eventID: 1}
+	_, firstHash := fakeHub.AddCast(1, " first", testBotFID)
+	fakeHub.AddCast(2, "not a mention")
+	secondID, secondHash := fakeHub.AddCast(3, " second", testBotFID)
+
+	b := &Bot{api: api, cursor: cursor, Messages: make(chan *farcasterapi.APIMessage)}
+	b.Start(context.Background())
+	c.Assert(receiveMessage(c, b).Hash, qt.Equals, firstHash)
+	c.Assert(receiveMessage(c, b).Hash, qt.Equals, secondHash)
+	// wait until the cursor of the last mention is stored
+	for start := time.Now(); ; time.Sleep(10 * time.Millisecond) {
+		if eventID, _ := cursor.Load(); eventID > secondID {
+			break
+		}
+		c.Assert(time.Since(start) < 5*time.Second, qt.IsTrue)
+	}
+	b.cancel()
+
+	// a restarted bot resumes from the stored cursor without duplicating the
+	// mentions already received
+	_, thirdHash := fakeHub.AddCast(4, " third", testBotFID)
+	b = &Bot{api: api, cursor: cursor, Messages: make(chan *farcasterapi.APIMessage)}
+	b.Start(context.Background())
+	defer b.cancel()
+	c.Assert(receiveMessage(c, b).Hash, qt.Equals, thirdHash)
+}
@@ -0,0 +1,238 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"go.vocdoni.io/dvote/log"
+)
+
+// Command represents a command that the bot understands when it is mentioned,
+// it is the first word of the cast.
+type Command string
+
+const (
+	// CmdResults replies with the current results of a poll.
+	CmdResults Command = "results"
+	// CmdClose ends a poll before its end date.
+	CmdClose Command = "close"
+	// CmdRemind sends reminders to the voters that have not voted yet in a
+	// community poll.
+	CmdRemind Command = "remind"
+	// CmdDelegate delegates the vote of the author to another user in a
+	// community.
+	CmdDelegate Command = "delegate"
+	// CmdMute mutes the notifications of the creator of a poll.
+	CmdMute Command = "mute"
+	// CmdUnmute unmutes the notifications of the creator of a poll.
+	CmdUnmute Command = "unmute"
+	// CmdHelp replies with the list of commands supported.
+	CmdHelp Command = "help"
+)
+
+// commandsUsage contains the usage of every command, it is used to compose
+// the help reply and the errors replies.
+var commandsUsage = map[Command]string{
+	CmdResults:  "results <poll>",
+	CmdClose:    "close <poll>",
+	CmdRemind:   "remind <poll>",
+	CmdDelegate: "delegate @user in <community>",
+	CmdMute:     "mute <poll>",
+	CmdUnmute:   "unmute <poll>",
+	CmdHelp:     "help",
+}
+
+// CommandRequest struct contains the information of a command received by the
+// bot. Poll contains the reference to the poll provided (an ID or an URL), it
+// is empty if it is not provided, then the poll should be found in the parent
+// cast embeds. Username and Community are only set for the delegate command.
+type CommandRequest struct {
+	Command   Command
+	Message   *farcasterapi.APIMessage
+	Parent    *farcasterapi.APIMessage
+	Author    *farcasterapi.Userdata
+	Poll      string
+	Username  string
+	Community string
+}
+
+// CommandHandler is a function that handles a command and returns the text of
+// the reply to the command cast and optionally the URLs to embed in it. If it
+// returns a *CommandError, its message is used as reply.
+type CommandHandler func(ctx context.Context, req *CommandRequest) (string, []string, error)
+
+// CommandError is an error that includes a message for the user that sent the
+// command, so it can be replied to them.
+type CommandError struct {
+	Message string
+}
+
+// Error returns the message of the CommandError.
+func (e *CommandError) Error() string {
+	return e.Message
+}
+
+// NewCommandError creates a new CommandError with the message provided
+// formatted with the arguments provided.
+func NewCommandError(format string, args ...any) *CommandError {
+	return &CommandError{Message: fmt.Sprintf(format, args...)}
+}
+
+// ParseCommand parses the content of a cast as a command. The command must be
+// in a single line and start with the name of a command, optionally preceded
+// by the mention of the bot. It returns ErrNotACommand if the content is not a
+// command, and ErrParsingCommand if the command arguments are not valid.
+func ParseCommand(content, botUsername string) (*CommandRequest, error) {
+	content = strings.TrimSpace(content)
+	if strings.Contains(content, "\n") {
+		return nil, ErrNotACommand
+	}
+	words := strings.Fields(content)
+	// discard the mention to the bot if it is included
+	if len(words) > 0 && botUsername != "" && strings.EqualFold(words[0], "@"+botUsername) {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return nil, ErrNotACommand
+	}
+	cmd := Command(strings.ToLower(words[0]))
+	if _, ok := commandsUsage[cmd]; !ok {
+		return nil, ErrNotACommand
+	}
+	args := words[1:]
+	req := &CommandRequest{Command: cmd}
+	switch cmd {
+	case CmdResults, CmdClose, CmdRemind, CmdMute, CmdUnmute:
+		if len(args) > 1 {
+			return nil, errors.Join(ErrParsingCommand, fmt.Errorf("usage: %s", commandsUsage[cmd]))
+		}
+		if len(args) == 1 {
+			req.Poll = args[0]
+		}
+	case CmdDelegate:
+		if len(args) != 3 || !strings.EqualFold(args[1], "in") {
+			return nil, errors.Join(ErrParsingCommand, fmt.Errorf("usage: %s", commandsUsage[cmd]))
+		}
+		req.Username = strings.TrimPrefix(args[0], "@")
+		req.Community = args[2]
+	case CmdHelp:
+		if len(args) > 0 {
+			return nil, errors.Join(ErrParsingCommand, fmt.Errorf("usage: %s", commandsUsage[cmd]))
+		}
+	}
+	return req, nil
+}
+
+// Dispatcher struct routes the commands received by the bot to the handlers
+// registered for them. The help command is handled by the dispatcher itself.
+type Dispatcher struct {
+	handlers map[Command]CommandHandler
+}
+
+// NewDispatcher creates a new Dispatcher without handlers.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[Command]CommandHandler)}
+}
+
+// Register registers the handler provided for the command provided, replacing
+// the previous one if it exists.
+func (d *Dispatcher) Register(cmd Command, handler CommandHandler) {
+	d.handlers[cmd] = handler
+}
+
+// Help returns the text of the help reply, that includes the usage of the
+// commands registered and how to create a poll.
+func (d *Dispatcher) Help() string {
+	cmds := []string{commandsUsage[CmdHelp]}
+	for cmd := range d.handlers {
+		cmds = append(cmds, commandsUsage[cmd])
+	}
+	sort.Strings(cmds)
+	var sb strings.Builder
+	sb.WriteString("🤖 Mention me with one of these commands:\n")
+	for _, cmd := range cmds {
+		sb.WriteString(fmt.Sprintf("- %s\n", cmd))
+	}
+	sb.WriteString("\n<poll> can be a poll URL, or skip it replying to the poll cast.\n")
+	sb.WriteString("To create a poll, mention me with a question followed by the options, one per line starting with '-'.")
+	return sb.String()
+}
+
+// CommandHandler method checks if the message provided is a command, and if it
+// is, it dispatches it to the handler registered and replies to the message
+// with the result, threaded in the conversation. It returns false if the
+// message is not a command. It returns an error if something goes wrong
+// handling the command or replying to the message, the errors for the user are
+// also replied to the message.
+func (b *Bot) CommandHandler(ctx context.Context, msg *farcasterapi.APIMessage, d *Dispatcher) (bool, error) {
+	if msg == nil || !msg.IsMention {
+		return false, nil
+	}
+	botUsername := ""
+	if b.UserData != nil {
+		botUsername = b.UserData.Username
+	}
+	req, err := ParseCommand(msg.Content, botUsername)
+	if err != nil {
+		if errors.Is(err, ErrNotACommand) {
+			return false, nil
+		}
+		return true, b.replyToCommand(ctx, msg, "🤔 "+commandUsageError(err), nil)
+	}
+	req.Message = msg
+	// help command is handled by the dispatcher itself
+	if req.Command == CmdHelp {
+		return true, b.replyToCommand(ctx, msg, d.Help(), nil)
+	}
+	handler, ok := d.handlers[req.Command]
+	if !ok {
+		return false, nil
+	}
+	// get the user data of the author and the parent cast, if any, that
+	// includes the poll frame if no poll is provided
+	if req.Author, err = b.api.UserDataByFID(ctx, msg.Author); err != nil {
+		return true, errors.Join(ErrGettingUserData, err)
+	}
+	if msg.Parent != nil {
+		if req.Parent, err = b.api.GetCast(ctx, msg.Parent.FID, msg.Parent.Hash); err != nil {
+			log.Warnw("parent cast of command not found", "error", errors.Join(ErrGettingParentCast, err), "hash", msg.Hash)
+		}
+	}
+	text, embeds, err := handler(ctx, req)
+	if err != nil {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) {
+			return true, b.replyToCommand(ctx, msg, "🤔 "+cmdErr.Message, nil)
+		}
+		if replyErr := b.replyToCommand(ctx, msg, "😵 Something went wrong, please try again later.", nil); replyErr != nil {
+			log.Warnw("error replying to command", "error", replyErr)
+		}
+		return true, errors.Join(ErrHandlingCommand, err)
+	}
+	return true, b.replyToCommand(ctx, msg, text, embeds)
+}
+
+// replyToCommand replies to the command message provided with the text and
+// the embeds provided.
+func (b *Bot) replyToCommand(ctx context.Context, msg *farcasterapi.APIMessage, text string, embeds []string) error {
+	if _, err := b.api.Reply(ctx, msg, text, nil, embeds...); err != nil {
+		return errors.Join(ErrReplyingToCast, err)
+	}
+	return nil
+}
+
+// commandUsageError returns the message of the last error joined in the error
+// provided, that contains the usage of the command.
+func commandUsageError(err error) string {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		if errs := joined.Unwrap(); len(errs) > 0 {
+			return errs[len(errs)-1].Error()
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestParseCommand(t *testing.T) {
+	c := qt.New(t)
+
+	req, err := ParseCommand("results https://farcaster.vote/app/0x1234", "")
+	c.Assert(err, qt.IsNil)
+	c.Assert(req.Command, qt.Equals, CmdResults)
+	c.Assert(req.Poll, qt.Equals, "https://farcaster.vote/app/0x1234")
+
+	// the bot mention is discarded and the commands are case insensitive
+	req, err = ParseCommand("@vocdoni  MUTE ", "vocdoni")
+	c.Assert(err, qt.IsNil)
+	c.Assert(req.Command, qt.Equals, CmdMute)
+	c.Assert(req.Poll, qt.Equals, "")
+
+	req, err = ParseCommand("delegate @alice in degen:1", "vocdoni")
+	c.Assert(err, qt.IsNil)
+	c.Assert(req.Command, qt.Equals, CmdDelegate)
+	c.Assert(req.Username, qt.Equals, "alice")
+	c.Assert(req.Community, qt.Equals, "degen:1")
+
+	// invalid arguments
+	_, err = ParseCommand("delegate @alice degen:1", "")
+	c.Assert(err, qt.ErrorIs, ErrParsingCommand)
+	c.Assert(commandUsageError(err), qt.Equals, "usage: delegate @user in <community>")
+	_, err = ParseCommand("close poll1 poll2", "")
+	c.Assert(err, qt.ErrorIs, ErrParsingCommand)
+
+	// not commands
+	_, err = ParseCommand("", "")
+	c.Assert(err, qt.ErrorIs, ErrNotACommand)
+	_, err = ParseCommand("what results do you expect?", "")
+	c.Assert(err, qt.ErrorIs, ErrNotACommand)
+	_, err = ParseCommand("results of the week?\n- Good\n- Bad", "")
+	c.Assert(err, qt.ErrorIs, ErrNotACommand)
+}
+
+func TestDispatcherHelp(t *testing.T) {
+	c := qt.New(t)
+	d := NewDispatcher()
+	d.Register(CmdResults, nil)
+	d.Register(CmdMute, nil)
+	help := d.Help()
+	c.Assert(strings.Contains(help, "- results <poll>"), qt.IsTrue)
+	c.Assert(strings.Contains(help, "- mute <poll>"), qt.IsTrue)
+	c.Assert(strings.Contains(help, "- help"), qt.IsTrue)
+	c.Assert(strings.Contains(help, "close"), qt.IsFalse)
+}
@@ -15,6 +15,15 @@ var (
 	// during the reply with poll URL function.
 	ErrReplyingToCast = fmt.Errorf("error replying to cast")
 	// ErrGettingParentCast is returned when there is an error getting the parent
-	// cast during the mute notifications message handler.
+	// cast during the command handler.
 	ErrGettingParentCast = fmt.Errorf("error getting parent cast")
+	// ErrNotACommand is returned when the content of a cast is not a command
+	// supported by the bot.
+	ErrNotACommand = fmt.Errorf("not a command")
+	// ErrParsingCommand is returned when there is an error parsing the
+	// arguments of a command.
+	ErrParsingCommand = fmt.Errorf("error parsing command")
+	// ErrHandlingCommand is returned when there is an error handling a command
+	// during the command handler.
+	ErrHandlingCommand = fmt.Errorf("error handling command")
 )
@@ -21,6 +21,18 @@ Now copy the URL, paste the Frame into a cast and share it with others!
 
 👇`
 
+// PollErrorReplyTemplate is the template for the reply to a cast with a poll
+// that cannot be created because of a mistake of its author. It must be
+// formatted with the reason.
+var PollErrorReplyTemplate = `😕 I could not create your poll: %s`
+
+// QuotaExceededReplyTemplate is the template for the reply to a cast with a
+// poll when its author has exceeded the quota of polls. It must be formatted
+// with the time to wait until a new poll can be created.
+var QuotaExceededReplyTemplate = `⏳ Easy there! You have created too many polls recently, please try again in %s.
+
+The more reputation you have, the more polls you can create 😉`
+
 // PollMessageHandler is a function that handles a new cast and checks if it is a
 // poll, if it is, it parses the poll and returns the user data and the poll. If
 // it is not a poll, it returns false. It returns an error if something goes
@@ -48,35 +60,48 @@ func (b *Bot) PollMessageHandler(ctx context.Context, msg *farcasterapi.APIMessa
 	return userdata, poll, true, nil
 }
 
-func (b *Bot) MuteRequestHandler(ctx context.Context, msg *farcasterapi.APIMessage) (*farcasterapi.Userdata, *farcasterapi.APIMessage, bool, error) {
-	// when a new cast is received, check if it is a mention and if
-	// it is not, continue to the next cast
-	if msg == nil || !msg.IsMention {
-		return nil, nil, false, nil
-	}
-	if msg.Parent == nil {
-		return nil, nil, false, nil
+// ReplyWithPollURL replies to the message provided with the URL of the poll
+// created from it. It returns the hash of the reply cast, so the poll results
+// can be posted in the same thread.
+func (b *Bot) ReplyWithPollURL(ctx context.Context, msg *farcasterapi.APIMessage, pollURL string) (string, error) {
+	hash, err := b.api.Reply(ctx, msg, fmt.Sprintf(PollReplyTemplate, pollURL), nil, pollURL)
+	if err != nil {
+		return "", errors.Join(ErrReplyingToCast, err)
 	}
-	// check if the content of the cast is a mute request, if it is
-	if strings.TrimSpace(msg.Content) != muteRequestContent {
-		return nil, nil, false, nil
+	return hash, nil
+}
+
+// ReplyWithQuotaExceeded replies to the message provided with a friendly
+// message to let the user know that they have created too many polls and how
+// long they have to wait to create a new one.
+func (b *Bot) ReplyWithQuotaExceeded(ctx context.Context, msg *farcasterapi.APIMessage, retryAfter time.Duration) error {
+	// round up to the next minute to avoid asking for retrying too early
+	wait := retryAfter.Truncate(time.Minute)
+	if wait < retryAfter {
+		wait += time.Minute
 	}
-	// get the user data such as username, custody address and verification
-	// addresses of the user that wants to mute the creator of the poll
-	userdata, err := b.api.UserDataByFID(ctx, msg.Author)
-	if err != nil {
-		return nil, nil, true, errors.Join(ErrGettingUserData, err)
+	text := fmt.Sprintf(QuotaExceededReplyTemplate, strings.TrimSuffix(wait.String(), "0s"))
+	if _, err := b.api.Reply(ctx, msg, text, nil); err != nil {
+		return errors.Join(ErrReplyingToCast, err)
 	}
-	// get the parent message to recover the poll
-	parentMsg, err := b.api.GetCast(ctx, msg.Parent.FID, msg.Parent.Hash)
-	if err != nil {
-		return nil, nil, true, errors.Join(ErrGettingParentCast, err)
+	return nil
+}
+
+// PollParseErrorReason returns the reason of the error provided if it was
+// returned by PollMessageHandler because the message is a poll with a mistake
+// of its author, so it can be replied to them.
+func PollParseErrorReason(err error) (string, bool) {
+	var parseErr *poll.ParseError
+	if !errors.As(err, &parseErr) {
+		return "", false
 	}
-	return userdata, parentMsg, true, nil
+	return parseErr.Reason, true
 }
 
-func (b *Bot) ReplyWithPollURL(ctx context.Context, msg *farcasterapi.APIMessage, pollURL string) error {
-	if err := b.api.Reply(ctx, msg, fmt.Sprintf(PollReplyTemplate, pollURL), nil, pollURL); err != nil {
+// ReplyWithPollError replies to the message provided with the reason provided
+// why the poll cannot be created.
+func (b *Bot) ReplyWithPollError(ctx context.Context, msg *farcasterapi.APIMessage, reason string) error {
+	if _, err := b.api.Reply(ctx, msg, fmt.Sprintf(PollErrorReplyTemplate, reason), nil); err != nil {
 		return errors.Join(ErrReplyingToCast, err)
 	}
 	return nil
@@ -10,6 +10,16 @@ var (
 	// question and options are set, and the duration content is set but it
 	// cannot be parsed.
 	ErrParsingDuration = fmt.Errorf("error parsing duration")
+	// ErrUnknownDurationUnit is returned when the duration of the poll includes
+	// a unit that is not supported. It wraps ErrParsingDuration.
+	ErrUnknownDurationUnit = fmt.Errorf("%w: unknown unit", ErrParsingDuration)
+	// ErrDurationOutOfRange is returned when the duration of the poll is
+	// shorter than the minimum duration or longer than the maximum duration. It
+	// wraps ErrParsingDuration.
+	ErrDurationOutOfRange = fmt.Errorf("%w: out of range", ErrParsingDuration)
+	// ErrEndDateInPast is returned when the end date of the poll is already in
+	// the past. It wraps ErrParsingDuration.
+	ErrEndDateInPast = fmt.Errorf("%w: end date in the past", ErrParsingDuration)
 	// ErrMinOptionsNotReached is returned when the poll command is recognised,
 	// the question content is set, and the number of options is less than the
 	// minimum number of options.
@@ -18,4 +28,34 @@ var (
 	// question content is set, and the number of options is greater than the
 	// maximum number of options.
 	ErrMaxOptionsReached = fmt.Errorf("max number of options reached")
+	// ErrUnknownDirective is returned when the poll command is recognised, and
+	// it includes a directive that is not supported.
+	ErrUnknownDirective = fmt.Errorf("unknown directive")
+	// ErrInvalidDirective is returned when the poll command is recognised, and
+	// it includes a supported directive with invalid arguments.
+	ErrInvalidDirective = fmt.Errorf("invalid directive")
 )
+
+// ParseError is returned when the message is clearly a poll but some part of
+// it is wrong. It wraps one of the errors of the package and includes a
+// reason that can be shown to the author of the poll.
+type ParseError struct {
+	Err    error
+	Reason string
+}
+
+// newParseError returns a new ParseError wrapping the error provided, with
+// the reason composed from the format and arguments provided.
+func newParseError(err error, format string, args ...any) *ParseError {
+	return &ParseError{Err: err, Reason: fmt.Sprintf(format, args...)}
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Err, e.Reason)
+}
+
+// Unwrap returns the wrapped error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -8,30 +8,127 @@
 // - <option 3*>
 // - <option 4*>
 // <duration*>
-// The duration is optional and if not set, it takes the default duration. The
-// minimum and maximum number of options are also configurable. The question
-// can be set in multiple lines, but the options must be set in a single line.
+// <directives*>
+// The duration is optional and if not set, it takes the default duration. It
+// can be set as a combination of minutes, hours, days and weeks (e.g. '90m',
+// '3 days' or '1d 12h') or as an absolute end date in UTC (e.g. 'ends
+// 2024-06-01 18:00', 'until 2024-06-01' or 'ends friday'). An end date
+// without time means the end of that day. The minimum and maximum number of
+// options are also configurable. The question can be set in multiple lines,
+// but the options must be set in a single line.
+// The directives are optional lines that start with a double dash and change
+// the behaviour of the poll:
+//   - '--no-results' disables posting the final results into the poll thread.
+//   - '--channel <channel>' creates the census from the followers of the
+//     channel provided.
+//   - '--community <community>' uses the census of the community provided.
+//   - '--census <farcaster|followers>' sets the census type of the poll, all
+//     the Farcaster users or the followers of the author.
+//   - '--notify' notifies the voters of the census about the poll.
+//   - '--max-options <n>' sets the maximum number of options of the poll.
 package poll
 
 import (
 	"bufio"
-	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
 )
 
+// timeNow is the clock used to resolve the absolute end dates of the polls,
+// it can be replaced in tests.
+var timeNow = time.Now
+
+const (
+	optionPrefix    = "-"
+	directivePrefix = "--"
+	linebreak       = "\n"
+	space           = " "
+)
+
 const (
-	optionPrefix = "-"
-	linebreak    = "\n"
-	space        = " "
+	// noResultsDirective is the directive that disables posting the final
+	// results of the poll into the poll thread.
+	noResultsDirective = "--no-results"
+	// channelDirective is the directive that sets the channel whose followers
+	// are the census of the poll.
+	channelDirective = "--channel"
+	// communityDirective is the directive that sets the community whose census
+	// is used for the poll.
+	communityDirective = "--community"
+	// censusDirective is the directive that sets the census type of the poll.
+	censusDirective = "--census"
+	// notifyDirective is the directive that enables the notifications to the
+	// voters of the poll.
+	notifyDirective = "--notify"
+	// maxOptionsDirective is the directive that sets the maximum number of
+	// options of the poll.
+	maxOptionsDirective = "--max-options"
 )
 
-// durationRgx var contains the regular expression to parse the duration from a
-// string message
-var durationRgx = regexp.MustCompile(`^(\d{1,2})\s*[hours|hour|h]+$`)
+const (
+	// CensusFarcaster is the census type that includes all the Farcaster
+	// users.
+	CensusFarcaster = "farcaster"
+	// CensusFollowers is the census type that includes the followers of the
+	// author of the poll.
+	CensusFollowers = "followers"
+)
+
+var (
+	// durationRgx var contains the regular expression to parse the duration
+	// from a string message, as a list of amounts and units, optionally
+	// separated by commas or 'and'
+	durationRgx = regexp.MustCompile(`^(?:\d+\s*[a-z]+(?:\s*,\s*|\s+and\s+|\s*))+$`)
+	// durationPartRgx var contains the regular expression to extract every
+	// amount and unit from a duration
+	durationPartRgx = regexp.MustCompile(`(\d+)\s*([a-z]+)`)
+	// endDateRgx var contains the regular expression to parse an absolute end
+	// date from a string message
+	endDateRgx = regexp.MustCompile(`^(?:ends?|until)\s+(?:on\s+)?(.+?)(?:\s+utc)?$`)
+	// numberRgx var contains the regular expression to detect a duration
+	// without units
+	numberRgx = regexp.MustCompile(`^\d+$`)
+)
+
+// durationUnits var contains the supported duration units by name
+var durationUnits = map[string]time.Duration{
+	"m":       time.Minute,
+	"min":     time.Minute,
+	"mins":    time.Minute,
+	"minute":  time.Minute,
+	"minutes": time.Minute,
+	"h":       time.Hour,
+	"hr":      time.Hour,
+	"hrs":     time.Hour,
+	"hour":    time.Hour,
+	"hours":   time.Hour,
+	"d":       24 * time.Hour,
+	"day":     24 * time.Hour,
+	"days":    24 * time.Hour,
+	"w":       7 * 24 * time.Hour,
+	"wk":      7 * 24 * time.Hour,
+	"wks":     7 * 24 * time.Hour,
+	"week":    7 * 24 * time.Hour,
+	"weeks":   7 * 24 * time.Hour,
+}
+
+// endDateLayouts var contains the supported layouts of the absolute end dates
+var endDateLayouts = []string{"2006-01-02 15:04", "2006-01-02t15:04", time.DateOnly}
+
+// weekdays var contains the supported weekday names of the absolute end dates
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
 
 // DefaultConfig var contains the default configuration for a poll with a
 // minimum of 2 options, a maximum of 4 options, a minimum duration of 1 hour,
@@ -54,11 +151,23 @@ type PollConfig struct {
 	DefaultDuration time.Duration
 }
 
-// Poll represents a poll with a question, options and duration
+// Poll represents a poll with a question, options and duration. SkipResults
+// is true if the final results of the poll must not be posted into the poll
+// thread. Channel and Community contain the channel or the community whose
+// census must be used for the poll, if any. Census contains the census type
+// set with the census directive, if any. Notify is true if the voters must be
+// notified about the poll. MaxOptions contains the maximum number of options
+// set with the max options directive, if any.
 type Poll struct {
-	Question string
-	Options  []string
-	Duration time.Duration
+	Question    string
+	Options     []string
+	Duration    time.Duration
+	SkipResults bool
+	Channel     string
+	Community   string
+	Census      string
+	Notify      bool
+	MaxOptions  int
 }
 
 // ParseString parses a string message and returns a Poll struct with the
@@ -76,6 +185,9 @@ func ParseString(message string, config *PollConfig) (*Poll, error) {
 	var question string
 	var options []string
 	var duration time.Duration = config.DefaultDuration
+	var durationSet, skipResults, notify bool
+	var channel, community, census string
+	var maxOptions int
 	// poll message follows the format:
 	// <question>
 	// - <option 1>
@@ -107,6 +219,38 @@ func ParseString(message string, config *PollConfig) (*Poll, error) {
 		//  - the question has been set
 		//  - at least the min number of options has been set
 		//  - by default, the duration is 24 hours
+		// line is a <directive> if it starts with a double dash and the
+		// question has been set
+		if question != "" && strings.HasPrefix(line, directivePrefix) {
+			directive, arg, err := parseDirective(line)
+			if err != nil {
+				return nil, err
+			}
+			switch directive {
+			case noResultsDirective:
+				skipResults = true
+			case channelDirective:
+				channel = arg
+			case communityDirective:
+				community = arg
+			case censusDirective:
+				census = arg
+			case notifyDirective:
+				notify = true
+			case maxOptionsDirective:
+				n, err := strconv.Atoi(arg)
+				if err != nil || n < config.MinOptions || n > config.MaxOptions {
+					return nil, newParseError(ErrInvalidDirective, "%s must be a number between %d and %d",
+						maxOptionsDirective, config.MinOptions, config.MaxOptions)
+				}
+				maxOptions = n
+			}
+			continue
+		}
+		// once the duration is set, only directives are parsed
+		if durationSet {
+			continue
+		}
 		startWithDash := strings.HasPrefix(line, optionPrefix)
 		numOfQuestions := len(options)
 		if !startWithDash {
@@ -119,12 +263,14 @@ func ParseString(message string, config *PollConfig) (*Poll, error) {
 			// an error, otherwise, break the loop and return the result
 			var err error
 			if duration, err = parseDuration(line); err != nil {
-				return nil, errors.Join(ErrParsingDuration, err)
+				return nil, err
 			}
 			if duration < config.MinDuration || duration > config.MaxDuration {
-				return nil, fmt.Errorf("duration out of range: %w", ErrParsingDuration)
+				return nil, newParseError(ErrDurationOutOfRange, "the poll must last between %s and %s",
+					formatDuration(config.MinDuration), formatDuration(config.MaxDuration))
 			}
-			break
+			durationSet = true
+			continue
 		}
 		// if the line is an option and the number of options is greater than
 		// the max number of options, return an error
@@ -142,28 +288,134 @@ func ParseString(message string, config *PollConfig) (*Poll, error) {
 	if len(options) < config.MinOptions {
 		return nil, fmt.Errorf("%w: %d", ErrMinOptionsNotReached, config.MinOptions)
 	}
+	if maxOptions > 0 && len(options) > maxOptions {
+		return nil, newParseError(ErrMaxOptionsReached, "the poll has %d options but %s is %d",
+			len(options), maxOptionsDirective, maxOptions)
+	}
+	if census != "" && (channel != "" || community != "") {
+		return nil, newParseError(ErrInvalidDirective, "%s cannot be combined with %s or %s",
+			censusDirective, channelDirective, communityDirective)
+	}
 	// return the results
 	return &Poll{
-		Question: strings.TrimSpace(strings.ReplaceAll(question, linebreak, space)),
-		Options:  options,
-		Duration: duration,
+		Question:    strings.TrimSpace(strings.ReplaceAll(question, linebreak, space)),
+		Options:     options,
+		Duration:    duration,
+		SkipResults: skipResults,
+		Channel:     channel,
+		Community:   community,
+		Census:      census,
+		Notify:      notify,
+		MaxOptions:  maxOptions,
 	}, nil
 }
 
-// parseDuration parses a string and returns a time.Duration. The string should
-// follow the format: <hours> (hours|hour|h). If the string does not follow the
-// format, an error is returned.
+// parseDirective parses a directive line and returns the directive and its
+// argument, if any. It returns an error if the directive is not supported or
+// its argument is not valid.
+func parseDirective(line string) (string, string, error) {
+	fields := strings.Fields(line)
+	directive := strings.ToLower(fields[0])
+	switch directive {
+	case noResultsDirective, notifyDirective:
+		if len(fields) != 1 {
+			return "", "", newParseError(ErrInvalidDirective, "%s does not accept arguments", directive)
+		}
+		return directive, "", nil
+	case channelDirective, communityDirective:
+		if len(fields) != 2 {
+			return "", "", newParseError(ErrInvalidDirective, "%s requires one argument", directive)
+		}
+		// the channel can be provided with the leading slash
+		return directive, strings.TrimPrefix(fields[1], "/"), nil
+	case censusDirective:
+		if len(fields) != 2 {
+			return "", "", newParseError(ErrInvalidDirective, "%s requires one argument", directive)
+		}
+		census := strings.ToLower(fields[1])
+		if census != CensusFarcaster && census != CensusFollowers {
+			return "", "", newParseError(ErrInvalidDirective, "%s must be %s or %s",
+				directive, CensusFarcaster, CensusFollowers)
+		}
+		return directive, census, nil
+	case maxOptionsDirective:
+		if len(fields) != 2 {
+			return "", "", newParseError(ErrInvalidDirective, "%s requires one argument", directive)
+		}
+		return directive, fields[1], nil
+	default:
+		return "", "", newParseError(ErrUnknownDirective, "unknown directive %s", directive)
+	}
+}
+
+// parseDuration parses a string and returns a time.Duration. The string
+// should be a combination of amounts of minutes, hours, days and weeks
+// (e.g. '1d 12h') or an absolute end date in UTC (e.g. 'ends 2024-06-01
+// 18:00'). If the string does not follow any format, an error is returned.
 func parseDuration(line string) (time.Duration, error) {
+	line = strings.ToLower(strings.TrimSpace(line))
+	if endDateRgx.MatchString(line) {
+		return parseEndDate(endDateRgx.FindStringSubmatch(line)[1])
+	}
+	if numberRgx.MatchString(line) {
+		return 0, newParseError(ErrParsingDuration, "the duration %s has no unit, use minutes, hours, days or weeks", line)
+	}
 	if !durationRgx.MatchString(line) {
-		return 0, errors.Join(ErrParsingDuration, errors.New("invalid duration format"))
+		return 0, newParseError(ErrParsingDuration, "invalid duration %s, use minutes, hours, days or weeks (e.g. '1d 12h')", line)
+	}
+	var duration time.Duration
+	for _, part := range durationPartRgx.FindAllStringSubmatch(line, -1) {
+		unit, ok := durationUnits[part[2]]
+		if !ok {
+			return 0, newParseError(ErrUnknownDurationUnit, "unknown duration unit %q, use minutes, hours, days or weeks", part[2])
+		}
+		// the amount is bounded to avoid overflowing the total duration
+		amount, err := strconv.ParseInt(part[1], 10, 64)
+		if err != nil || time.Duration(amount) > (math.MaxInt64-duration)/unit {
+			return 0, newParseError(ErrParsingDuration, "the duration %s is too long", line)
+		}
+		duration += time.Duration(amount) * unit
 	}
-	hours := durationRgx.FindStringSubmatch(line)[1]
-	if hours == "" {
-		return 0, errors.Join(ErrParsingDuration, errors.New("invalid duration format"))
+	return duration, nil
+}
+
+// parseEndDate parses an absolute end date in UTC and returns the duration
+// from now until it. The date can include the time or be a weekday name, in
+// other case it means the end of that day. If the date cannot be parsed or it
+// is in the past, an error is returned.
+func parseEndDate(date string) (time.Duration, error) {
+	now := timeNow().UTC()
+	if weekday, ok := weekdays[date]; ok {
+		days := (int(weekday) - int(now.Weekday()) + 7) % 7
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		return today.AddDate(0, 0, days+1).Sub(now), nil
 	}
-	iHours, err := strconv.Atoi(hours)
-	if err != nil {
-		return 0, errors.Join(ErrParsingDuration, err)
+	for _, layout := range endDateLayouts {
+		end, err := time.Parse(layout, date)
+		if err != nil {
+			continue
+		}
+		// a date without time means the end of that day
+		if layout == time.DateOnly {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !end.After(now) {
+			return 0, newParseError(ErrEndDateInPast, "the end date %s is in the past", date)
+		}
+		return end.Sub(now), nil
+	}
+	return 0, newParseError(ErrParsingDuration, "invalid end date %s, use YYYY-MM-DD HH:MM in UTC or a weekday", date)
+}
+
+// formatDuration returns a short human readable representation of the
+// duration provided, in days, hours or minutes.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
 	}
-	return time.Hour * time.Duration(iHours), nil
 }
@@ -1,6 +1,7 @@
 package poll
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -66,6 +67,46 @@ Just as frames allowed embeds by implementing a limited, safe scope, there's an
 -Red
 -Blue
 1 hour`
+
+	noResultsMessage = `What is your favourite colour?
+- Red
+- Blue
+12h
+--no-results`
+	unknownDirectiveMessage = `What is your favourite colour?
+- Red
+- Blue
+--unknown`
+	censusDirectivesMessage = `What is your favourite colour?
+- Red
+- Blue
+--channel /vocdoni
+--community degen:1`
+	invalidDirectiveMessage = `What is your favourite colour?
+- Red
+- Blue
+--channel`
+	optionDirectivesMessage = `What is your favourite colour?
+- Red
+- Blue
+1d 12h
+--census followers
+--notify
+--max-options 2`
+	conflictingCensusMessage = `What is your favourite colour?
+- Red
+- Blue
+--census farcaster
+--channel vocdoni`
+	maxOptionsDirectiveMessage = `What is your favourite colour?
+- Red
+- Blue
+- Green
+--max-options 2`
+	durationOutOfRangeMessage = `What is your favourite colour?
+- Red
+- Blue
+3 weeks`
 )
 
 var (
@@ -162,4 +203,88 @@ func TestParseString(t *testing.T) {
 
 	_, err = ParseString(randomMessage, DefaultConfig)
 	c.Assert(err, qt.ErrorIs, ErrMinOptionsNotReached)
+
+	noResultsPoll, err := ParseString(noResultsMessage, DefaultConfig)
+	c.Assert(err, qt.IsNil)
+	c.Assert(noResultsPoll.Options, qt.ContentEquals, []string{"Red", "Blue"})
+	c.Assert(noResultsPoll.Duration, qt.Equals, time.Hour*12)
+	c.Assert(noResultsPoll.SkipResults, qt.IsTrue)
+	c.Assert(correctPoll.SkipResults, qt.IsFalse)
+
+	_, err = ParseString(unknownDirectiveMessage, DefaultConfig)
+	c.Assert(err, qt.ErrorIs, ErrUnknownDirective)
+
+	censusDirectivesPoll, err := ParseString(censusDirectivesMessage, DefaultConfig)
+	c.Assert(err, qt.IsNil)
+	c.Assert(censusDirectivesPoll.Channel, qt.Equals, "vocdoni")
+	c.Assert(censusDirectivesPoll.Community, qt.Equals, "degen:1")
+
+	_, err = ParseString(invalidDirectiveMessage, DefaultConfig)
+	c.Assert(err, qt.ErrorIs, ErrInvalidDirective)
+
+	optionDirectivesPoll, err := ParseString(optionDirectivesMessage, DefaultConfig)
+	c.Assert(err, qt.IsNil)
+	c.Assert(optionDirectivesPoll.Duration, qt.Equals, time.Hour*36)
+	c.Assert(optionDirectivesPoll.Census, qt.Equals, CensusFollowers)
+	c.Assert(optionDirectivesPoll.Notify, qt.IsTrue)
+	c.Assert(optionDirectivesPoll.MaxOptions, qt.Equals, 2)
+
+	_, err = ParseString(conflictingCensusMessage, DefaultConfig)
+	c.Assert(err, qt.ErrorIs, ErrInvalidDirective)
+
+	_, err = ParseString(maxOptionsDirectiveMessage, DefaultConfig)
+	c.Assert(err, qt.ErrorIs, ErrMaxOptionsReached)
+
+	_, err = ParseString(durationOutOfRangeMessage, DefaultConfig)
+	c.Assert(err, qt.ErrorIs, ErrDurationOutOfRange)
+	c.Assert(err, qt.ErrorIs, ErrParsingDuration)
+	var parseErr *ParseError
+	c.Assert(errors.As(err, &parseErr), qt.IsTrue)
+	c.Assert(parseErr.Reason, qt.Equals, "the poll must last between 1h and 15d")
+}
+
+func TestParseDuration(t *testing.T) {
+	c := qt.New(t)
+
+	// Thursday
+	now := time.Date(2024, 5, 30, 10, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	for line, expected := range map[string]time.Duration{
+		"90m":                        90 * time.Minute,
+		"3d":                         72 * time.Hour,
+		"1 week":                     7 * 24 * time.Hour,
+		"1d 12h":                     36 * time.Hour,
+		"1 day and 2 Hours":          26 * time.Hour,
+		"2h, 30min":                  150 * time.Minute,
+		"ends 2024-05-31 18:00":      32 * time.Hour,
+		"until 2024-05-31T18:00 UTC": 32 * time.Hour,
+		"ends on 2024-05-31":         38 * time.Hour,
+		"ends Friday":                38 * time.Hour,
+		"ends thu":                   14 * time.Hour,
+	} {
+		duration, err := parseDuration(line)
+		c.Assert(err, qt.IsNil, qt.Commentf("line: %s", line))
+		c.Assert(duration, qt.Equals, expected, qt.Commentf("line: %s", line))
+	}
+
+	_, err := parseDuration("3 fortnights")
+	c.Assert(err, qt.ErrorIs, ErrUnknownDurationUnit)
+	_, err = parseDuration("ends 2024-05-01")
+	c.Assert(err, qt.ErrorIs, ErrEndDateInPast)
+	_, err = parseDuration("ends tomorrow")
+	c.Assert(err, qt.ErrorIs, ErrParsingDuration)
+	_, err = parseDuration("24")
+	c.Assert(err, qt.ErrorIs, ErrParsingDuration)
+	_, err = parseDuration("whenever")
+	c.Assert(err, qt.ErrorIs, ErrParsingDuration)
+	var parseErr *ParseError
+	c.Assert(errors.As(err, &parseErr), qt.IsTrue)
+	// huge amounts are rejected instead of overflowing
+	for _, line := range []string{"99999999999999999999d", "9223372036854775807w", "2562047h 2562047h"} {
+		_, err = parseDuration(line)
+		c.Assert(err, qt.ErrorIs, ErrParsingDuration, qt.Commentf("line: %s", line))
+		c.Assert(errors.As(err, &parseErr), qt.IsTrue, qt.Commentf("line: %s", line))
+	}
 }
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vocdoni/vote-frame/farcasterapi"
+)
+
+// ResultsReplyTemplate is the template for the reply to a poll cast with its
+// final results. It must be formatted with the poll question and the results
+// lines.
+var ResultsReplyTemplate = `🏁 The poll has ended! "%s"
+
+%s`
+
+// maxResultsQuestionLength is the maximum number of characters of the poll
+// question included in the results reply.
+const maxResultsQuestionLength = 100
+
+// ResultsSummary composes the text of the final results reply of a poll with
+// the question, choices and votes provided. The question and the choices are
+// truncated if the text is longer than the maximum cast size.
+func ResultsSummary(question string, choices, votes []string) string {
+	lines := make([]string, 0, len(choices))
+	for i, choice := range choices {
+		count := "0"
+		if i < len(votes) {
+			count = votes[i]
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", choice, count))
+	}
+	text := fmt.Sprintf(ResultsReplyTemplate, truncate(question, maxResultsQuestionLength),
+		strings.Join(lines, "\n"))
+	// if the text is still too long, truncate the choices
+	for maxChoice := 40; len([]byte(text)) > farcasterapi.MaxCastBytes && maxChoice > 0; maxChoice -= 10 {
+		for i, choice := range choices {
+			count := "0"
+			if i < len(votes) {
+				count = votes[i]
+			}
+			lines[i] = fmt.Sprintf("- %s: %s", truncate(choice, maxChoice), count)
+		}
+		text = fmt.Sprintf(ResultsReplyTemplate, truncate(question, maxResultsQuestionLength),
+			strings.Join(lines, "\n"))
+	}
+	return text
+}
+
+// ReplyWithResults replies to the poll cast of the author and hash provided
+// with the results summary provided, embedding the URL of the final results
+// image.
+func (b *Bot) ReplyWithResults(ctx context.Context, authorFID uint64, hash, summary, imageURL string) error {
+	target := &farcasterapi.APIMessage{Author: authorFID, Hash: hash}
+	if _, err := b.api.Reply(ctx, target, summary, nil, imageURL); err != nil {
+		return errors.Join(ErrReplyingToCast, err)
+	}
+	return nil
+}
+
+// truncate returns the text provided truncated to the number of characters
+// provided, adding an ellipsis if it is truncated.
+func truncate(text string, maxChars int) string {
+	runes := []rune(text)
+	if len(runes) <= maxChars {
+		return text
+	}
+	return string(runes[:maxChars-1]) + "…"
+}
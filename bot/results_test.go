@@ -0,0 +1,26 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/farcasterapi"
+)
+
+func TestResultsSummary(t *testing.T) {
+	c := qt.New(t)
+
+	summary := ResultsSummary("What is your favourite colour?", []string{"Red", "Blue"}, []string{"10", "3"})
+	c.Assert(summary, qt.Equals, "🏁 The poll has ended! \"What is your favourite colour?\"\n\n- Red: 10\n- Blue: 3")
+
+	// missing votes are zero
+	summary = ResultsSummary("Question", []string{"Red", "Blue"}, []string{"1"})
+	c.Assert(strings.HasSuffix(summary, "- Blue: 0"), qt.IsTrue)
+
+	// long questions and choices are truncated to fit in a cast
+	long := strings.Repeat("a", 200)
+	summary = ResultsSummary(long, []string{long, long, long, long}, []string{"1", "2", "3", "4"})
+	c.Assert(len([]byte(summary)) <= farcasterapi.MaxCastBytes, qt.IsTrue)
+	c.Assert(strings.Contains(summary, "…"), qt.IsTrue)
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vocdoni/vote-frame/bot"
+	"github.com/vocdoni/vote-frame/mongo"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+// botReminderTemplate is the template of the reminders sent from the bot
+// remind command. It must be formatted with the poll question and URL.
+const botReminderTemplate = "👋 Hey! You have not voted yet in the poll \"%s\", there is still time to do it: %s"
+
+// botCommands returns a dispatcher with the handlers of the bot commands
+// registered. The handlers reuse the authorization checks of the API
+// handlers, such as the community admin status and the reputation features.
+func botCommands(handler *vocdoniHandler) *bot.Dispatcher {
+	d := bot.NewDispatcher()
+	d.Register(bot.CmdResults, handler.botResultsCommand)
+	d.Register(bot.CmdClose, handler.botCloseCommand)
+	d.Register(bot.CmdRemind, handler.botRemindCommand)
+	d.Register(bot.CmdDelegate, handler.botDelegateCommand)
+	d.Register(bot.CmdMute, handler.botMuteCommand)
+	d.Register(bot.CmdUnmute, handler.botUnmuteCommand)
+	return d
+}
+
+// botResultsCommand replies with the current results of the poll of the
+// command, including the poll frame to vote.
+func (v *vocdoniHandler) botResultsCommand(_ context.Context, req *bot.CommandRequest) (string, []string, error) {
+	electionID, election, err := v.electionFromCommand(req)
+	if err != nil {
+		return "", nil, err
+	}
+	results, err := v.db.Results(electionID)
+	if err != nil || results == nil || !results.Finalized {
+		if results, err = v.updateAndFetchResultsFromDatabase(electionID, nil); err != nil {
+			return "", nil, err
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📊 %s\n", election.Question))
+	for i, choice := range results.Choices {
+		votes := "0"
+		if i < len(results.Votes) {
+			votes = results.Votes[i]
+		}
+		sb.WriteString(fmt.Sprintf("- %s: %s\n", choice, votes))
+	}
+	if results.Finalized {
+		sb.WriteString("\nThese are the final results.")
+	} else {
+		sb.WriteString(fmt.Sprintf("\n%d votes so far, the poll is still open!", election.CastedVotes))
+	}
+	return sb.String(), []string{electionFrameURL(electionID)}, nil
+}
+
+// botCloseCommand ends the poll of the command before its end date. Only the
+// creator of the poll, the admins of its community and the service admin can
+// close it.
+func (v *vocdoniHandler) botCloseCommand(_ context.Context, req *bot.CommandRequest) (string, []string, error) {
+	electionID, election, err := v.electionFromCommand(req)
+	if err != nil {
+		return "", nil, err
+	}
+	isCommunityAdmin := election.Community != nil && v.db.IsCommunityAdmin(req.Author.FID, election.Community.ID)
+	if election.UserID != req.Author.FID && !isCommunityAdmin && req.Author.FID != v.adminFID {
+		return "", nil, bot.NewCommandError("only the creator of the poll or the community admins can close it")
+	}
+	if !election.EndTime.IsZero() && election.EndTime.Before(time.Now()) {
+		return "", nil, bot.NewCommandError("the poll is already closed")
+	}
+	if _, err := v.cli.SetElectionStatus(electionID, "ENDED"); err != nil {
+		return "", nil, fmt.Errorf("failed to end election: %w", err)
+	}
+	// update the end time, so the poll is seen as closed and its final
+	// results are posted without waiting for the original end date
+	if err := v.db.SetElectionEndTime(electionID, time.Now()); err != nil {
+		log.Warnw("failed to update election end time", "electionID", electionID.String(), "error", err)
+	}
+	log.Infow("election closed from bot", "electionID", electionID.String(), "fid", req.Author.FID)
+	return "🔒 The poll is closed, the final results will be ready in a few minutes.",
+		[]string{electionFrameURL(electionID)}, nil
+}
+
+// botRemindCommand sends reminders to every voter that has not voted yet in
+// the community poll of the command. It requires the same permissions as the
+// API reminders.
+func (v *vocdoniHandler) botRemindCommand(_ context.Context, req *bot.CommandRequest) (string, []string, error) {
+	electionID, election, err := v.electionFromCommand(req)
+	if err != nil {
+		return "", nil, err
+	}
+	accessProfile, err := v.remindersAllowed(req.Author.FID, election)
+	if err != nil {
+		switch {
+		case errors.Is(err, errNoWarpcastAPIKey):
+			return "", nil, bot.NewCommandError("configure your Warpcast API key in the app to send reminders")
+		case errors.Is(err, errNotCommunityElection), errors.Is(err, errNotCommunityAdmin),
+			errors.Is(err, errRemindersReputation):
+			return "", nil, bot.NewCommandError("%s", err.Error())
+		}
+		return "", nil, err
+	}
+	remindableUsers, alreadySent, err := v.db.RemindersOfElection(electionID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get voters of election: %w", err)
+	}
+	if len(remindableUsers) == 0 {
+		return "", nil, bot.NewCommandError("there are no voters to remind")
+	}
+	if err := v.remindersLimitAllowed(req.Author.FID, remindableUsers, alreadySent); err != nil {
+		return "", nil, bot.NewCommandError("%s", err.Error())
+	}
+	content := fmt.Sprintf(botReminderTemplate, election.Question, electionFrameURL(electionID))
+	if _, err := v.queueReminders(req.Author.FID, accessProfile.WarpcastAPIKey, electionID,
+		remindableUsers, remindableUsers, content); err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("📨 Sending reminders to %d voters!", len(remindableUsers)), nil, nil
+}
+
+// botDelegateCommand delegates the vote of the author of the command to the
+// user provided in the community provided, with the same checks of the API
+// delegations.
+func (v *vocdoniHandler) botDelegateCommand(_ context.Context, req *bot.CommandRequest) (string, []string, error) {
+	delegate, err := v.db.UserByUsername(req.Username)
+	if err != nil {
+		if errors.Is(err, mongo.ErrUserUnknown) {
+			return "", nil, bot.NewCommandError("user @%s not found", req.Username)
+		}
+		return "", nil, err
+	}
+	if err := v.delegateVote(mongo.Delegation{
+		From:       req.Author.FID,
+		To:         delegate.UserID,
+		CommuniyID: req.Community,
+	}); err != nil {
+		if isInvalidDelegation(err) {
+			return "", nil, bot.NewCommandError("%s", err.Error())
+		}
+		return "", nil, err
+	}
+	return fmt.Sprintf("🤝 Done! @%s will vote for you in the community %s polls.", delegate.Username, req.Community), nil, nil
+}
+
+// botMuteCommand mutes the notifications of the creator of the poll of the
+// command for the author of the command.
+func (v *vocdoniHandler) botMuteCommand(_ context.Context, req *bot.CommandRequest) (string, []string, error) {
+	_, election, err := v.electionFromCommand(req)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := v.db.AddNotificationMutedUser(req.Author.FID, election.UserID); err != nil {
+		return "", nil, fmt.Errorf("error muting user: %w", err)
+	}
+	return fmt.Sprintf("🔕 You will not receive more notifications from the polls of %s.",
+		v.usernameOrFID(election.UserID)), nil, nil
+}
+
+// botUnmuteCommand unmutes the notifications of the creator of the poll of the
+// command for the author of the command.
+func (v *vocdoniHandler) botUnmuteCommand(_ context.Context, req *bot.CommandRequest) (string, []string, error) {
+	_, election, err := v.electionFromCommand(req)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := v.db.DelNotificationMutedUser(req.Author.FID, election.UserID); err != nil {
+		return "", nil, fmt.Errorf("error unmuting user: %w", err)
+	}
+	return fmt.Sprintf("🔔 You will receive notifications from the polls of %s again.",
+		v.usernameOrFID(election.UserID)), nil, nil
+}
+
+// electionFromCommand returns the election referenced in the command
+// provided. The poll can be referenced by its ID or URL in the command, or by
+// the frame embedded in the parent cast if the command is a reply. If the
+// election is not found, it returns a bot.CommandError.
+func (v *vocdoniHandler) electionFromCommand(req *bot.CommandRequest) (types.HexBytes, *mongo.Election, error) {
+	ref := req.Poll
+	if ref == "" && req.Parent != nil {
+		ref = electionIDFromEmbeds(req.Parent.Embeds)
+	}
+	if ref == "" {
+		return nil, nil, bot.NewCommandError("include the poll URL or reply to the poll cast")
+	}
+	// get the last part of the URL if the reference is an URL
+	ref = strings.TrimSuffix(ref, "/")
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	electionID, err := hex.DecodeString(strings.TrimPrefix(ref, "0x"))
+	if err != nil || len(electionID) == 0 {
+		return nil, nil, bot.NewCommandError("invalid poll %s", req.Poll)
+	}
+	election, err := v.db.Election(electionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrElectionUnknown) {
+			return nil, nil, bot.NewCommandError("poll not found")
+		}
+		return nil, nil, fmt.Errorf("error getting election from the database: %w", err)
+	}
+	return electionID, election, nil
+}
+
+// electionIDFromEmbeds returns the election ID of the first election frame
+// found in the embeds provided, or an empty string if there is none.
+func electionIDFromEmbeds(embeds []string) string {
+	for _, embed := range embeds {
+		if strings.HasPrefix(embed, serverURL) {
+			// get the election ID from the embed URL removing the server URL
+			// prefix, including the slash separator
+			return strings.TrimPrefix(embed, serverURL+"/")
+		}
+	}
+	return ""
+}
+
+// electionFrameURL returns the URL of the frame of the election provided.
+func electionFrameURL(electionID types.HexBytes) string {
+	return fmt.Sprintf("%s/%s", serverURL, electionID.String())
+}
+
+// usernameOrFID returns the username of the user with the FID provided
+// prefixed with @, or the FID if the user is unknown.
+func (v *vocdoniHandler) usernameOrFID(fid uint64) string {
+	user, err := v.db.User(fid)
+	if err != nil || user.Username == "" {
+		return fmt.Sprintf("FID %d", fid)
+	}
+	return "@" + user.Username
+}
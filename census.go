@@ -21,8 +21,10 @@ import (
 	"github.com/vocdoni/vote-frame/alfafrens"
 	"github.com/vocdoni/vote-frame/farcasterapi"
 	"github.com/vocdoni/vote-frame/farcasterapi/neynar"
+	"github.com/vocdoni/vote-frame/features"
 	"github.com/vocdoni/vote-frame/helpers"
 	"github.com/vocdoni/vote-frame/mongo"
+	"github.com/vocdoni/vote-frame/quota"
 	"go.vocdoni.io/dvote/api"
 	"go.vocdoni.io/dvote/apiclient"
 	"go.vocdoni.io/dvote/httprouter"
@@ -246,15 +248,26 @@ func (p UniqueParticipants) Add(username string, weight *big.Int, participation
 // censusCSV creates a new census from a CSV file containing Ethereum addresses and weights.
 // It builds the census async and returns the census ID.
 func (v *vocdoniHandler) censusCSV(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
-	censusID, err := v.cli.NewCensus(api.CensusTypeWeighted)
+	userFID, err := v.db.UserFromAuthToken(msg.AuthToken)
 	if err != nil {
+		return fmt.Errorf("cannot get user from auth token: %w", err)
+	}
+	// check if the user has enough reputation to create large censuses, the
+	// errors parsing the CSV are reported by the background process
+	if records, err := ParseCSV(msg.Data); err == nil && len(records) > features.LargeCSVCensusMinAddresses {
+		if !v.featureAllowed(features.LARGE_CSV_CENSUS, "", userFID) {
+			return ctx.Send([]byte("user does not have enough reputation to create large csv censuses"), http.StatusForbidden)
+		}
+	}
+	// check if the user has exceeded the quota of censuses
+	if exceeded, err := v.checkQuota(ctx, userFID, quota.CensusCreation); exceeded || err != nil {
 		return err
 	}
-	v.backgroundQueue.Store(censusID.String(), CensusInfo{})
-	userFID, err := v.db.UserFromAuthToken(msg.AuthToken)
+	censusID, err := v.cli.NewCensus(api.CensusTypeWeighted)
 	if err != nil {
-		return fmt.Errorf("cannot get user from auth token: %w", err)
+		return err
 	}
+	v.backgroundQueue.Store(censusID.String(), CensusInfo{})
 	if err := v.db.AddCensus(censusID, userFID); err != nil {
 		return fmt.Errorf("cannot add census to database: %w", err)
 	}
@@ -371,6 +384,10 @@ func (v *vocdoniHandler) censusChannel(msg *apirest.APIdata, ctx *httprouter.HTT
 	if !exists {
 		return ctx.Send([]byte("channel not found"), http.StatusNotFound)
 	}
+	// check if the user has exceeded the quota of censuses
+	if exceeded, err := v.checkQuota(ctx, userFID, quota.CensusCreation); exceeded || err != nil {
+		return err
+	}
 	// create a censusID for the queue and store into it
 	data, err := v.censusWarpcastChannel(channelID, userFID, nil)
 	if err != nil {
@@ -469,44 +486,89 @@ func (v *vocdoniHandler) censusCommunity(msg *apirest.APIdata, ctx *httprouter.H
 	if !ready {
 		return ctx.Send([]byte("community not ready"), http.StatusPreconditionFailed)
 	}
+	data, err := v.communityCensus(community, userFID)
+	if err != nil {
+		if errors.Is(err, errInvalidCommunityCensusType) {
+			return ctx.Send([]byte(err.Error()), http.StatusBadRequest)
+		}
+		log.Warnf("error creating census for the community %s: %v", community.ID, err)
+		return ctx.Send([]byte("error creating community census"), http.StatusInternalServerError)
+	}
+	return ctx.Send(data, http.StatusOK)
+}
+
+// errInvalidCommunityCensusType is returned when the census type of a
+// community is not supported.
+var errInvalidCommunityCensusType = fmt.Errorf("invalid census type")
+
+// communityCensus creates a new census for the community provided by the
+// user provided, taking into account the delegations of the community. It
+// checks the census type to create it from the correct source (channel,
+// census3 (nft/erc20) or user followers). The process is async and returns
+// the json encoded censusID.
+func (v *vocdoniHandler) communityCensus(community *mongo.Community, userFID uint64) ([]byte, error) {
 	// getting the delegations of the community to build the census taking into
 	// account them
-	delegations, err := v.db.DelegationsByCommunity(req.CommunityID, true, false)
+	delegations, err := v.db.DelegationsByCommunity(community.ID, true, false)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// check the type to create it from the correct source (channel, airstak
-	// (nft/erc20) or user followers) and in the correct way (async or sync)
 	switch community.Census.Type {
 	case mongo.TypeCommunityCensusFollowers:
 		// if the census type is followers, create the census from the users who
-		// follow the user, the process is async so return add the censusID to the
-		// queue and return it to the client
-		data, err := v.censusFollowers(userFID, delegations)
-		if err != nil {
-			log.Warnf("error creating census for the user: %d: %v", userFID, err)
-			return ctx.Send([]byte("error creating user followers census"), http.StatusInternalServerError)
-		}
-		return ctx.Send(data, http.StatusOK)
+		// follow the user
+		return v.censusFollowers(userFID, delegations)
 	case mongo.TypeCommunityCensusChannel:
 		// if the census type is a channel, create the census from the users who
-		// follow the channel, the process is async so return add the censusID
-		// to the queue and return it to the client
-		data, err := v.censusWarpcastChannel(community.Census.Channel, userFID, delegations)
-		if err != nil {
-			log.Warnf("error creating census for the chanel: %s: %v", community.Census.Channel, err)
-			return ctx.Send([]byte("error creating channel census"), http.StatusInternalServerError)
-		}
-		return ctx.Send(data, http.StatusOK)
+		// follow the channel
+		return v.censusWarpcastChannel(community.Census.Channel, userFID, delegations)
 	case mongo.TypeCommunityCensusNFT, mongo.TypeCommunityCensusERC20:
 		// create the census from the token holders
 		data, err := v.tokenBasedCensus(community.Census.Strategy, community.Census.Type, userFID, delegations)
 		if err != nil {
-			return fmt.Errorf("cannot create erc20/nft based census: %w", err)
+			return nil, fmt.Errorf("cannot create erc20/nft based census: %w", err)
 		}
-		return ctx.Send(data, http.StatusOK)
+		return data, nil
 	default:
-		return ctx.Send([]byte("invalid census type"), http.StatusBadRequest)
+		return nil, errInvalidCommunityCensusType
+	}
+}
+
+// waitForCensus waits until the census of the json encoded censusID provided,
+// as it is returned by the async census helpers, is ready in the background
+// queue, and returns it. It sets the root of the census in the database, as
+// the census queue handler does. It returns an error if the census creation
+// fails or the context provided is done before the census is ready.
+func (v *vocdoniHandler) waitForCensus(ctx context.Context, data []byte) (*CensusInfo, error) {
+	res := map[string]string{}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("cannot decode census response: %w", err)
+	}
+	censusID, err := hex.DecodeString(res["censusId"])
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode censusID: %w", err)
+	}
+	ticker := time.NewTicker(time.Second * 2)
+	defer ticker.Stop()
+	for {
+		if iCensusInfo, ok := v.backgroundQueue.Load(res["censusId"]); ok {
+			if censusInfo, ok := iCensusInfo.(CensusInfo); ok {
+				if censusInfo.Error != "" {
+					return nil, fmt.Errorf("census creation failed: %s", censusInfo.Error)
+				}
+				if censusInfo.Root != nil {
+					if err := v.db.SetRootForCensus(censusID, censusInfo.Root); err != nil {
+						return nil, fmt.Errorf("cannot set root for census: %w", err)
+					}
+					return &censusInfo, nil
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("census not ready: %w", ctx.Err())
+		case <-ticker.C:
+		}
 	}
 }
 
@@ -1377,7 +1439,11 @@ func (v *vocdoniHandler) processCensusRecords(records [][]string, delegations []
 				if errors.Is(err, farcasterapi.ErrNoDataFound) {
 					break
 				}
-				log.Errorw(err, "error fetching users from Neynar API")
+				if errors.Is(err, farcasterapi.ErrPartialData) {
+					log.Warnw("some users could not be fetched from the farcaster API", "error", err)
+				} else {
+					log.Errorw(err, "error fetching users from Neynar API")
+				}
 			}
 			log.Debugw("users found on neynar", "count", len(usersData))
 			for _, userData := range usersData {
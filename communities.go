@@ -405,6 +405,30 @@ func (v *vocdoniHandler) communityStatusHandler(_ *apirest.APIdata, ctx *httprou
 	return ctx.Send(res, http.StatusOK)
 }
 
+// communityReputationHistoryHandler returns the reputation history of the
+// community provided in the URL. The time window can be set with the 'days'
+// query parameter.
+func (v *vocdoniHandler) communityReputationHistoryHandler(_ *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	// get community id from the URL
+	communityID, _, _, err := v.parseCommunityIDFromURL(ctx)
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusBadRequest)
+	}
+	since, err := reputationHistorySince(ctx)
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusBadRequest)
+	}
+	history, err := v.repUpdater.CommunityReputationHistory(communityID, since, 0)
+	if err != nil {
+		return ctx.Send([]byte("error getting community reputation history"), http.StatusInternalServerError)
+	}
+	res, err := json.Marshal(history)
+	if err != nil {
+		return ctx.Send([]byte("error encoding community reputation history"), http.StatusInternalServerError)
+	}
+	return ctx.Send(res, http.StatusOK)
+}
+
 // communitySettingsHandler allows to an admin of a community to update the
 // community information.
 func (v *vocdoniHandler) communitySettingsHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,11 +8,9 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
-	"github.com/vocdoni/vote-frame/airstack"
 	"go.vocdoni.io/dvote/httprouter"
 	"go.vocdoni.io/dvote/httprouter/apirest"
 	"go.vocdoni.io/dvote/log"
-	"go.vocdoni.io/dvote/vochain/transaction/proofs/farcasterproof"
 )
 
 const (
@@ -78,26 +75,12 @@ func (v *vocdoniHandler) composerMetadataHandler(msg *apirest.APIdata, ctx *http
 }
 
 func (v *vocdoniHandler) composerActionHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
-	// decode the packet from the message
-	packet := &FrameSignaturePacket{}
-	if err := json.Unmarshal(msg.Data, packet); err != nil {
-		return fmt.Errorf("failed to unmarshal frame signature packet: %w", err)
-	}
-	// validate the frame package to airstack
-	if v.airstack != nil {
-		airstack.ValidateFrameMessage(msg.Data, v.airstack.ApiKey())
-	}
-	// decode the message bytes
-	messageBytes, err := hex.DecodeString(packet.TrustedData.MessageBytes)
-	if err != nil {
-		return fmt.Errorf("failed to decode message bytes: %w", err)
-	}
-	// verify the frame signature and get the action message and the fid of the
-	// user
-	actionMessage, _, userFID, err := farcasterproof.VerifyFrameSignature(messageBytes)
+	// validate the frame message and get the fid of the user
+	frameMsg, err := v.frames.Validate(msg.Data)
 	if err != nil {
-		return fmt.Errorf("failed to verify frame signature: %w", err)
+		return fmt.Errorf("failed to validate frame message: %w", err)
 	}
+	userFID := frameMsg.FID
 	// get the token of the user from the database, or generate a new one
 	var token string
 	if authTokens, err := v.db.UserAuthorizations(userFID); err != nil {
@@ -123,7 +106,7 @@ func (v *vocdoniHandler) composerActionHandler(msg *apirest.APIdata, ctx *httpro
 	// URL-decode the cast from the action message state, and extract the text
 	// to be used as a question in the composer action form, if any error occurs
 	// ignore it and continue
-	if decodedCast, err := url.QueryUnescape(string(actionMessage.GetState())); err == nil {
+	if decodedCast, err := url.QueryUnescape(string(frameMsg.State)); err == nil {
 		cast := &composerActionCast{}
 		if err := json.Unmarshal([]byte(decodedCast), cast); err == nil {
 			// add the text of the cast that launched the composer action to the URL
@@ -11,11 +11,14 @@ import (
 	"strings"
 	"time"
 
-	"github.com/vocdoni/vote-frame/airstack"
 	"github.com/vocdoni/vote-frame/features"
+	"github.com/vocdoni/vote-frame/frameanalytics"
+	"github.com/vocdoni/vote-frame/frametemplates"
 	"github.com/vocdoni/vote-frame/helpers"
 	"github.com/vocdoni/vote-frame/imageframe"
 	"github.com/vocdoni/vote-frame/mongo"
+	"github.com/vocdoni/vote-frame/openframes"
+	"github.com/vocdoni/vote-frame/quota"
 	"github.com/vocdoni/vote-frame/shortener"
 	"go.vocdoni.io/dvote/api"
 	"go.vocdoni.io/dvote/apiclient"
@@ -54,9 +57,9 @@ func (v *vocdoniHandler) createElection(msg *apirest.APIdata, ctx *httprouter.HT
 	if err := json.Unmarshal(msg.Data, &req); err != nil {
 		return fmt.Errorf("failed to unmarshal election request: %w", err)
 	}
-	// Get the user from the database to log the user creating the election and
-	// check if the user has the required reputation to differents features.
-	var accessProfile *mongo.UserAccessProfile
+	// Get the user from the database to log the user creating the election, its
+	// FID is used to check if the user has the required reputation to
+	// differents features.
 	fid, err := v.db.UserFromAuthToken(msg.AuthToken)
 	if err != nil {
 		log.Errorf("failed to get user from auth token %s: %v", msg.AuthToken, err)
@@ -65,10 +68,6 @@ func (v *vocdoniHandler) createElection(msg *apirest.APIdata, ctx *httprouter.HT
 		if err != nil {
 			return fmt.Errorf("failed to get user from database: %w", err)
 		}
-		accessProfile, err = v.db.UserAccessProfile(fid)
-		if err != nil {
-			return fmt.Errorf("failed to get user access profile: %w", err)
-		}
 		// log the user creating the election for debugging purposes
 		log.Infow("user creating election", "username", user.Username, "fid", fid)
 	}
@@ -91,7 +90,7 @@ func (v *vocdoniHandler) createElection(msg *apirest.APIdata, ctx *httprouter.HT
 			return ctx.Send([]byte("notifications are only available for community polls"), http.StatusBadRequest)
 		}
 		// check if the user has enough reputation to notify voters
-		if !features.IsAllowed(features.NOTIFY_USERS, accessProfile.Reputation) {
+		if !v.featureAllowed(features.NOTIFY_USERS, *req.CommunityID, fid) {
 			return ctx.Send([]byte("user does not have enough reputation to notify voters"), http.StatusBadRequest)
 		}
 		// check if the community allows notifications
@@ -113,6 +112,32 @@ func (v *vocdoniHandler) createElection(msg *apirest.APIdata, ctx *httprouter.HT
 		if req.Duration > maxElectionDuration {
 			return fmt.Errorf("election duration too long")
 		}
+		// check if the user has enough reputation to create long polls
+		if req.Duration > features.LongPollsMinDuration {
+			communityID := ""
+			if req.CommunityID != nil {
+				communityID = *req.CommunityID
+			}
+			if !v.featureAllowed(features.LONG_POLLS, communityID, fid) {
+				return ctx.Send([]byte("user does not have enough reputation to create long polls"), http.StatusBadRequest)
+			}
+		}
+	}
+
+	// check the Open Frames client protocols accepted by the poll, if any
+	if err := openframes.ValidateProtocols(req.Protocols); err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusBadRequest)
+	}
+
+	// check the number of times that the voters can change their vote, if any
+	if err := helpers.ValidateVoteOverwrites(req.Overwrite, req.MaxOverwrites); err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusBadRequest)
+	}
+
+	// check if the user has exceeded the quota of polls, once the request is
+	// validated so the invalid requests do not consume it
+	if exceeded, err := v.checkQuota(ctx, fid, quota.PollCreation); exceeded || err != nil {
+		return err
 	}
 
 	// get the user count from different sources (fallback to the total number of addresses)
@@ -145,10 +170,20 @@ func (v *vocdoniHandler) createElection(msg *apirest.APIdata, ctx *httprouter.HT
 }
 
 func (v *vocdoniHandler) showElection(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
-	// validate the frame package to airstack
-	if v.airstack != nil {
-		airstack.ValidateFrameMessage(msg.Data, v.airstack.ApiKey())
-	}
+	return v.sendElection(msg, ctx, false)
+}
+
+// changeVote shows the question and the options of the election to the voters
+// that already voted and want to change their vote.
+func (v *vocdoniHandler) changeVote(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	return v.sendElection(msg, ctx, true)
+}
+
+// sendElection sends the frame with the question and the options of the
+// election to the eligible voters. The voters that already voted in an
+// election that allows to overwrite the votes get the frame to change their
+// vote first, unless changing is true.
+func (v *vocdoniHandler) sendElection(msg *apirest.APIdata, ctx *httprouter.HTTPContext, changing bool) error {
 	electionID, err := hex.DecodeString(ctx.URLParam("electionID"))
 	if err != nil {
 		return fmt.Errorf("failed to decode electionID: %w", err)
@@ -166,39 +201,69 @@ func (v *vocdoniHandler) showElection(msg *apirest.APIdata, ctx *httprouter.HTTP
 	if err != nil {
 		return fmt.Errorf("failed to fetch election: %w", err)
 	}
-	// unpack the frame data from the message body
-	packet := &FrameSignaturePacket{}
-	if err := json.Unmarshal(msg.Data, packet); err != nil {
-		return fmt.Errorf("failed to unmarshal frame signature packet: %w", err)
-	}
+	var vote *voteData
+	var voteErr error
+	if isOpenFrame(msg.Data) {
+		// verify the Open Frames packet and check if the signer is eligible
+		// to vote
+		vote, voteErr = v.openFrameVoteData(msg.Data, electionID, election.Census.CensusRoot,
+			maxVoteOverwrites(election), false)
+	} else {
+		// validate the frame message from the body and check it was signed
+		// recently for this election
+		frameMsg, err := v.frames.Validate(msg.Data)
+		if err == nil {
+			err = checkFrameMessage(frameMsg, electionID, false)
+		}
+		if err != nil {
+			if response, err := v.handleVoteError(err, nil, electionIDbytes, frameanalytics.FrameShowElection); err != nil {
+				ctx.SetResponseContentType("text/html; charset=utf-8")
+				return ctx.Send(response, http.StatusOK)
+			}
+		}
 
-	// check if the user is eligible to vote and extract the vote data
-	vote, voteErr := extractVoteDataAndCheckIfEligible(packet, electionID, election.Census.CensusRoot, v.cli)
+		// check if the user is eligible to vote and extract the vote data
+		vote, voteErr = extractVoteDataAndCheckIfEligible(frameMsg, electionID, election.Census.CensusRoot,
+			maxVoteOverwrites(election), v.cli)
+	}
 	if voteErr != nil {
 		// check if the user has delegated their vote, if so, return an error
 		dbElection, _ := v.db.Election(electionIDbytes)
-		if dbElection != nil && dbElection.Community != nil {
-			delegations, err := v.db.DelegationsByCommunityFrom(dbElection.Community.ID, uint64(packet.UntrustedData.FID), false)
+		if vote != nil && dbElection != nil && dbElection.Community != nil {
+			delegations, err := v.db.DelegationsByCommunityFrom(dbElection.Community.ID, vote.FID, false)
 			if err != nil {
 				log.Warnw("failed to fetch delegations", "error", err)
 			}
 			if len(delegations) > 0 {
-				if response, err := handleVoteError(ErrVoteDelegated, vote, electionIDbytes); err != nil {
+				if response, err := v.handleVoteError(ErrVoteDelegated, vote, electionIDbytes, frameanalytics.FrameShowElection); err != nil {
 					ctx.SetResponseContentType("text/html; charset=utf-8")
-					return ctx.Send([]byte(response), http.StatusOK)
+					return ctx.Send(response, http.StatusOK)
 				}
 			}
 		}
 
 		// handle the error (if any)
-		if response, err := handleVoteError(voteErr, vote, electionIDbytes); err != nil {
+		if response, err := v.handleVoteError(voteErr, vote, electionIDbytes, frameanalytics.FrameShowElection); err != nil {
 			ctx.SetResponseContentType("text/html; charset=utf-8")
-			return ctx.Send([]byte(response), http.StatusOK)
+			return ctx.Send(response, http.StatusOK)
 		}
 	}
 
 	// get the election metadata (question, title, etc.)
 	metadata := helpers.UnpackMetadata(election.Metadata)
+
+	// offer the voters that already voted to change their vote
+	if vote != nil && vote.Voted && !changing {
+		return v.sendFrame(ctx, electionIDbytes, frametemplates.ChangeVote{
+			Page: frametemplates.Page{
+				Image: imageLink(imageframe.ChangeVoteImage(maxVoteOverwrites(election) - vote.Overwrites)),
+				Title: metadata.Title["default"],
+			},
+			ProcessID: ctx.URLParam("electionID"),
+			Nullifier: vote.Nullifier.String(),
+		})
+	}
+
 	png, err := imageframe.QuestionImage(election)
 	if err != nil {
 		return fmt.Errorf("failed to generate image: %v", err)
@@ -212,34 +277,24 @@ func (v *vocdoniHandler) showElection(msg *apirest.APIdata, ctx *httprouter.HTTP
 		return fmt.Errorf("failed to marshal farcaster state: %w", err)
 	}
 
-	// send the response
-	response := strings.ReplaceAll(frame(frameVote), "{image}", imageLink(png))
-	response = strings.ReplaceAll(response, "{title}", metadata.Title["default"])
-	response = strings.ReplaceAll(response, "{processID}", ctx.URLParam("electionID"))
-	response = strings.ReplaceAll(response, "{state}", string(state))
-
-	r := metadata.Questions[0].Choices
-	for i := 0; i < 4; i++ {
-		if len(r) > i {
-			opt := ""
-			switch i {
-			case 0:
-				opt = "A"
-			case 1:
-				opt = "B"
-			case 2:
-				opt = "C"
-			case 3:
-				opt = "D"
-			}
-			response = strings.ReplaceAll(response, fmt.Sprintf("{option%d}", i), opt)
-			continue
+	// send the response with a button for each of the first four choices
+	options := []string{}
+	for i := range metadata.Questions[0].Choices {
+		if i >= 4 {
+			break
 		}
-		response = strings.ReplaceAll(response, fmt.Sprintf("{option%d}", i), "")
+		options = append(options, string(rune('A'+i)))
 	}
-
-	ctx.SetResponseContentType("text/html; charset=utf-8")
-	return ctx.Send([]byte(response), http.StatusOK)
+	return v.sendFrame(ctx, electionIDbytes, frametemplates.Vote{
+		Page: frametemplates.Page{
+			Image: imageLink(png),
+			Title: metadata.Title["default"],
+		},
+		ProcessID: ctx.URLParam("electionID"),
+		Options:   options,
+		State:     string(state),
+		Accepts:   v.acceptedProtocols(electionIDbytes),
+	})
 }
 
 func (v *vocdoniHandler) checkElection(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
@@ -460,12 +515,7 @@ func newElectionDescription(description *ElectionDescription, census *CensusInfo
 			Autostart: true,
 		},
 		VoteType: api.VoteType{
-			MaxVoteOverwrites: func() int {
-				if description.Overwrite {
-					return 1
-				}
-				return 0
-			}(),
+			MaxVoteOverwrites: int(helpers.VoteOverwrites(description.Overwrite, description.MaxOverwrites)),
 		},
 		TempSIKs: false,
 		Census: api.CensusTypeDescription{
@@ -575,6 +625,11 @@ func (v *vocdoniHandler) createAndSaveElectionAndProfile(desc *ElectionDescripti
 			desc.UsersCountInitial, communityID); err != nil {
 			return fmt.Errorf("failed to save election and profile: %w", err)
 		}
+		if len(desc.Protocols) > 0 {
+			if err := v.db.SetElectionProtocols(electionID, desc.Protocols); err != nil {
+				return fmt.Errorf("failed to set election protocols: %w", err)
+			}
+		}
 		if notify {
 			if len(census.Usernames) > MaxUsersToNotify {
 				return fmt.Errorf("census too large to notify users but election has been created successfully")
@@ -3,6 +3,7 @@ package farcasterapi
 import (
 	"context"
 	"fmt"
+	"strings"
 )
 
 // MaxCastBytes is the maximum number of bytes that a cast can have.
@@ -15,6 +16,15 @@ var (
 	ErrNoNewCasts = fmt.Errorf("no new casts")
 	// ErrChannelNotFound is returned when the requested channel is not found.
 	ErrChannelNotFound = fmt.Errorf("channel not found")
+	// ErrNotSupported is returned when the API does not support the requested
+	// method.
+	ErrNotSupported = fmt.Errorf("not supported")
+	// ErrRateLimited is returned when the API rejects the request because of
+	// its rate limits.
+	ErrRateLimited = fmt.Errorf("rate limited")
+	// ErrPartialData is returned with the data found when the API cannot
+	// resolve all the requested items, so the data may be incomplete.
+	ErrPartialData = fmt.Errorf("partial data")
 )
 
 type API interface {
@@ -40,8 +50,9 @@ type API interface {
 	// given URLs in the content.
 	Publish(ctx context.Context, content string, mentionFids []uint64, embedURLS ...string) error
 	// Reply replies to a cast of the given fid with the given hash and content,
-	// it returns an error if something goes wrong
-	Reply(ctx context.Context, targetMsg *APIMessage, content string, mentionFids []uint64, embedURLS ...string) error
+	// it returns the hash of the reply cast and an error if something goes
+	// wrong
+	Reply(ctx context.Context, targetMsg *APIMessage, content string, mentionFids []uint64, embedURLS ...string) (string, error)
 	// RecastsFIDs retrieves the fids of the users that recast the message with
 	// the given fid and hash, it returns the fids in a slice of uint64 and an
 	// error if something goes wrong.
@@ -50,7 +61,9 @@ type API interface {
 	// something goes wrong, it returns an error
 	UserDataByFID(ctx context.Context, fid uint64) (*Userdata, error)
 	// UserDataByVerificationAddress retrieves the Userdata of the user with the
-	// given verification address, if something goes wrong, it returns an error
+	// given verification address, if something goes wrong, it returns an error.
+	// If some addresses cannot be resolved, it returns the Userdata found with
+	// an ErrPartialData error
 	UserDataByVerificationAddress(ctx context.Context, address []string) ([]*Userdata, error)
 	// WebhookHandler handles the incoming webhooks from the farcaster API
 	WebhookHandler(body []byte) error
@@ -79,6 +92,24 @@ type API interface {
 	DirectMessage(ctx context.Context, content string, to uint64) error
 }
 
+// MentionsStreamer is implemented by the APIs that can stream the mentions of
+// the farcaster user set from an event subscription, using a resumable event
+// cursor instead of polling the last mentions.
+type MentionsStreamer interface {
+	// MentionEvents retrieves the next page of events from the given event ID
+	// and returns the mentions of the farcaster user found in them and the ID
+	// of the next event to request. If the event ID is zero, it starts from
+	// the current time. If something goes wrong, it returns an error.
+	MentionEvents(ctx context.Context, fromEventID uint64) ([]*MentionEvent, uint64, error)
+}
+
+// MentionEvent is a struct that represents a mention of the farcaster user
+// received from an event subscription, with the ID of the event.
+type MentionEvent struct {
+	EventID uint64
+	Message *APIMessage
+}
+
 // ParentAPIMessage is a struct that represents the parent message of an
 // APIMessage that does not includes the parent message itself, but only the
 // fid of the author and hash as reference of the parent message.
@@ -88,6 +119,7 @@ type ParentAPIMessage struct {
 }
 
 // APIMessage is a struct that represents a message in the farcaster API.
+// Channel contains the ID of the channel where the message was cast, if any.
 type APIMessage struct {
 	IsMention bool
 	Content   string
@@ -95,6 +127,7 @@ type APIMessage struct {
 	Hash      string
 	Parent    *ParentAPIMessage
 	Embeds    []string
+	Channel   string
 }
 
 // Userdata is a struct that represents the user data in the farcaster API.
@@ -118,3 +151,24 @@ type Channel struct {
 	Image       string
 	URL         string
 }
+
+// channelURLPrefix is the prefix of the parent URL of the casts published in
+// a channel.
+const channelURLPrefix = "https://warpcast.com/~/channel/"
+
+// ChannelFromParentURL returns the ID of the channel of a cast based on its
+// parent URL, or an empty string if the parent URL is not a channel URL. The
+// legacy channels, whose parent URL is not a warpcast channel URL, are not
+// supported.
+func ChannelFromParentURL(parentURL string) string {
+	if !strings.HasPrefix(parentURL, channelURLPrefix) {
+		return ""
+	}
+	return strings.Trim(strings.TrimPrefix(parentURL, channelURLPrefix), "/")
+}
+
+// ChannelParentURL returns the parent URL of the casts published in the
+// channel with the ID provided.
+func ChannelParentURL(channelID string) string {
+	return channelURLPrefix + channelID
+}
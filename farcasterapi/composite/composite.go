@@ -0,0 +1,466 @@
+// composite package provides an implementation of the farcasterapi.API
+// interface that combines several backends (e.g. Neynar and a Hub). Every
+// method is routed to the first backend that supports it, failing over to the
+// next one on errors or rate limits. The backends that hit their rate limits
+// are skipped for a cool down time. The user data, the user followers and the
+// channel followers are cached with a TTL. The health, the requests, the
+// errors and the latency of every backend are reported as metrics.
+package composite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"go.vocdoni.io/dvote/log"
+)
+
+const (
+	// DefaultUserDataTTL is the default time to keep the user data cached
+	DefaultUserDataTTL = 10 * time.Minute
+	// DefaultFollowersTTL is the default time to keep the followers of users
+	// and channels cached
+	DefaultFollowersTTL = 30 * time.Minute
+	// DefaultCacheSize is the default maximum number of items of every cache
+	DefaultCacheSize = 10000
+	// DefaultRateLimitCoolDown is the default time to skip a backend after it
+	// hits its rate limits
+	DefaultRateLimitCoolDown = time.Minute
+)
+
+// ErrNoBackendAvailable is returned when no backend could handle the request.
+var ErrNoBackendAvailable = fmt.Errorf("no farcaster api backend available")
+
+// Backend struct represents a farcasterapi.API implementation to be used by
+// the composite API, identified by its name in logs and metrics.
+type Backend struct {
+	Name string
+	API  farcasterapi.API
+}
+
+// Config struct contains the configuration of the composite API. The backends
+// are used in the order provided. The zero values of the rest of the fields
+// take the default values.
+type Config struct {
+	Backends          []*Backend
+	UserDataTTL       time.Duration
+	FollowersTTL      time.Duration
+	CacheSize         int
+	RateLimitCoolDown time.Duration
+}
+
+// BackendHealth struct contains the health status of a backend: if its last
+// request succeeded, the last error, until when it is skipped because of its
+// rate limits, and the number of requests and errors.
+type BackendHealth struct {
+	Name          string
+	Healthy       bool
+	LastError     string
+	CoolDownUntil time.Time
+	Requests      uint64
+	Errors        uint64
+}
+
+// backend struct wraps a Backend with its health status.
+type backend struct {
+	*Backend
+	mtx           sync.Mutex
+	healthy       bool
+	lastError     string
+	coolDownUntil time.Time
+	requests      uint64
+	errors        uint64
+}
+
+// API struct implements the farcasterapi.API interface over several backends.
+type API struct {
+	backends    []*backend
+	coolDown    time.Duration
+	userdata    *expirable.LRU[uint64, *farcasterapi.Userdata]
+	followers   *expirable.LRU[uint64, []uint64]
+	channelFIDs *expirable.LRU[string, []uint64]
+	metrics     *metrics.Set
+}
+
+// New creates a new composite API with the configuration provided and
+// registers its metrics. It returns an error if no backend is provided.
+func New(config *Config) (*API, error) {
+	if len(config.Backends) == 0 {
+		return nil, fmt.Errorf("no backends provided")
+	}
+	if config.UserDataTTL == 0 {
+		config.UserDataTTL = DefaultUserDataTTL
+	}
+	if config.FollowersTTL == 0 {
+		config.FollowersTTL = DefaultFollowersTTL
+	}
+	if config.CacheSize == 0 {
+		config.CacheSize = DefaultCacheSize
+	}
+	if config.RateLimitCoolDown == 0 {
+		config.RateLimitCoolDown = DefaultRateLimitCoolDown
+	}
+	a := &API{
+		coolDown:    config.RateLimitCoolDown,
+		userdata:    expirable.NewLRU[uint64, *farcasterapi.Userdata](config.CacheSize, nil, config.UserDataTTL),
+		followers:   expirable.NewLRU[uint64, []uint64](config.CacheSize, nil, config.FollowersTTL),
+		channelFIDs: expirable.NewLRU[string, []uint64](config.CacheSize, nil, config.FollowersTTL),
+		metrics:     metrics.NewSet(),
+	}
+	for _, b := range config.Backends {
+		if b.API == nil {
+			return nil, fmt.Errorf("backend %s has no api", b.Name)
+		}
+		be := &backend{Backend: b, healthy: true}
+		a.backends = append(a.backends, be)
+		a.metrics.NewGauge(fmt.Sprintf(`farcasterapi_backend_healthy{backend=%q}`, b.Name), func() float64 {
+			if be.health().Healthy {
+				return 1
+			}
+			return 0
+		})
+	}
+	for name, cache := range map[string]interface{ Len() int }{
+		"userdata":     a.userdata,
+		"followers":    a.followers,
+		"channel_fids": a.channelFIDs,
+	} {
+		a.metrics.NewGauge(fmt.Sprintf(`farcasterapi_cache_items{cache=%q}`, name), func() float64 {
+			return float64(cache.Len())
+		})
+	}
+	metrics.RegisterSet(a.metrics)
+	return a, nil
+}
+
+// Health returns the health status of every backend, in order.
+func (a *API) Health() []*BackendHealth {
+	health := make([]*BackendHealth, 0, len(a.backends))
+	for _, b := range a.backends {
+		health = append(health, b.health())
+	}
+	return health
+}
+
+// SetFarcasterUser sets the farcaster user in every backend. It only returns
+// an error if no backend accepts it.
+func (a *API) SetFarcasterUser(fid uint64, signer string) error {
+	var errs []error
+	for _, b := range a.backends {
+		if err := b.API.SetFarcasterUser(fid, signer); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+		}
+	}
+	if len(errs) == len(a.backends) {
+		return errors.Join(errs...)
+	}
+	for _, err := range errs {
+		log.Warnw("failed to set farcaster user in backend", "error", err)
+	}
+	return nil
+}
+
+// FID returns the fid of the farcaster user set in the first backend that has
+// one.
+func (a *API) FID() uint64 {
+	for _, b := range a.backends {
+		if fid := b.API.FID(); fid != 0 {
+			return fid
+		}
+	}
+	return 0
+}
+
+// Stop stops every backend and unregisters the metrics.
+func (a *API) Stop() error {
+	var errs []error
+	for _, b := range a.backends {
+		if err := b.API.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+		}
+	}
+	metrics.UnregisterSet(a.metrics)
+	return errors.Join(errs...)
+}
+
+// LastMentions retrieves the last mentions from the given timestamp.
+func (a *API) LastMentions(ctx context.Context, timestamp uint64) ([]*farcasterapi.APIMessage, uint64, error) {
+	type result struct {
+		messages []*farcasterapi.APIMessage
+		last     uint64
+	}
+	res, err := call(ctx, a, "LastMentions", true, func(api farcasterapi.API) (*result, error) {
+		messages, last, err := api.LastMentions(ctx, timestamp)
+		return &result{messages, last}, err
+	})
+	if res == nil {
+		return nil, timestamp, err
+	}
+	return res.messages, res.last, err
+}
+
+// GetCast retrieves the cast with the given fid and hash.
+func (a *API) GetCast(ctx context.Context, fid uint64, hash string) (*farcasterapi.APIMessage, error) {
+	return call(ctx, a, "GetCast", true, func(api farcasterapi.API) (*farcasterapi.APIMessage, error) {
+		return api.GetCast(ctx, fid, hash)
+	})
+}
+
+// Publish publishes a new cast. It only fails over when the backend does not
+// support it or it is rate limited, to avoid publishing it twice.
+func (a *API) Publish(ctx context.Context, content string, mentionFids []uint64, embedURLS ...string) error {
+	_, err := call(ctx, a, "Publish", false, func(api farcasterapi.API) (any, error) {
+		return nil, api.Publish(ctx, content, mentionFids, embedURLS...)
+	})
+	return err
+}
+
+// Reply replies to the given message. It only fails over when the backend
+// does not support it or it is rate limited, to avoid replying twice.
+func (a *API) Reply(ctx context.Context, targetMsg *farcasterapi.APIMessage, content string,
+	mentionFids []uint64, embedURLS ...string,
+) (string, error) {
+	return call(ctx, a, "Reply", false, func(api farcasterapi.API) (string, error) {
+		return api.Reply(ctx, targetMsg, content, mentionFids, embedURLS...)
+	})
+}
+
+// RecastsFIDs retrieves the fids of the users that recast the given message.
+func (a *API) RecastsFIDs(ctx context.Context, msg *farcasterapi.APIMessage) ([]uint64, error) {
+	return call(ctx, a, "RecastsFIDs", true, func(api farcasterapi.API) ([]uint64, error) {
+		return api.RecastsFIDs(ctx, msg)
+	})
+}
+
+// UserDataByFID retrieves the user data of the given fid, from the cache if
+// it is available.
+func (a *API) UserDataByFID(ctx context.Context, fid uint64) (*farcasterapi.Userdata, error) {
+	if userdata, ok := a.userdata.Get(fid); ok {
+		a.cacheHit("userdata")
+		return userdata, nil
+	}
+	a.cacheMiss("userdata")
+	userdata, err := call(ctx, a, "UserDataByFID", true, func(api farcasterapi.API) (*farcasterapi.Userdata, error) {
+		return api.UserDataByFID(ctx, fid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.userdata.Add(fid, userdata)
+	return userdata, nil
+}
+
+// UserDataByVerificationAddress retrieves the user data of the given
+// verification addresses. If no backend resolves all of them, the partial
+// user data returned by a backend, if any, is returned with the error.
+func (a *API) UserDataByVerificationAddress(ctx context.Context, address []string) ([]*farcasterapi.Userdata, error) {
+	var partial []*farcasterapi.Userdata
+	users, err := call(ctx, a, "UserDataByVerificationAddress", true, func(api farcasterapi.API) ([]*farcasterapi.Userdata, error) {
+		users, err := api.UserDataByVerificationAddress(ctx, address)
+		if errors.Is(err, farcasterapi.ErrPartialData) && len(users) > len(partial) {
+			partial = users
+		}
+		return users, err
+	})
+	if err != nil && partial != nil {
+		return partial, err
+	}
+	return users, err
+}
+
+// WebhookHandler handles the incoming webhooks with the first backend that
+// supports them.
+func (a *API) WebhookHandler(body []byte) error {
+	_, err := call(context.Background(), a, "WebhookHandler", false, func(api farcasterapi.API) (any, error) {
+		return nil, api.WebhookHandler(body)
+	})
+	return err
+}
+
+// SignersFromFID retrieves the signers of the user with the given fid.
+func (a *API) SignersFromFID(fid uint64) ([]string, error) {
+	return call(context.Background(), a, "SignersFromFID", true, func(api farcasterapi.API) ([]string, error) {
+		return api.SignersFromFID(fid)
+	})
+}
+
+// UserFollowers retrieves the fids of the followers of the given user, from
+// the cache if it is available.
+func (a *API) UserFollowers(ctx context.Context, fid uint64) ([]uint64, error) {
+	if followers, ok := a.followers.Get(fid); ok {
+		a.cacheHit("followers")
+		return followers, nil
+	}
+	a.cacheMiss("followers")
+	followers, err := call(ctx, a, "UserFollowers", true, func(api farcasterapi.API) ([]uint64, error) {
+		return api.UserFollowers(ctx, fid)
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.followers.Add(fid, followers)
+	return followers, nil
+}
+
+// Channel retrieves the channel with the given id.
+func (a *API) Channel(ctx context.Context, channelID string) (*farcasterapi.Channel, error) {
+	return call(ctx, a, "Channel", true, func(api farcasterapi.API) (*farcasterapi.Channel, error) {
+		return api.Channel(ctx, channelID)
+	})
+}
+
+// ChannelFIDs retrieves the fids of the followers of the given channel, from
+// the cache if it is available. If it is cached and a progress channel is
+// provided, the completed progress is sent to it.
+func (a *API) ChannelFIDs(ctx context.Context, channelID string, progress chan int) ([]uint64, error) {
+	if fids, ok := a.channelFIDs.Get(channelID); ok {
+		a.cacheHit("channel_fids")
+		if progress != nil {
+			progress <- 100
+		}
+		return fids, nil
+	}
+	a.cacheMiss("channel_fids")
+	fids, err := call(ctx, a, "ChannelFIDs", true, func(api farcasterapi.API) ([]uint64, error) {
+		return api.ChannelFIDs(ctx, channelID, progress)
+	})
+	if err != nil {
+		return nil, err
+	}
+	a.channelFIDs.Add(channelID, fids)
+	return fids, nil
+}
+
+// ChannelExists returns if the channel with the given id exists.
+func (a *API) ChannelExists(ctx context.Context, channelID string) (bool, error) {
+	return call(ctx, a, "ChannelExists", true, func(api farcasterapi.API) (bool, error) {
+		return api.ChannelExists(ctx, channelID)
+	})
+}
+
+// FindChannel returns the channels that match the given query.
+func (a *API) FindChannel(ctx context.Context, query string, adminFid uint64) ([]*farcasterapi.Channel, error) {
+	return call(ctx, a, "FindChannel", true, func(api farcasterapi.API) ([]*farcasterapi.Channel, error) {
+		return api.FindChannel(ctx, query, adminFid)
+	})
+}
+
+// DirectMessage sends a direct message to the user with the given fid. It
+// only fails over when the backend does not support it or it is rate limited,
+// to avoid sending it twice.
+func (a *API) DirectMessage(ctx context.Context, content string, to uint64) error {
+	_, err := call(ctx, a, "DirectMessage", false, func(api farcasterapi.API) (any, error) {
+		return nil, api.DirectMessage(ctx, content, to)
+	})
+	return err
+}
+
+// cacheHit and cacheMiss update the metrics of the cache provided.
+func (a *API) cacheHit(cache string) {
+	a.metrics.GetOrCreateCounter(fmt.Sprintf(`farcasterapi_cache_hits_total{cache=%q}`, cache)).Inc()
+}
+
+func (a *API) cacheMiss(cache string) {
+	a.metrics.GetOrCreateCounter(fmt.Sprintf(`farcasterapi_cache_misses_total{cache=%q}`, cache)).Inc()
+}
+
+// call function calls the method provided in the backends in order until one
+// of them succeeds or returns an error that is an answer by itself (e.g. the
+// data does not exist). The backends that do not support the method are
+// skipped, and the ones that are cooling down after hitting their rate limits
+// are skipped too unless all of them are. If failover is false, only the
+// errors of unsupported methods and rate limits fail over to the next
+// backend, for the methods that must not be executed twice. It records the
+// health and the metrics of every backend called.
+func call[T any](ctx context.Context, a *API, method string, failover bool,
+	fn func(farcasterapi.API) (T, error),
+) (T, error) {
+	var zero T
+	var errs []error
+	for _, b := range a.candidates() {
+		start := time.Now()
+		res, err := fn(b.API)
+		a.record(b, method, time.Since(start), err)
+		if err == nil || isAnswer(err) {
+			return res, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", b.Name, err))
+		canFailover := failover || errors.Is(err, farcasterapi.ErrNotSupported) ||
+			errors.Is(err, farcasterapi.ErrRateLimited)
+		if !canFailover || ctx.Err() != nil {
+			return zero, errors.Join(errs...)
+		}
+	}
+	return zero, errors.Join(append([]error{ErrNoBackendAvailable}, errs...)...)
+}
+
+// candidates returns the backends that are not cooling down after hitting
+// their rate limits, in order. If all of them are, it returns all of them.
+func (a *API) candidates() []*backend {
+	now := time.Now()
+	candidates := make([]*backend, 0, len(a.backends))
+	for _, b := range a.backends {
+		if b.health().CoolDownUntil.Before(now) {
+			candidates = append(candidates, b)
+		}
+	}
+	if len(candidates) == 0 {
+		return a.backends
+	}
+	return candidates
+}
+
+// record updates the health and the metrics of the backend provided with the
+// result of a call to the method provided.
+func (a *API) record(b *backend, method string, latency time.Duration, err error) {
+	labels := fmt.Sprintf(`{backend=%q,method=%q}`, b.Name, method)
+	// the unsupported methods are not requests to the backend
+	if errors.Is(err, farcasterapi.ErrNotSupported) {
+		return
+	}
+	a.metrics.GetOrCreateCounter("farcasterapi_backend_requests_total" + labels).Inc()
+	a.metrics.GetOrCreateHistogram("farcasterapi_backend_request_duration_seconds" + labels).Update(latency.Seconds())
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.requests++
+	if err == nil || isAnswer(err) {
+		b.healthy = true
+		return
+	}
+	a.metrics.GetOrCreateCounter("farcasterapi_backend_errors_total" + labels).Inc()
+	b.errors++
+	b.healthy = false
+	b.lastError = err.Error()
+	if errors.Is(err, farcasterapi.ErrRateLimited) {
+		b.coolDownUntil = time.Now().Add(a.coolDown)
+		log.Warnw("farcaster api backend rate limited", "backend", b.Name, "method", method,
+			"coolDownUntil", b.coolDownUntil)
+	}
+}
+
+// health returns the health status of the backend.
+func (b *backend) health() *BackendHealth {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return &BackendHealth{
+		Name:          b.Name,
+		Healthy:       b.healthy && time.Now().After(b.coolDownUntil),
+		LastError:     b.lastError,
+		CoolDownUntil: b.coolDownUntil,
+		Requests:      b.requests,
+		Errors:        b.errors,
+	}
+}
+
+// isAnswer returns if the error provided is a valid answer of a backend, that
+// must be returned to the caller instead of failing over to the next backend.
+func isAnswer(err error) bool {
+	return errors.Is(err, farcasterapi.ErrNoDataFound) ||
+		errors.Is(err, farcasterapi.ErrChannelNotFound) ||
+		errors.Is(err, farcasterapi.ErrNoNewCasts)
+}
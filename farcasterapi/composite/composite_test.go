@@ -0,0 +1,169 @@
+package composite
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"github.com/vocdoni/vote-frame/farcasterapi/fake"
+)
+
+// check that the composite API implements the farcasterapi.API interface
+var _ farcasterapi.API = (*API)(nil)
+
+// failingAPI wraps a fake API to return the error provided in some methods
+// and count the calls to them.
+type failingAPI struct {
+	*fake.API
+	err   error
+	calls int
+}
+
+func (f *failingAPI) UserDataByFID(ctx context.Context, fid uint64) (*farcasterapi.Userdata, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.API.UserDataByFID(ctx, fid)
+}
+
+func (f *failingAPI) UserDataByVerificationAddress(ctx context.Context, addresses []string) ([]*farcasterapi.Userdata, error) {
+	f.calls++
+	users, err := f.API.UserDataByVerificationAddress(ctx, addresses)
+	if f.err != nil {
+		// return the users found with the error, as partial results
+		return users, f.err
+	}
+	return users, err
+}
+
+func (f *failingAPI) Publish(ctx context.Context, content string, mentions []uint64, embeds ...string) error {
+	f.calls++
+	if f.err != nil {
+		return f.err
+	}
+	return f.API.Publish(ctx, content, mentions, embeds...)
+}
+
+func newBackends() (*failingAPI, *failingAPI) {
+	primary, secondary := fake.New(), fake.New()
+	for _, api := range []*fake.API{primary, secondary} {
+		api.AddUser(&farcasterapi.Userdata{FID: 1, Username: "alice"})
+		_ = api.SetFarcasterUser(100, "")
+	}
+	return &failingAPI{API: primary}, &failingAPI{API: secondary}
+}
+
+func TestFailoverAndCache(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	primary, secondary := newBackends()
+	api, err := New(&Config{Backends: []*Backend{
+		{Name: "primary", API: primary},
+		{Name: "secondary", API: secondary},
+	}})
+	c.Assert(err, qt.IsNil)
+	defer func() { c.Assert(api.Stop(), qt.IsNil) }()
+
+	// fails over to the secondary backend on errors
+	primary.err = fmt.Errorf("connection refused")
+	user, err := api.UserDataByFID(ctx, 1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(user.Username, qt.Equals, "alice")
+	c.Assert(primary.calls, qt.Equals, 1)
+	c.Assert(secondary.calls, qt.Equals, 1)
+	health := api.Health()
+	c.Assert(health[0].Healthy, qt.IsFalse)
+	c.Assert(health[0].LastError, qt.Equals, "connection refused")
+	c.Assert(health[1].Healthy, qt.IsTrue)
+
+	// the user data is cached
+	_, err = api.UserDataByFID(ctx, 1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(primary.calls+secondary.calls, qt.Equals, 2)
+
+	// the not found errors are answers, so they do not fail over
+	primary.err = nil
+	_, err = api.UserDataByFID(ctx, 2)
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrNoDataFound)
+	c.Assert(secondary.calls, qt.Equals, 1)
+	c.Assert(api.Health()[0].Healthy, qt.IsTrue)
+
+	// the unsupported methods are routed to the capable backends without
+	// affecting the health of the backends that do not support them
+	primary.err = fmt.Errorf("hub: %w", farcasterapi.ErrNotSupported)
+	secondary.AddUser(&farcasterapi.Userdata{FID: 5, Username: "bob"})
+	requests := api.Health()[0].Requests
+	user, err = api.UserDataByFID(ctx, 5)
+	c.Assert(err, qt.IsNil)
+	c.Assert(user.Username, qt.Equals, "bob")
+	c.Assert(api.Health()[0].Healthy, qt.IsTrue)
+	c.Assert(api.Health()[0].Requests, qt.Equals, requests)
+
+	// if every backend fails, the errors are returned
+	primary.err = fmt.Errorf("primary down")
+	secondary.err = fmt.Errorf("secondary down")
+	_, err = api.UserDataByFID(ctx, 3)
+	c.Assert(err, qt.ErrorIs, ErrNoBackendAvailable)
+	c.Assert(err, qt.ErrorMatches, "(?s).*primary down.*secondary down.*")
+}
+
+func TestRateLimitsAndWrites(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	primary, secondary := newBackends()
+	api, err := New(&Config{Backends: []*Backend{
+		{Name: "primary", API: primary},
+		{Name: "secondary", API: secondary},
+	}})
+	c.Assert(err, qt.IsNil)
+	defer func() { c.Assert(api.Stop(), qt.IsNil) }()
+
+	// the rate limited backends are skipped while cooling down
+	primary.err = fmt.Errorf("too many requests: %w", farcasterapi.ErrRateLimited)
+	c.Assert(api.Publish(ctx, "hello", nil), qt.IsNil)
+	c.Assert(secondary.Published(), qt.HasLen, 1)
+	c.Assert(api.Health()[0].CoolDownUntil.IsZero(), qt.IsFalse)
+	c.Assert(api.Publish(ctx, "hello again", nil), qt.IsNil)
+	c.Assert(primary.calls, qt.Equals, 1)
+	c.Assert(secondary.Published(), qt.HasLen, 2)
+
+	// the writes do not fail over on other errors to avoid duplicates
+	secondary.err = fmt.Errorf("timeout")
+	c.Assert(api.Publish(ctx, "hello twice", nil), qt.ErrorMatches, "secondary: timeout")
+	c.Assert(primary.calls, qt.Equals, 1)
+	c.Assert(primary.Published(), qt.HasLen, 0)
+}
+
+func TestPartialUserData(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	primary, secondary := newBackends()
+	secondary.AddUser(&farcasterapi.Userdata{FID: 2, Username: "bob", VerificationsAddresses: []string{"0xb0b"}})
+	api, err := New(&Config{Backends: []*Backend{
+		{Name: "primary", API: primary},
+		{Name: "secondary", API: secondary},
+	}})
+	c.Assert(err, qt.IsNil)
+	defer func() { c.Assert(api.Stop(), qt.IsNil) }()
+
+	// the partial results of a backend are returned with the error if no
+	// backend resolves all the addresses
+	primary.err = fmt.Errorf("timeout")
+	secondary.err = fmt.Errorf("1 of 2 addresses are not indexed: %w", farcasterapi.ErrPartialData)
+	users, err := api.UserDataByVerificationAddress(ctx, []string{"0xb0b", "0xunknown"})
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrPartialData)
+	c.Assert(users, qt.HasLen, 1)
+	c.Assert(users[0].Username, qt.Equals, "bob")
+
+	// the complete results are returned without error
+	secondary.err = nil
+	users, err = api.UserDataByVerificationAddress(ctx, []string{"0xb0b"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(users, qt.HasLen, 1)
+}
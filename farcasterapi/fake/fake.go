@@ -0,0 +1,504 @@
+// fake package provides an in-memory implementation of the farcasterapi.API
+// interface to be used in tests and for local development without any live
+// Farcaster API. It includes a scriptable social graph of users, followers,
+// channels, casts and recasts, and records the casts published and the
+// direct messages sent to be asserted. Hooks can be set to be notified about
+// them.
+package fake
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vocdoni/vote-frame/farcasterapi"
+)
+
+// DirectMessage struct represents a direct message sent through the fake API.
+type DirectMessage struct {
+	To      uint64
+	Content string
+}
+
+// cast struct represents a cast stored in the fake API with its timestamp in
+// seconds.
+type cast struct {
+	msg       *farcasterapi.APIMessage
+	timestamp uint64
+}
+
+// channel struct represents a channel stored in the fake API with the FIDs of
+// its followers and admins.
+type channel struct {
+	info      *farcasterapi.Channel
+	followers []uint64
+	admins    []uint64
+}
+
+// API struct implements the farcasterapi.API interface with an in-memory
+// social graph. It is safe for concurrent use.
+type API struct {
+	mtx           sync.RWMutex
+	fid           uint64
+	signer        string
+	users         map[uint64]*farcasterapi.Userdata
+	followers     map[uint64][]uint64
+	channels      map[string]*channel
+	casts         []*cast
+	recasts       map[string][]uint64
+	published     []*farcasterapi.APIMessage
+	dms           []*DirectMessage
+	lastTimestamp uint64
+	nextHash      uint64
+	onPublish     func(*farcasterapi.APIMessage)
+	onDM          func(*DirectMessage)
+}
+
+// New creates a new fake API with an empty social graph.
+func New() *API {
+	return &API{
+		users:     map[uint64]*farcasterapi.Userdata{},
+		followers: map[uint64][]uint64{},
+		channels:  map[string]*channel{},
+		recasts:   map[string][]uint64{},
+	}
+}
+
+// seedUser struct represents a user of the JSON seed of the social graph.
+type seedUser struct {
+	FID            uint64   `json:"fid"`
+	Username       string   `json:"username"`
+	Displayname    string   `json:"displayname"`
+	CustodyAddress string   `json:"custodyAddress"`
+	Verifications  []string `json:"verifications"`
+	Signers        []string `json:"signers"`
+	Avatar         string   `json:"avatar"`
+	Followers      []uint64 `json:"followers"`
+}
+
+// seedChannel struct represents a channel of the JSON seed of the social
+// graph.
+type seedChannel struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Image       string   `json:"image"`
+	Followers   []uint64 `json:"followers"`
+	Admins      []uint64 `json:"admins"`
+}
+
+// seed struct represents the JSON seed of the social graph.
+type seed struct {
+	Users    []*seedUser    `json:"users"`
+	Channels []*seedChannel `json:"channels"`
+}
+
+// LoadJSON populates the social graph with the users and channels of the JSON
+// document provided, with the following format:
+//
+//	{
+//	  "users": [{"fid": 1, "username": "alice", "custodyAddress": "0x...",
+//	    "verifications": ["0x..."], "followers": [2, 3]}],
+//	  "channels": [{"id": "vocdoni", "name": "Vocdoni", "followers": [1, 2],
+//	    "admins": [1]}]
+//	}
+func (f *API) LoadJSON(data []byte) error {
+	s := &seed{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return fmt.Errorf("error decoding seed: %w", err)
+	}
+	for _, u := range s.Users {
+		f.AddUser(&farcasterapi.Userdata{
+			FID:                    u.FID,
+			Username:               u.Username,
+			Displayname:            u.Displayname,
+			CustodyAddress:         u.CustodyAddress,
+			VerificationsAddresses: u.Verifications,
+			Signers:                u.Signers,
+			Avatar:                 u.Avatar,
+		})
+		for _, follower := range u.Followers {
+			f.AddFollower(u.FID, follower)
+		}
+	}
+	for _, c := range s.Channels {
+		f.AddChannel(&farcasterapi.Channel{
+			ID:          c.ID,
+			Name:        c.Name,
+			Description: c.Description,
+			Image:       c.Image,
+		}, c.Admins...)
+		for _, follower := range c.Followers {
+			f.FollowChannel(c.ID, follower)
+		}
+	}
+	return nil
+}
+
+// AddUser adds the user provided to the social graph, replacing it if it
+// already exists.
+func (f *API) AddUser(user *farcasterapi.Userdata) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.users[user.FID] = user
+}
+
+// AddFollower makes the follower provided follow the user provided.
+func (f *API) AddFollower(fid, follower uint64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if !slices.Contains(f.followers[fid], follower) {
+		f.followers[fid] = append(f.followers[fid], follower)
+	}
+}
+
+// AddChannel adds the channel provided to the social graph with the admins
+// provided, replacing it if it already exists.
+func (f *API) AddChannel(info *farcasterapi.Channel, admins ...uint64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.channels[info.ID] = &channel{info: info, admins: admins}
+}
+
+// FollowChannel makes the user provided follow the channel provided. It does
+// nothing if the channel does not exist.
+func (f *API) FollowChannel(channelID string, fid uint64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	ch, ok := f.channels[channelID]
+	if !ok || slices.Contains(ch.followers, fid) {
+		return
+	}
+	ch.followers = append(ch.followers, fid)
+	ch.info.Followers = len(ch.followers)
+}
+
+// AddCast adds the cast provided to the social graph and returns its hash. If
+// the cast has no hash, a new one is generated. The cast is returned by
+// LastMentions if it is a mention.
+func (f *API) AddCast(msg *farcasterapi.APIMessage) string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.addCast(msg)
+	return msg.Hash
+}
+
+// AddMention adds a new cast of the author provided that mentions the bot
+// with the content provided, and returns its hash.
+func (f *API) AddMention(author uint64, content string) string {
+	return f.AddCast(&farcasterapi.APIMessage{
+		IsMention: true,
+		Author:    author,
+		Content:   content,
+	})
+}
+
+// AddRecast makes the user provided recast the cast with the hash provided.
+func (f *API) AddRecast(hash string, fid uint64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if !slices.Contains(f.recasts[hash], fid) {
+		f.recasts[hash] = append(f.recasts[hash], fid)
+	}
+}
+
+// Published returns the casts published through the API, including the
+// replies, in order.
+func (f *API) Published() []*farcasterapi.APIMessage {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return slices.Clone(f.published)
+}
+
+// DirectMessages returns the direct messages sent through the API, in order.
+func (f *API) DirectMessages() []*DirectMessage {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return slices.Clone(f.dms)
+}
+
+// OnPublish sets a hook that is called with every cast published through the
+// API, including the replies.
+func (f *API) OnPublish(hook func(*farcasterapi.APIMessage)) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.onPublish = hook
+}
+
+// OnDirectMessage sets a hook that is called with every direct message sent
+// through the API.
+func (f *API) OnDirectMessage(hook func(*DirectMessage)) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.onDM = hook
+}
+
+// SetFarcasterUser sets the farcaster user with the given fid and signer.
+func (f *API) SetFarcasterUser(fid uint64, signer string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.fid = fid
+	f.signer = signer
+	// ensure that the bot user exists
+	if _, ok := f.users[fid]; !ok {
+		f.users[fid] = &farcasterapi.Userdata{FID: fid, Username: fmt.Sprintf("fid%d", fid)}
+	}
+	return nil
+}
+
+// FID returns the fid of the farcaster user set in the API.
+func (f *API) FID() uint64 {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return f.fid
+}
+
+// Stop stops the API. It does nothing.
+func (f *API) Stop() error {
+	return nil
+}
+
+// LastMentions returns the mentions added to the social graph after the given
+// timestamp, and the timestamp of the last one. If there are no new mentions,
+// it returns a farcasterapi.ErrNoNewCasts error.
+func (f *API) LastMentions(_ context.Context, timestamp uint64) ([]*farcasterapi.APIMessage, uint64, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	messages := []*farcasterapi.APIMessage{}
+	lastTimestamp := timestamp
+	for _, c := range f.casts {
+		if !c.msg.IsMention || c.timestamp <= timestamp {
+			continue
+		}
+		messages = append(messages, c.msg)
+		lastTimestamp = max(lastTimestamp, c.timestamp)
+	}
+	if len(messages) == 0 {
+		return nil, timestamp, farcasterapi.ErrNoNewCasts
+	}
+	return messages, lastTimestamp, nil
+}
+
+// GetCast returns the cast with the given fid and hash.
+func (f *API) GetCast(_ context.Context, fid uint64, hash string) (*farcasterapi.APIMessage, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	for _, c := range f.casts {
+		if c.msg.Author == fid && c.msg.Hash == hash {
+			return c.msg, nil
+		}
+	}
+	return nil, farcasterapi.ErrNoDataFound
+}
+
+// Publish publishes a new cast of the farcaster user set with the given
+// content and embeds.
+func (f *API) Publish(_ context.Context, content string, _ []uint64, embedURLs ...string) error {
+	return f.publish(&farcasterapi.APIMessage{Content: content, Embeds: embedURLs})
+}
+
+// Reply publishes a new cast of the farcaster user set with the given content
+// and embeds as a reply to the target message. It returns the hash of the
+// reply.
+func (f *API) Reply(_ context.Context, targetMsg *farcasterapi.APIMessage, content string,
+	_ []uint64, embedURLs ...string,
+) (string, error) {
+	msg := &farcasterapi.APIMessage{
+		Content: content,
+		Embeds:  embedURLs,
+		Parent:  &farcasterapi.ParentAPIMessage{FID: targetMsg.Author, Hash: targetMsg.Hash},
+	}
+	if err := f.publish(msg); err != nil {
+		return "", err
+	}
+	return msg.Hash, nil
+}
+
+// RecastsFIDs returns the fids of the users that recast the message provided.
+func (f *API) RecastsFIDs(_ context.Context, msg *farcasterapi.APIMessage) ([]uint64, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return slices.Clone(f.recasts[msg.Hash]), nil
+}
+
+// UserDataByFID returns the user with the given fid. If it does not exist, it
+// returns a farcasterapi.ErrNoDataFound error.
+func (f *API) UserDataByFID(_ context.Context, fid uint64) (*farcasterapi.Userdata, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	user, ok := f.users[fid]
+	if !ok {
+		return nil, farcasterapi.ErrNoDataFound
+	}
+	return user, nil
+}
+
+// UserDataByVerificationAddress returns the users whose custody or verified
+// addresses include any of the addresses provided. If none is found, it
+// returns a farcasterapi.ErrNoDataFound error.
+func (f *API) UserDataByVerificationAddress(_ context.Context, addresses []string) ([]*farcasterapi.Userdata, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	users := []*farcasterapi.Userdata{}
+	for _, user := range f.users {
+		userAddresses := append([]string{user.CustodyAddress}, user.VerificationsAddresses...)
+		if slices.ContainsFunc(addresses, func(addr string) bool {
+			return slices.ContainsFunc(userAddresses, func(userAddr string) bool {
+				return userAddr != "" && strings.EqualFold(addr, userAddr)
+			})
+		}) {
+			users = append(users, user)
+		}
+	}
+	if len(users) == 0 {
+		return nil, farcasterapi.ErrNoDataFound
+	}
+	return users, nil
+}
+
+// WebhookHandler handles the incoming webhooks. It does nothing.
+func (f *API) WebhookHandler(_ []byte) error {
+	return nil
+}
+
+// SignersFromFID returns the signers of the user with the given fid.
+func (f *API) SignersFromFID(fid uint64) ([]string, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	user, ok := f.users[fid]
+	if !ok {
+		return nil, farcasterapi.ErrNoDataFound
+	}
+	return slices.Clone(user.Signers), nil
+}
+
+// UserFollowers returns the fids of the followers of the user with the given
+// fid.
+func (f *API) UserFollowers(_ context.Context, fid uint64) ([]uint64, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return slices.Clone(f.followers[fid]), nil
+}
+
+// Channel returns the channel with the given id. If it does not exist, it
+// returns a farcasterapi.ErrChannelNotFound error.
+func (f *API) Channel(_ context.Context, channelID string) (*farcasterapi.Channel, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	ch, ok := f.channels[channelID]
+	if !ok {
+		return nil, farcasterapi.ErrChannelNotFound
+	}
+	return ch.info, nil
+}
+
+// ChannelFIDs returns the fids of the followers of the channel with the given
+// id. If it does not exist, it returns a farcasterapi.ErrChannelNotFound
+// error. If a progress channel is provided, it sends the completed progress
+// to it.
+func (f *API) ChannelFIDs(_ context.Context, channelID string, progress chan int) ([]uint64, error) {
+	f.mtx.RLock()
+	ch, ok := f.channels[channelID]
+	var followers []uint64
+	if ok {
+		followers = slices.Clone(ch.followers)
+	}
+	f.mtx.RUnlock()
+	if !ok {
+		return nil, farcasterapi.ErrChannelNotFound
+	}
+	if progress != nil {
+		progress <- 100
+	}
+	return followers, nil
+}
+
+// ChannelExists returns if the channel with the given id exists.
+func (f *API) ChannelExists(_ context.Context, channelID string) (bool, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	_, ok := f.channels[channelID]
+	return ok, nil
+}
+
+// FindChannel returns the channels whose id or name contains the query
+// provided. If an admin fid is provided, only the channels administered by it
+// are returned. If none is found, it returns a farcasterapi.ErrNoDataFound
+// error.
+func (f *API) FindChannel(_ context.Context, query string, adminFid uint64) ([]*farcasterapi.Channel, error) {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	query = strings.ToLower(query)
+	channels := []*farcasterapi.Channel{}
+	for _, ch := range f.channels {
+		matches := strings.Contains(strings.ToLower(ch.info.ID), query) ||
+			strings.Contains(strings.ToLower(ch.info.Name), query)
+		if matches && (adminFid == 0 || slices.Contains(ch.admins, adminFid)) {
+			channels = append(channels, ch.info)
+		}
+	}
+	if len(channels) == 0 {
+		return nil, farcasterapi.ErrNoDataFound
+	}
+	slices.SortFunc(channels, func(a, b *farcasterapi.Channel) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return channels, nil
+}
+
+// DirectMessage records a direct message with the given content to the user
+// with the given fid and calls the direct message hook, if any.
+func (f *API) DirectMessage(_ context.Context, content string, to uint64) error {
+	dm := &DirectMessage{To: to, Content: content}
+	f.mtx.Lock()
+	f.dms = append(f.dms, dm)
+	hook := f.onDM
+	f.mtx.Unlock()
+	if hook != nil {
+		hook(dm)
+	}
+	return nil
+}
+
+// publish records the cast provided as published by the farcaster user set,
+// adds it to the social graph and calls the publish hook, if any. It returns
+// an error if the farcaster user is not set or the content is too long.
+func (f *API) publish(msg *farcasterapi.APIMessage) error {
+	if len([]byte(msg.Content)) > farcasterapi.MaxCastBytes {
+		return fmt.Errorf("content is too long")
+	}
+	f.mtx.Lock()
+	if f.fid == 0 {
+		f.mtx.Unlock()
+		return fmt.Errorf("no farcaster user set")
+	}
+	msg.Author = f.fid
+	f.addCast(msg)
+	f.published = append(f.published, msg)
+	hook := f.onPublish
+	f.mtx.Unlock()
+	if hook != nil {
+		hook(msg)
+	}
+	return nil
+}
+
+// addCast stores the cast provided, generating its hash if it has no one,
+// with a timestamp greater than the previous ones. The timestamp is at least
+// one second in the future, so the casts added in the same second that a
+// client requests the last mentions are not missed. It must be called with
+// the lock held.
+func (f *API) addCast(msg *farcasterapi.APIMessage) {
+	if msg.Hash == "" {
+		f.nextHash++
+		msg.Hash = "0x" + hex.EncodeToString([]byte(fmt.Sprintf("%020d", f.nextHash)))
+	}
+	timestamp := max(uint64(time.Now().Unix())+1, f.lastTimestamp+1)
+	f.lastTimestamp = timestamp
+	f.casts = append(f.casts, &cast{msg: msg, timestamp: timestamp})
+}
@@ -0,0 +1,126 @@
+package fake
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/farcasterapi"
+)
+
+// check that the fake API implements the farcasterapi.API interface
+var _ farcasterapi.API = (*API)(nil)
+
+const testSeed = `{
+	"users": [
+		{"fid": 1, "username": "alice", "custodyAddress": "0xA1", "verifications": ["0xa2"], "followers": [2, 3]},
+		{"fid": 2, "username": "bob"},
+		{"fid": 3, "username": "carol"}
+	],
+	"channels": [
+		{"id": "vocdoni", "name": "Vocdoni", "followers": [1, 2], "admins": [1]},
+		{"id": "degen", "name": "Degen", "followers": [3]}
+	]
+}`
+
+func TestSocialGraph(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	api := New()
+	c.Assert(api.LoadJSON([]byte(testSeed)), qt.IsNil)
+
+	user, err := api.UserDataByFID(ctx, 1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(user.Username, qt.Equals, "alice")
+	_, err = api.UserDataByFID(ctx, 100)
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrNoDataFound)
+
+	users, err := api.UserDataByVerificationAddress(ctx, []string{"0xa1"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(users, qt.HasLen, 1)
+	c.Assert(users[0].FID, qt.Equals, uint64(1))
+
+	followers, err := api.UserFollowers(ctx, 1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(followers, qt.DeepEquals, []uint64{2, 3})
+
+	exists, err := api.ChannelExists(ctx, "vocdoni")
+	c.Assert(err, qt.IsNil)
+	c.Assert(exists, qt.IsTrue)
+	channel, err := api.Channel(ctx, "vocdoni")
+	c.Assert(err, qt.IsNil)
+	c.Assert(channel.Followers, qt.Equals, 2)
+	_, err = api.Channel(ctx, "unknown")
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrChannelNotFound)
+
+	progress := make(chan int, 1)
+	fids, err := api.ChannelFIDs(ctx, "vocdoni", progress)
+	c.Assert(err, qt.IsNil)
+	c.Assert(fids, qt.DeepEquals, []uint64{1, 2})
+	c.Assert(<-progress, qt.Equals, 100)
+
+	channels, err := api.FindChannel(ctx, "o", 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(channels, qt.HasLen, 1)
+	channels, err = api.FindChannel(ctx, "VOC", 1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(channels, qt.HasLen, 1)
+	c.Assert(channels[0].ID, qt.Equals, "vocdoni")
+	_, err = api.FindChannel(ctx, "degen", 1)
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrNoDataFound)
+}
+
+func TestCastsAndMessages(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	api := New()
+	c.Assert(api.LoadJSON([]byte(testSeed)), qt.IsNil)
+	c.Assert(api.SetFarcasterUser(100, "signer"), qt.IsNil)
+
+	// the mentions added after the given timestamp are returned
+	start := uint64(time.Now().Unix())
+	hash := api.AddMention(1, "@bot what?\n- a\n- b")
+	api.AddCast(&farcasterapi.APIMessage{Author: 2, Content: "not a mention"})
+	mentions, last, err := api.LastMentions(ctx, start)
+	c.Assert(err, qt.IsNil)
+	c.Assert(mentions, qt.HasLen, 1)
+	c.Assert(mentions[0].Hash, qt.Equals, hash)
+	_, _, err = api.LastMentions(ctx, last)
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrNoNewCasts)
+
+	cast, err := api.GetCast(ctx, 1, hash)
+	c.Assert(err, qt.IsNil)
+	c.Assert(cast.Content, qt.Equals, "@bot what?\n- a\n- b")
+
+	api.AddRecast(hash, 2)
+	api.AddRecast(hash, 3)
+	recasters, err := api.RecastsFIDs(ctx, cast)
+	c.Assert(err, qt.IsNil)
+	c.Assert(recasters, qt.DeepEquals, []uint64{2, 3})
+
+	// the published casts and direct messages are recorded and hooked
+	var hooked []*farcasterapi.APIMessage
+	api.OnPublish(func(msg *farcasterapi.APIMessage) { hooked = append(hooked, msg) })
+	var hookedDMs []*DirectMessage
+	api.OnDirectMessage(func(dm *DirectMessage) { hookedDMs = append(hookedDMs, dm) })
+
+	replyHash, err := api.Reply(ctx, cast, "done", nil, "https://frame")
+	c.Assert(err, qt.IsNil)
+	c.Assert(api.Publish(ctx, "hello", nil), qt.IsNil)
+	c.Assert(api.Publish(ctx, strings.Repeat("a", farcasterapi.MaxCastBytes+1), nil), qt.IsNotNil)
+	published := api.Published()
+	c.Assert(published, qt.HasLen, 2)
+	c.Assert(published[0].Author, qt.Equals, uint64(100))
+	c.Assert(published[0].Parent.Hash, qt.Equals, hash)
+	c.Assert(published[0].Hash, qt.Equals, replyHash)
+	c.Assert(published[0].Embeds, qt.DeepEquals, []string{"https://frame"})
+	c.Assert(hooked, qt.DeepEquals, published)
+
+	c.Assert(api.DirectMessage(ctx, "vote!", 2), qt.IsNil)
+	c.Assert(api.DirectMessages(), qt.DeepEquals, []*DirectMessage{{To: 2, Content: "vote!"}})
+	c.Assert(hookedDMs, qt.HasLen, 1)
+}
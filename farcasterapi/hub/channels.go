@@ -0,0 +1,415 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"go.vocdoni.io/dvote/log"
+)
+
+const (
+	// castsByParentPageSize is the number of casts requested in every page of
+	// the casts of a channel
+	castsByParentPageSize = 1000
+	// channelFollowersPageSize is the number of followers requested in every
+	// page of the followers of a channel
+	channelFollowersPageSize = 1000
+	// maxChannelSyncPages is the maximum number of pages of every source of
+	// members of a channel to request in a single sync, to limit the requests
+	// to the hub
+	maxChannelSyncPages = 100
+	// channelSyncTimeout is the maximum time spent syncing the members of a
+	// channel in a single sync, the interrupted syncs are resumed in the next
+	// one
+	channelSyncTimeout = 30 * time.Second
+	// channelFollowersTimeout is the timeout of every request to the channel
+	// followers API
+	channelFollowersTimeout = 15 * time.Second
+)
+
+// memberSource type identifies the source of the members of a channel.
+type memberSource string
+
+const (
+	// castMembers source contains the users that cast in the channel
+	castMembers memberSource = "casts"
+	// followerMembers source contains the users that follow the channel
+	followerMembers memberSource = "followers"
+)
+
+// channelIndex struct is a local index of the channels known by the hub API.
+// The hubs do not expose the channels nor their followers, so the members of
+// every channel are the users that cast in it, resolved from the casts whose
+// parent URL is the channel URL, and its followers, resolved from the channel
+// followers API if it is set. The index is synced incrementally from the
+// newest casts and follows of the channel, and it is also fed with the casts
+// of the hub events processed. The incremental syncs cannot detect the
+// unfollows nor the users that stopped casting, so the members of a channel
+// are the union of its casters and its followers and they never shrink,
+// unlike the followers returned by the Neynar API.
+type channelIndex struct {
+	mtx      sync.RWMutex
+	channels map[string]*indexedChannel
+}
+
+// indexedChannel struct contains the members of a channel and the sync state
+// of every source of members.
+type indexedChannel struct {
+	members map[uint64]struct{}
+	syncs   map[memberSource]memberSync
+}
+
+// memberSync struct contains the sync state of a source of members of a
+// channel: the timestamp of its newest item synced and, if the last sync was
+// interrupted, the point to resume it from.
+type memberSync struct {
+	lastSynced uint64
+	resume     *syncResume
+}
+
+// syncResume struct contains the token of the next page to sync of an
+// interrupted sync and the timestamp of the newest item synced by it.
+type syncResume struct {
+	pageToken string
+	newest    uint64
+}
+
+// channelMember struct represents a member of a channel found in a source,
+// with the timestamp of the item that makes it a member (a cast or a follow).
+type channelMember struct {
+	fid       uint64
+	timestamp uint64
+}
+
+// memberPageFn type is a function that returns a page of members of a
+// channel from a source, from the newest to the oldest, and the token of the
+// next page, if any.
+type memberPageFn func(ctx context.Context, pageToken string) ([]channelMember, string, error)
+
+// newChannelIndex creates a new empty channel index.
+func newChannelIndex() *channelIndex {
+	return &channelIndex{channels: map[string]*indexedChannel{}}
+}
+
+// add adds the member provided to the channel provided, creating it in the
+// index if it does not exist.
+func (ci *channelIndex) add(channelID string, fid uint64) {
+	ci.mtx.Lock()
+	defer ci.mtx.Unlock()
+	ch, ok := ci.channels[channelID]
+	if !ok {
+		ch = &indexedChannel{
+			members: map[uint64]struct{}{},
+			syncs:   map[memberSource]memberSync{},
+		}
+		ci.channels[channelID] = ch
+	}
+	ch.members[fid] = struct{}{}
+}
+
+// members returns the sorted members of the channel provided and if it is in
+// the index.
+func (ci *channelIndex) members(channelID string) ([]uint64, bool) {
+	ci.mtx.RLock()
+	defer ci.mtx.RUnlock()
+	ch, ok := ci.channels[channelID]
+	if !ok {
+		return nil, false
+	}
+	fids := make([]uint64, 0, len(ch.members))
+	for fid := range ch.members {
+		fids = append(fids, fid)
+	}
+	slices.Sort(fids)
+	return fids, true
+}
+
+// syncState returns the sync state of the source of members provided of the
+// channel provided.
+func (ci *channelIndex) syncState(channelID string, source memberSource) memberSync {
+	ci.mtx.RLock()
+	defer ci.mtx.RUnlock()
+	if ch, ok := ci.channels[channelID]; ok {
+		return ch.syncs[source]
+	}
+	return memberSync{}
+}
+
+// setSyncState sets the sync state of the source of members provided of the
+// channel provided, if it is in the index.
+func (ci *channelIndex) setSyncState(channelID string, source memberSource, state memberSync) {
+	ci.mtx.Lock()
+	defer ci.mtx.Unlock()
+	if ch, ok := ci.channels[channelID]; ok {
+		ch.syncs[source] = state
+	}
+}
+
+// find returns the IDs of the channels of the index that contain the query
+// provided, sorted.
+func (ci *channelIndex) find(query string) []string {
+	ci.mtx.RLock()
+	defer ci.mtx.RUnlock()
+	ids := []string{}
+	for id := range ci.channels {
+		if strings.Contains(id, strings.ToLower(query)) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// SetChannelFollowersEndpoint method sets the endpoint of the API used to get
+// the followers of the channels (e.g.
+// https://api.warpcast.com/v1/channel-followers), since the hubs do not expose
+// them. Until it is set, the members of the channels are only the users that
+// cast in them.
+func (h *Hub) SetChannelFollowersEndpoint(endpoint string) {
+	h.channelFollowersEndpoint = endpoint
+}
+
+// Channel method returns the channel with the given id, resolving its
+// members from the casts published in it and its followers. It returns a
+// farcasterapi.ErrChannelNotFound error if there are no members in the
+// channel.
+func (h *Hub) Channel(ctx context.Context, channelID string) (*farcasterapi.Channel, error) {
+	fids, err := h.syncChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	return h.channelInfo(channelID, len(fids)), nil
+}
+
+// ChannelFIDs method returns the FIDs of the members of the channel with the
+// given id, which are the users that cast in it and its followers, including
+// the users that unfollowed it or stopped casting in it since they were
+// indexed. It returns a farcasterapi.ErrChannelNotFound error if there are no
+// members in the channel. If a progress channel is provided, the completed progress is
+// sent to it, since the total number of members is unknown.
+func (h *Hub) ChannelFIDs(ctx context.Context, channelID string, progress chan int) ([]uint64, error) {
+	fids, err := h.syncChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	if progress != nil {
+		progress <- 100
+	}
+	return fids, nil
+}
+
+// ChannelExists method returns if the channel with the given id exists, that
+// is, if there is any cast published in it.
+func (h *Hub) ChannelExists(ctx context.Context, channelID string) (bool, error) {
+	if _, ok := h.channels.members(channelID); ok {
+		return true, nil
+	}
+	casts, _, err := h.castsByParent(ctx, farcasterapi.ChannelParentURL(channelID), "", 1)
+	if err != nil {
+		return false, fmt.Errorf("error checking channel existence: %w", err)
+	}
+	return len(casts) > 0, nil
+}
+
+// FindChannel method returns the channels of the local index whose id
+// contains the query provided. The hubs do not expose the admins of the
+// channels, so it returns a farcasterapi.ErrNotSupported error if an admin
+// FID is provided. If no channel is found, it returns a
+// farcasterapi.ErrNoDataFound error.
+func (h *Hub) FindChannel(ctx context.Context, query string, adminFid uint64) ([]*farcasterapi.Channel, error) {
+	if adminFid != 0 {
+		return nil, fmt.Errorf("hub api does not support channel admins: %w", farcasterapi.ErrNotSupported)
+	}
+	channels := []*farcasterapi.Channel{}
+	for _, id := range h.channels.find(query) {
+		fids, _ := h.channels.members(id)
+		channels = append(channels, h.channelInfo(id, len(fids)))
+	}
+	if len(channels) == 0 {
+		return nil, farcasterapi.ErrNoDataFound
+	}
+	return channels, nil
+}
+
+// channelInfo method composes the farcasterapi.Channel of the channel with
+// the id and number of members provided.
+func (h *Hub) channelInfo(channelID string, members int) *farcasterapi.Channel {
+	return &farcasterapi.Channel{
+		ID:        channelID,
+		Name:      channelID,
+		Followers: members,
+		URL:       farcasterapi.ChannelParentURL(channelID),
+	}
+}
+
+// indexCast method adds the author of the given cast add message to the
+// members of its channel in the local index, if it was published in a
+// channel.
+func (h *Hub) indexCast(m *hubMessage) {
+	if m == nil || m.Data == nil || m.Data.CastAddBody == nil {
+		return
+	}
+	if channelID := farcasterapi.ChannelFromParentURL(m.Data.CastAddBody.ParentURL); channelID != "" {
+		h.channels.add(channelID, m.Data.From)
+	}
+}
+
+// syncChannel method syncs the members of the channel provided in the local
+// index from the casts published in it and from its followers, if the channel
+// followers API is set, and returns the members of the channel. The sync is
+// bounded in time and pages, and the interrupted syncs are resumed in the
+// next one, so the members returned may be partial until the channel is fully
+// synced. It returns a farcasterapi.ErrChannelNotFound error if there are no
+// members in the channel.
+func (h *Hub) syncChannel(ctx context.Context, channelID string) ([]uint64, error) {
+	syncCtx, cancel := context.WithTimeout(ctx, channelSyncTimeout)
+	defer cancel()
+	parentURL := farcasterapi.ChannelParentURL(channelID)
+	if err := h.syncChannelMembers(syncCtx, channelID, castMembers,
+		func(ctx context.Context, pageToken string) ([]channelMember, string, error) {
+			casts, nextPageToken, err := h.castsByParent(ctx, parentURL, pageToken, castsByParentPageSize)
+			if err != nil {
+				return nil, "", err
+			}
+			members := []channelMember{}
+			for _, m := range casts {
+				if m.Data != nil && m.Data.Type == MESSAGE_TYPE_CAST_ADD {
+					members = append(members, channelMember{fid: m.Data.From, timestamp: m.Data.Timestamp})
+				}
+			}
+			return members, nextPageToken, nil
+		}); err != nil {
+		return nil, err
+	}
+	if h.channelFollowersEndpoint != "" {
+		if err := h.syncChannelMembers(syncCtx, channelID, followerMembers,
+			func(ctx context.Context, cursor string) ([]channelMember, string, error) {
+				return h.channelFollowers(ctx, channelID, cursor)
+			}); err != nil {
+			return nil, err
+		}
+	}
+	fids, ok := h.channels.members(channelID)
+	if !ok {
+		return nil, farcasterapi.ErrChannelNotFound
+	}
+	return fids, nil
+}
+
+// syncChannelMembers method syncs the members of the channel provided in the
+// local index from the source provided, from the newest to the oldest, until
+// it reaches the items already synced. If the sync reaches the maximum number
+// of pages or the context deadline, it is interrupted and the next sync
+// resumes it from the last page requested.
+func (h *Hub) syncChannelMembers(ctx context.Context, channelID string, source memberSource, page memberPageFn) error {
+	state := h.channels.syncState(channelID, source)
+	pageToken, newest := "", uint64(0)
+	if state.resume != nil {
+		pageToken, newest = state.resume.pageToken, state.resume.newest
+	}
+	for i := 0; i < maxChannelSyncPages; i++ {
+		members, nextPageToken, err := page(ctx, pageToken)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				break
+			}
+			return fmt.Errorf("error syncing channel %s %s: %w", channelID, source, err)
+		}
+		reachedSynced := false
+		for _, m := range members {
+			if m.timestamp < state.lastSynced {
+				reachedSynced = true
+				break
+			}
+			h.channels.add(channelID, m.fid)
+			newest = max(newest, m.timestamp)
+		}
+		if reachedSynced || nextPageToken == "" {
+			h.channels.setSyncState(channelID, source, memberSync{lastSynced: max(state.lastSynced, newest)})
+			return nil
+		}
+		pageToken = nextPageToken
+	}
+	log.Warnw("channel sync interrupted, it will be resumed in the next one",
+		"channel", channelID, "source", source)
+	h.channels.setSyncState(channelID, source, memberSync{
+		lastSynced: state.lastSynced,
+		resume:     &syncResume{pageToken: pageToken, newest: newest},
+	})
+	return nil
+}
+
+// channelFollowers method returns a page of the followers of the channel
+// provided from the channel followers API, from the newest to the oldest,
+// and the cursor of the next page, if any.
+func (h *Hub) channelFollowers(ctx context.Context, channelID, cursor string) ([]channelMember, string, error) {
+	internalCtx, cancel := context.WithTimeout(ctx, channelFollowersTimeout)
+	defer cancel()
+	uri := fmt.Sprintf("%s?channelId=%s&limit=%d&cursor=%s", h.channelFollowersEndpoint,
+		url.QueryEscape(channelID), channelFollowersPageSize, url.QueryEscape(cursor))
+	req, err := http.NewRequestWithContext(internalCtx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating request: %w", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("error downloading channel followers: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("error downloading channel followers: %s", res.Status)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading response body: %w", err)
+	}
+	followers := &channelFollowersResponse{}
+	if err := json.Unmarshal(body, followers); err != nil {
+		return nil, "", fmt.Errorf("error unmarshalling channel followers: %w", err)
+	}
+	members := make([]channelMember, 0, len(followers.Result.Users))
+	for _, user := range followers.Result.Users {
+		members = append(members, channelMember{fid: user.FID, timestamp: user.FollowedAt})
+	}
+	return members, followers.Next.Cursor, nil
+}
+
+// castsByParent method returns a page of the casts whose parent is the URL
+// provided, from the newest to the oldest, and the token of the next page, if
+// any.
+func (h *Hub) castsByParent(ctx context.Context, parentURL, pageToken string, pageSize int) ([]*hubMessage, string, error) {
+	internalCtx, cancel := context.WithTimeout(ctx, getCastTimeout)
+	defer cancel()
+	uri := fmt.Sprintf(ENDPOINT_CASTS_BY_PARENT, url.QueryEscape(parentURL), pageSize, url.QueryEscape(pageToken))
+	req, err := h.newRequest(internalCtx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating request: %w", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("error downloading casts: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("error downloading casts: %s", res.Status)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading response body: %w", err)
+	}
+	casts := &hubMessageResponse{}
+	if err := json.Unmarshal(body, casts); err != nil {
+		return nil, "", fmt.Errorf("error unmarshalling casts: %w", err)
+	}
+	return casts.Messages, casts.NextPageToken, nil
+}
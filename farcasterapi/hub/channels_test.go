@@ -0,0 +1,174 @@
+package hub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"github.com/vocdoni/vote-frame/farcasterapi/hub/hubtest"
+)
+
+func TestChannels(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	fakeHub := hubtest.NewFakeHub(1)
+	defer fakeHub.Close()
+
+	h, err := NewHubAPI(fakeHub.Endpoint(), nil)
+	c.Assert(err, qt.IsNil)
+
+	vocdoniURL := farcasterapi.ChannelParentURL("vocdoni")
+	fakeHub.AddChannelCast(1, "gm", vocdoniURL)
+	fakeHub.AddChannelCast(2, "gm", vocdoniURL)
+	fakeHub.AddChannelCast(1, "gm again", vocdoniURL)
+	fakeHub.AddChannelCast(3, "gm", farcasterapi.ChannelParentURL("degen"))
+	fakeHub.AddCast(4, "not in a channel")
+
+	// the members of the channel are the authors of its casts
+	exists, err := h.ChannelExists(ctx, "vocdoni")
+	c.Assert(err, qt.IsNil)
+	c.Assert(exists, qt.IsTrue)
+	progress := make(chan int, 1)
+	fids, err := h.ChannelFIDs(ctx, "vocdoni", progress)
+	c.Assert(err, qt.IsNil)
+	c.Assert(fids, qt.DeepEquals, []uint64{1, 2})
+	c.Assert(<-progress, qt.Equals, 100)
+
+	// the channel is synced incrementally
+	fakeHub.AddChannelCast(5, "gm", vocdoniURL)
+	channel, err := h.Channel(ctx, "vocdoni")
+	c.Assert(err, qt.IsNil)
+	c.Assert(channel.Followers, qt.Equals, 3)
+	c.Assert(channel.URL, qt.Equals, vocdoniURL)
+
+	// the unknown channels are not found
+	exists, err = h.ChannelExists(ctx, "unknown")
+	c.Assert(err, qt.IsNil)
+	c.Assert(exists, qt.IsFalse)
+	_, err = h.Channel(ctx, "unknown")
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrChannelNotFound)
+
+	// the channels are found in the local index
+	channels, err := h.FindChannel(ctx, "voc", 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(channels, qt.HasLen, 1)
+	c.Assert(channels[0].ID, qt.Equals, "vocdoni")
+	_, err = h.FindChannel(ctx, "degen", 0)
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrNoDataFound)
+	_, err = h.FindChannel(ctx, "voc", 1)
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrNotSupported)
+}
+
+func TestChannelIndexFromEvents(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	fakeHub := hubtest.NewFakeHub(1)
+	defer fakeHub.Close()
+
+	h, err := NewHubAPI(fakeHub.Endpoint(), nil)
+	c.Assert(err, qt.IsNil)
+	h.fid = testBotFID
+
+	fakeHub.AddChannelCast(1, "gm", farcasterapi.ChannelParentURL("degen"))
+	fakeHub.AddCast(2, " poll", testBotFID)
+	_, _, err = h.MentionEvents(ctx, 1)
+	c.Assert(err, qt.IsNil)
+
+	channels, err := h.FindChannel(ctx, "degen", 0)
+	c.Assert(err, qt.IsNil)
+	c.Assert(channels, qt.HasLen, 1)
+	c.Assert(channels[0].Followers, qt.Equals, 1)
+}
+
+func TestChannelFollowers(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	fakeHub := hubtest.NewFakeHub(1)
+	defer fakeHub.Close()
+
+	h, err := NewHubAPI(fakeHub.Endpoint(), nil)
+	c.Assert(err, qt.IsNil)
+
+	// without the channel followers API, the followers are not members
+	fakeHub.AddChannelCast(1, "gm", farcasterapi.ChannelParentURL("vocdoni"))
+	fakeHub.AddChannelFollower("vocdoni", 2)
+	fids, err := h.ChannelFIDs(ctx, "vocdoni", nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(fids, qt.DeepEquals, []uint64{1})
+
+	// the followers are members, incrementally synced, even if they never
+	// cast in the channel
+	h.SetChannelFollowersEndpoint(fakeHub.ChannelFollowersEndpoint())
+	fids, err = h.ChannelFIDs(ctx, "vocdoni", nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(fids, qt.DeepEquals, []uint64{1, 2})
+	fakeHub.AddChannelFollower("vocdoni", 3)
+	fids, err = h.ChannelFIDs(ctx, "vocdoni", nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(fids, qt.DeepEquals, []uint64{1, 2, 3})
+
+	fakeHub.AddChannelFollower("followed", 4)
+	channel, err := h.Channel(ctx, "followed")
+	c.Assert(err, qt.IsNil)
+	c.Assert(channel.Followers, qt.Equals, 1)
+}
+
+func TestChannelSyncResume(t *testing.T) {
+	c := qt.New(t)
+
+	h, err := NewHubAPI("", nil)
+	c.Assert(err, qt.IsNil)
+
+	// the source serves a page per call, and blocks in the third one until
+	// the sync deadline, if any
+	pages := [][]channelMember{
+		{{fid: 4, timestamp: 4}, {fid: 3, timestamp: 3}},
+		{{fid: 2, timestamp: 2}},
+		{{fid: 1, timestamp: 1}},
+	}
+	block := true
+	page := func(ctx context.Context, pageToken string) ([]channelMember, string, error) {
+		i := 0
+		if pageToken != "" {
+			i = int(pageToken[0] - '0')
+		}
+		if i == 2 && block {
+			<-ctx.Done()
+			return nil, "", ctx.Err()
+		}
+		next := ""
+		if i < len(pages)-1 {
+			next = string(rune('0' + i + 1))
+		}
+		return pages[i], next, nil
+	}
+
+	// the sync is interrupted by the deadline with the members synced so far
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	c.Assert(h.syncChannelMembers(ctx, "vocdoni", castMembers, page), qt.IsNil)
+	fids, _ := h.channels.members("vocdoni")
+	c.Assert(fids, qt.DeepEquals, []uint64{2, 3, 4})
+	state := h.channels.syncState("vocdoni", castMembers)
+	c.Assert(state.lastSynced, qt.Equals, uint64(0))
+	c.Assert(state.resume, qt.IsNotNil)
+	c.Assert(*state.resume, qt.Equals, syncResume{pageToken: "2", newest: 4})
+
+	// the next sync resumes it from the interrupted page
+	block = false
+	c.Assert(h.syncChannelMembers(context.Background(), "vocdoni", castMembers, page), qt.IsNil)
+	fids, _ = h.channels.members("vocdoni")
+	c.Assert(fids, qt.DeepEquals, []uint64{1, 2, 3, 4})
+	state = h.channels.syncState("vocdoni", castMembers)
+	c.Assert(state.lastSynced, qt.Equals, uint64(4))
+	c.Assert(state.resume, qt.IsNil)
+
+	// the other errors are returned
+	_, err = h.syncChannel(context.Background(), "unreachable")
+	c.Assert(err, qt.IsNotNil)
+}
@@ -0,0 +1,115 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"go.vocdoni.io/dvote/log"
+)
+
+const (
+	// farcasterEpochMillis is the farcaster epoch in milliseconds, used to
+	// compose the event IDs
+	farcasterEpochMillis = farcasterEpoch * 1000
+	// eventSequenceBits is the number of bits of the event IDs reserved for
+	// the sequence of the events in the same millisecond
+	eventSequenceBits = 12
+)
+
+// EventIDFromTime returns the ID of the first hub event of the given time.
+// The hub event IDs are composed of the milliseconds since the farcaster
+// epoch and a sequence number, so they can be used as a cursor of the events
+// from a point in time.
+func EventIDFromTime(t time.Time) uint64 {
+	millis := uint64(t.UnixMilli())
+	if millis < farcasterEpochMillis {
+		return 0
+	}
+	return (millis - farcasterEpochMillis) << eventSequenceBits
+}
+
+// MentionEvents method returns the mentions of the bot included in the next
+// page of hub events from the given event ID, and the ID of the next event to
+// request. Only the merge message events of cast add messages that mention
+// the bot are returned. If the event ID is zero, it starts from the current
+// time. It implements the farcasterapi.MentionsStreamer interface.
+func (h *Hub) MentionEvents(ctx context.Context, fromEventID uint64) ([]*farcasterapi.MentionEvent, uint64, error) {
+	if h.fid == 0 {
+		return nil, 0, fmt.Errorf("no farcaster user set")
+	}
+	if fromEventID == 0 {
+		fromEventID = EventIDFromTime(time.Now())
+	}
+	internalCtx, cancel := context.WithTimeout(ctx, getEventsTimeout)
+	defer cancel()
+	uri := fmt.Sprintf(ENDPOINT_EVENTS, fromEventID)
+	req, err := h.newRequest(internalCtx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fromEventID, fmt.Errorf("error creating request: %w", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fromEventID, fmt.Errorf("error downloading events: %w", err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			log.Error("error closing response body")
+		}
+	}()
+	if res.StatusCode != http.StatusOK {
+		return nil, fromEventID, fmt.Errorf("error downloading events: %s", res.Status)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fromEventID, fmt.Errorf("error reading response body: %w", err)
+	}
+	events := &hubEventsResponse{}
+	if err := json.Unmarshal(body, events); err != nil {
+		return nil, fromEventID, fmt.Errorf("error unmarshalling events: %w", err)
+	}
+	// if the page is empty, keep the cursor to request the same event again
+	if len(events.Events) == 0 {
+		return nil, fromEventID, nil
+	}
+	mentions := []*farcasterapi.MentionEvent{}
+	for _, e := range events.Events {
+		// index the channel casts and the verifications to know the members
+		// of the channels and the owners of the verified addresses
+		if e.Type == EVENT_TYPE_MERGE_MESSAGE && e.MergeMessageBody != nil {
+			h.indexCast(e.MergeMessageBody.Message)
+			h.indexVerification(e.MergeMessageBody.Message)
+		}
+		if !h.isMentionEvent(e) {
+			continue
+		}
+		mentions = append(mentions, &farcasterapi.MentionEvent{
+			EventID: e.ID,
+			Message: h.newAPIMessage(e.MergeMessageBody.Message),
+		})
+	}
+	nextEventID := events.NextPageEventID
+	if last := events.Events[len(events.Events)-1].ID; nextEventID <= last {
+		nextEventID = last + 1
+	}
+	return mentions, nextEventID, nil
+}
+
+// isMentionEvent method returns if the given hub event merges a cast add
+// message with text that mentions the bot.
+func (h *Hub) isMentionEvent(e *hubEvent) bool {
+	if e.Type != EVENT_TYPE_MERGE_MESSAGE || e.MergeMessageBody == nil {
+		return false
+	}
+	m := e.MergeMessageBody.Message
+	if m == nil || m.Data == nil || m.Data.Type != MESSAGE_TYPE_CAST_ADD ||
+		m.Data.CastAddBody == nil || m.Data.CastAddBody.Text == "" {
+		return false
+	}
+	return slices.Contains(m.Data.CastAddBody.Mentions, h.fid)
+}
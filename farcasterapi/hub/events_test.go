@@ -0,0 +1,65 @@
+package hub
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/farcasterapi/hub/hubtest"
+)
+
+const testBotFID = 1000
+
+func TestMentionEvents(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	fakeHub := hubtest.NewFakeHub(1)
+	defer fakeHub.Close()
+	fakeHub.PageSize = 3
+
+	h, err := NewHubAPI(fakeHub.Endpoint(), nil)
+	c.Assert(err, qt.IsNil)
+	h.fid = testBotFID
+
+	firstID, firstHash := fakeHub.AddCast(1, " first poll", testBotFID)
+	fakeHub.AddCast(2, "not a mention")
+	fakeHub.AddPruneEvent()
+	fakeHub.AddCast(3, "mentions someone else", 2000)
+	lastID, lastHash := fakeHub.AddCast(4, " second poll", testBotFID)
+
+	// first page only includes the first mention
+	mentions, next, err := h.MentionEvents(ctx, 1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(mentions, qt.HasLen, 1)
+	c.Assert(mentions[0].EventID, qt.Equals, firstID)
+	c.Assert(mentions[0].Message.Hash, qt.Equals, firstHash)
+	c.Assert(mentions[0].Message.Author, qt.Equals, uint64(1))
+	c.Assert(mentions[0].Message.Content, qt.Equals, " first poll")
+	c.Assert(next, qt.Equals, uint64(4))
+
+	// resuming from the cursor returns the rest of the mentions
+	mentions, next, err = h.MentionEvents(ctx, next)
+	c.Assert(err, qt.IsNil)
+	c.Assert(mentions, qt.HasLen, 1)
+	c.Assert(mentions[0].EventID, qt.Equals, lastID)
+	c.Assert(mentions[0].Message.Hash, qt.Equals, lastHash)
+	c.Assert(next, qt.Equals, lastID+1)
+
+	// no new events keeps the cursor
+	mentions, next, err = h.MentionEvents(ctx, next)
+	c.Assert(err, qt.IsNil)
+	c.Assert(mentions, qt.HasLen, 0)
+	c.Assert(next, qt.Equals, lastID+1)
+	c.Assert(fakeHub.Requests(), qt.Equals, 3)
+}
+
+func TestEventIDFromTime(t *testing.T) {
+	c := qt.New(t)
+
+	epoch := time.Unix(int64(farcasterEpoch), 0)
+	c.Assert(EventIDFromTime(epoch), qt.Equals, uint64(0))
+	c.Assert(EventIDFromTime(epoch.Add(-time.Hour)), qt.Equals, uint64(0))
+	c.Assert(EventIDFromTime(epoch.Add(time.Millisecond)), qt.Equals, uint64(1)<<eventSequenceBits)
+}
@@ -9,13 +9,47 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/vocdoni/vote-frame/farcasterapi"
 	hubproto "github.com/vocdoni/vote-frame/farcasterapi/hub/proto"
 	"github.com/zeebo/blake3"
+	"go.vocdoni.io/dvote/log"
 	"google.golang.org/protobuf/proto"
 )
 
 var mentionRgx = regexp.MustCompile(`(@\S+)`)
 
+// newAPIMessage method composes the farcasterapi.APIMessage of the given cast
+// add message, including its content with the mentions, its embeds, its
+// parent and its channel.
+func (h *Hub) newAPIMessage(m *hubMessage) *farcasterapi.APIMessage {
+	content, err := h.composeCastContent(m.Data.CastAddBody)
+	if err != nil {
+		log.Error(err)
+	}
+	// parse the embeds of the message to be included
+	embeds := []string{}
+	for _, e := range m.Data.CastAddBody.Embeds {
+		embeds = append(embeds, e.Url)
+	}
+	// check if the message has a parent
+	var parent *farcasterapi.ParentAPIMessage
+	if m.Data.CastAddBody.ParentCast != nil {
+		parent = &farcasterapi.ParentAPIMessage{
+			FID:  m.Data.CastAddBody.ParentCast.FID,
+			Hash: m.Data.CastAddBody.ParentCast.Hash,
+		}
+	}
+	return &farcasterapi.APIMessage{
+		IsMention: true,
+		Content:   content,
+		Author:    m.Data.From,
+		Hash:      m.HexHash,
+		Parent:    parent,
+		Embeds:    embeds,
+		Channel:   farcasterapi.ChannelFromParentURL(m.Data.CastAddBody.ParentURL),
+	}
+}
+
 // newAddCastBody method creates a new cast add body with the given content, the
 // mentions fids and the embeds. It returns the cast add body and an error. It
 // returns an error if the farcaster user is not set or there is an error
@@ -54,14 +88,14 @@ func (h *Hub) newAddCastBody(content string, mentionFIDs []uint64, embeds ...str
 }
 
 // buildAndSignAddCastBody method builds and signs the given cast add body. It
-// returns the message bytes and an error. It creates the message data with the
+// returns the message bytes, the message hash and an error. It creates the message data with the
 // message type, the bot FID, the current timestamp, the network, and the cast
 // add body. It marshals the message data and calculates the hash of the message
 // data. It creates the message with the hash scheme, the hash and the signature
 // scheme. It signs the message with the private key and sets the signature and
 // the signer to the message. It marshals the message and returns the message
-// bytes.
-func (h *Hub) buildAndSignAddCastBody(castAddBody *hubproto.CastAddBody) ([]byte, error) {
+// bytes with its hash.
+func (h *Hub) buildAndSignAddCastBody(castAddBody *hubproto.CastAddBody) ([]byte, []byte, error) {
 	// compose the message data with the message type, the bot FID, the current
 	// timestamp, the network, and the cast add body
 	msgData := &hubproto.MessageData{
@@ -74,7 +108,7 @@ func (h *Hub) buildAndSignAddCastBody(castAddBody *hubproto.CastAddBody) ([]byte
 	// marshal the message data
 	msgDataBytes, err := proto.Marshal(msgData)
 	if err != nil {
-		return nil, fmt.Errorf("error marshalling message data: %s", err)
+		return nil, nil, fmt.Errorf("error marshalling message data: %s", err)
 	}
 	// calculate the hash of the message data
 	hasher := blake3.New()
@@ -99,9 +133,9 @@ func (h *Hub) buildAndSignAddCastBody(castAddBody *hubproto.CastAddBody) ([]byte
 	// marshal the message
 	msgBytes, err := proto.Marshal(msg)
 	if err != nil {
-		return nil, fmt.Errorf("error marshalling message: %s", err)
+		return nil, nil, fmt.Errorf("error marshalling message: %s", err)
 	}
-	return msgBytes, nil
+	return msgBytes, hash, nil
 }
 
 // composeCastContent method composes the cast content with the given body. It
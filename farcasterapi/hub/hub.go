@@ -27,9 +27,12 @@ const (
 	ENDPOINT_USER_FOLLOWERs        = "linksByTargetFid?target_fid=%d"
 	ENDPOINT_VERIFICATIONS         = "verificationsByFid?fid=%d"
 	ENDPOINT_IDREGISTRY_BY_ADDRESS = "onChainIdRegistryEventByAddress?address=%s"
+	ENDPOINT_EVENTS                = "events?from_event_id=%d"
+	ENDPOINT_CASTS_BY_PARENT       = "castsByParent?url=%s&pageSize=%d&reverse=true&pageToken=%s"
 	// timeouts
 	getCastTimeout          = 10 * time.Second
 	getCastByMentionTimeout = 15 * time.Second
+	getEventsTimeout        = 15 * time.Second
 	submitMessageTimeout    = 5 * time.Minute
 	userdataTimeout         = 15 * time.Second
 	userFollowersTimeout    = 15 * time.Second
@@ -41,6 +44,8 @@ const (
 	MESSAGE_TYPE_USERDATA_ADD = "MESSAGE_TYPE_USER_DATA_ADD"
 	MESSAGE_TYPE_REACTION_ADD = "MESSAGE_TYPE_REACTION_ADD"
 	MESSAGE_TYPE_RECAST       = "REACTION_TYPE_RECAST"
+	// event types
+	EVENT_TYPE_MERGE_MESSAGE = "HUB_EVENT_TYPE_MERGE_MESSAGE"
 	// user data types
 	USERDATA_TYPE_USERNAME = "USER_DATA_TYPE_USERNAME"
 	// other constants
@@ -54,13 +59,24 @@ type Hub struct {
 	privKey  []byte
 	endpoint string
 	auth     map[string]string
+	channels *channelIndex
+	// channelFollowersEndpoint is the endpoint of the API to get the
+	// followers of the channels, since the hubs do not expose them
+	channelFollowersEndpoint string
+	// verified addresses and signers
+	verifications   *verificationsIndex
+	signersProvider appKeysProvider
 }
 
 // Init initializes the API Hub with the given arguments.
 // apiKeys must be a slice of strings with an even number of elements, where
 // each pair of elements is a header and a key.
 func NewHubAPI(apiEndpoint string, apiKeys []string) (*Hub, error) {
-	h := &Hub{endpoint: apiEndpoint}
+	h := &Hub{
+		endpoint:      apiEndpoint,
+		channels:      newChannelIndex(),
+		verifications: newVerificationsIndex(),
+	}
 	// take the apikeys by group of two and set them as header/key
 	if len(apiKeys)%2 != 0 {
 		return nil, fmt.Errorf("invalid number of api keys")
@@ -141,32 +157,7 @@ func (h *Hub) LastMentions(ctx context.Context, timestamp uint64) ([]*farcastera
 			continue
 		}
 		if m.Data.Timestamp > timestamp {
-			content, err := h.composeCastContent(m.Data.CastAddBody)
-			if err != nil {
-				log.Error(err)
-			}
-			// parse the embeds of the message to be included
-			embeds := []string{}
-			if len(m.Data.CastAddBody.Embeds) > 0 {
-				for _, e := range m.Data.CastAddBody.Embeds {
-					embeds = append(embeds, e.Url)
-				}
-			}
-			var parent *farcasterapi.ParentAPIMessage = nil
-			if m.Data.CastAddBody.ParentCast != nil {
-				parent = &farcasterapi.ParentAPIMessage{
-					FID:  m.Data.CastAddBody.ParentCast.FID,
-					Hash: m.Data.CastAddBody.ParentCast.Hash,
-				}
-			}
-			messages = append(messages, &farcasterapi.APIMessage{
-				IsMention: true,
-				Content:   content,
-				Author:    m.Data.From,
-				Hash:      m.HexHash,
-				Parent:    parent,
-				Embeds:    embeds,
-			})
+			messages = append(messages, h.newAPIMessage(m))
 			if m.Data.Timestamp > lastTimestamp {
 				lastTimestamp = m.Data.Timestamp
 			}
@@ -215,36 +206,8 @@ func (h *Hub) GetCast(ctx context.Context, fid uint64, hash string) (*farcastera
 	if msg.Data.Type != MESSAGE_TYPE_CAST_ADD || msg.Data.CastAddBody == nil {
 		return nil, fmt.Errorf("no valid cast")
 	}
-	// compose the content of the message
-	content, err := h.composeCastContent(msg.Data.CastAddBody)
-	if err != nil {
-		log.Error(err)
-	}
-	// parse the embeds of the message to be included
-	embeds := []string{}
-	if len(msg.Data.CastAddBody.Embeds) > 0 {
-		for _, e := range msg.Data.CastAddBody.Embeds {
-			embeds = append(embeds, e.Url)
-		}
-	}
-	// check if the message has a parent
-	var parent *farcasterapi.ParentAPIMessage = nil
-	if msg.Data.CastAddBody.ParentCast != nil {
-		parent = &farcasterapi.ParentAPIMessage{
-			FID:  msg.Data.CastAddBody.ParentCast.FID,
-			Hash: msg.Data.CastAddBody.ParentCast.Hash,
-		}
-	}
 	// compose the api message
-	message := &farcasterapi.APIMessage{
-		IsMention: true,
-		Content:   content,
-		Author:    msg.Data.From,
-		Hash:      msg.HexHash,
-		Parent:    parent,
-		Embeds:    embeds,
-	}
-	return message, nil
+	return h.newAPIMessage(msg), nil
 }
 
 func (h *Hub) Publish(ctx context.Context, content string, mentionFIDs []uint64, embeds ...string) error {
@@ -258,7 +221,7 @@ func (h *Hub) Publish(ctx context.Context, content string, mentionFIDs []uint64,
 	if err != nil {
 		return fmt.Errorf("error decomposing content: %s", err)
 	}
-	msgBytes, err := h.buildAndSignAddCastBody(castBody)
+	msgBytes, _, err := h.buildAndSignAddCastBody(castBody)
 	if err != nil {
 		return fmt.Errorf("error building and signing cast body: %s", err)
 	}
@@ -287,27 +250,27 @@ func (h *Hub) Publish(ctx context.Context, content string, mentionFIDs []uint64,
 }
 
 // Reply method sends a reply to the given targetFid and targetHash with the
-// given content.
+// given content. It returns the hash of the reply cast.
 func (h *Hub) Reply(ctx context.Context, targetMsg *farcasterapi.APIMessage,
 	content string, mentionFIDs []uint64, embeds ...string,
-) error {
+) (string, error) {
 	log.Infow("replying to cast", "msg", content, "embeds", embeds)
 	if targetMsg == nil {
-		return fmt.Errorf("invalid target message")
+		return "", fmt.Errorf("invalid target message")
 	}
 	// check if the content is too long
 	if len([]byte(content)) > farcasterapi.MaxCastBytes {
-		return fmt.Errorf("content is too long")
+		return "", fmt.Errorf("content is too long")
 	}
 	castAdd, err := h.newAddCastBody(content, mentionFIDs, embeds...)
 	if err != nil {
-		return fmt.Errorf("error creating cast add body: %s", err)
+		return "", fmt.Errorf("error creating cast add body: %s", err)
 	}
 	// create the cast as a reply to the message with the parentFID provided
 	// and the desired text
 	bTargetHash, err := hex.DecodeString(strings.TrimPrefix(targetMsg.Hash, "0x"))
 	if err != nil {
-		return fmt.Errorf("error decoding target hash: %s", err)
+		return "", fmt.Errorf("error decoding target hash: %s", err)
 	}
 	castAdd.Parent = &hubproto.CastAddBody_ParentCastId{
 		ParentCastId: &hubproto.CastId{
@@ -315,9 +278,9 @@ func (h *Hub) Reply(ctx context.Context, targetMsg *farcasterapi.APIMessage,
 			Hash: bTargetHash,
 		},
 	}
-	msgBytes, err := h.buildAndSignAddCastBody(castAdd)
+	msgBytes, hash, err := h.buildAndSignAddCastBody(castAdd)
 	if err != nil {
-		return fmt.Errorf("error building message: %s", err)
+		return "", fmt.Errorf("error building message: %s", err)
 	}
 	// create a new context with a timeout
 	internalCtx, cancel := context.WithTimeout(ctx, submitMessageTimeout)
@@ -325,22 +288,22 @@ func (h *Hub) Reply(ctx context.Context, targetMsg *farcasterapi.APIMessage,
 	// submit the message to the API endpoint
 	req, err := h.newRequest(internalCtx, http.MethodPost, ENDPOINT_SUBMIT_MESSAGE, bytes.NewBuffer(msgBytes))
 	if err != nil {
-		return fmt.Errorf("error creating request: %s", err)
+		return "", fmt.Errorf("error creating request: %s", err)
 	}
 	req.Header.Set("Content-Type", "application/octet-stream")
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("error submitting the message: %s", err)
+		return "", fmt.Errorf("error submitting the message: %s", err)
 	}
 	if res.StatusCode != http.StatusOK {
 		// read the response body
 		body, err := io.ReadAll(res.Body)
 		if err != nil {
-			return fmt.Errorf("error reading response body: %s", err)
+			return "", fmt.Errorf("error reading response body: %s", err)
 		}
-		return fmt.Errorf("error submitting the message: %s", string(body))
+		return "", fmt.Errorf("error submitting the message: %s", string(body))
 	}
-	return nil
+	return "0x" + hex.EncodeToString(hash), nil
 }
 
 // RecastsFIDs method returns the fids of the users that recast the message with
@@ -491,12 +454,13 @@ func (h *Hub) UserDataByFID(ctx context.Context, fid uint64) (*farcasterapi.User
 	for _, msg := range verificationsData.Messages {
 		// if no data or verification data is found, skip. If the message data
 		// type is not the one we are looking for, skip
-		if msg.Data == nil || msg.Data.Type != MESSAGE_TYPE_VERIFICATION || msg.Data.Verification == nil || msg.Data.Signer == "" {
+		if msg.Data == nil || msg.Data.Type != MESSAGE_TYPE_VERIFICATION || msg.Data.Verification == nil || msg.Signer == "" {
 			log.Warnw("invalid verification message", "msg", msg)
 			continue
 		}
 		verifications = append(verifications, msg.Data.Verification.Address)
-		signersMap[msg.Data.Signer] = struct{}{}
+		h.verifications.add(msg.Data.Verification.Address, fid)
+		signersMap[msg.Signer] = struct{}{}
 	}
 	signers := []string{}
 	for signer := range signersMap {
@@ -511,13 +475,6 @@ func (h *Hub) UserDataByFID(ctx context.Context, fid uint64) (*farcasterapi.User
 	}, nil
 }
 
-// UserDataByVerificationAddress method returns the user data for the given
-// verification addresses. It returns a slice of user data and an error. Hub
-// does not implement this method.
-func (h *Hub) UserDataByVerificationAddress(ctx context.Context, address []string) ([]*farcasterapi.Userdata, error) {
-	return nil, fmt.Errorf("not implemented")
-}
-
 // UserFollowers method returns the FIDs of the followers of the user with the
 // given id. If something goes wrong, it returns an error.
 func (h *Hub) UserFollowers(ctx context.Context, fid uint64) ([]uint64, error) {
@@ -560,42 +517,16 @@ func (h *Hub) UserFollowers(ctx context.Context, fid uint64) ([]uint64, error) {
 	return followersFids, nil
 }
 
-// Channel method is not supported by the Hub API. It returns an error.
-func (h *Hub) Channel(ctx context.Context, channelID string) (*farcasterapi.Channel, error) {
-	return nil, fmt.Errorf("hub api does not support channels yet")
-}
-
-// ChannelFIDs method is not supported by the Hub API. It returns an error.
-func (h *Hub) ChannelFIDs(ctx context.Context, channelID string, _ chan int) ([]uint64, error) {
-	return nil, fmt.Errorf("hub api does not support channels yet")
-}
-
-// ChannelExists method is not supported by the Hub API. It returns an error.
-func (h *Hub) ChannelExists(ctx context.Context, channelID string) (bool, error) {
-	return false, fmt.Errorf("hub api does not support channels yet")
-}
-
-// ListChannels method is not supported by the Hub API. It returns an error.
-func (h *Hub) FindChannel(ctx context.Context, query string, adminFid uint64) ([]*farcasterapi.Channel, error) {
-	return nil, fmt.Errorf("hub api does not support channels yet")
-}
-
 // DirectMessage method sends a direct message to the user with the given fid.
 // If something goes wrong, it returns an error.
 func (h *Hub) DirectMessage(ctx context.Context, content string, to uint64) error {
 	// not implemented yet, it will be available when the direct message
 	// decentralization roadmap is completed
-	return fmt.Errorf("not implemented")
+	return farcasterapi.ErrNotSupported
 }
 
 // WebhookHandler method handles the incoming webhooks. Hub does not implement
 // this method.
 func (h *Hub) WebhookHandler(_ []byte) error {
-	return fmt.Errorf("not implemented")
-}
-
-// SignersFromFID method returns the signers for the given FID. It returns a
-// slice of signers and an error. Hub does not implement this method.
-func (h *Hub) SignersFromFID(fid uint64) ([]string, error) {
-	return nil, fmt.Errorf("not implemented")
+	return farcasterapi.ErrNotSupported
 }
@@ -0,0 +1,433 @@
+// hubtest package provides a local fake Farcaster Hub to be used in tests. It
+// serves the events, the casts by parent and the users endpoints of the HTTP
+// API of a hub from in-memory events and users that can be populated by the
+// tests. It also serves the channel followers endpoint of the Warpcast API.
+package hubtest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultPageSize is the default number of events returned by the fake hub
+	// in every page.
+	DefaultPageSize = 100
+	// mergeMessageEventType is the type of the events that merge a message
+	mergeMessageEventType = "HUB_EVENT_TYPE_MERGE_MESSAGE"
+	// pruneMessageEventType is the type of the events that prune a message
+	pruneMessageEventType = "HUB_EVENT_TYPE_PRUNE_MESSAGE"
+	// castAddMessageType is the type of the cast add messages
+	castAddMessageType = "MESSAGE_TYPE_CAST_ADD"
+	// verificationMessageType is the type of the verification messages
+	verificationMessageType = "MESSAGE_TYPE_VERIFICATION_ADD_ETH_ADDRESS"
+	// testSigner is the signer of the verification messages
+	testSigner = "0x0000000000000000000000000000000000000000000000000000000000000001"
+)
+
+// FakeHub struct represents a local fake Farcaster Hub that serves some
+// endpoints of the HTTP API from an in-memory list of events and users. The
+// events get consecutive IDs from the first ID provided.
+type FakeHub struct {
+	PageSize int
+
+	mtx       sync.Mutex
+	server    *httptest.Server
+	events    []map[string]any
+	users     map[uint64]*fakeUser
+	followers map[string][]uint64
+	nextID    uint64
+	requests  int
+}
+
+// fakeUser struct represents a user of the fake hub.
+type fakeUser struct {
+	username       string
+	custodyAddress string
+	verifications  []string
+}
+
+// NewFakeHub creates and starts a new fake hub whose events IDs start from
+// the ID provided. It must be closed after using it.
+func NewFakeHub(firstEventID uint64) *FakeHub {
+	f := &FakeHub{
+		PageSize:  DefaultPageSize,
+		users:     map[uint64]*fakeUser{},
+		followers: map[string][]uint64{},
+		nextID:    firstEventID,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/events", f.handleEvents)
+	mux.HandleFunc("/v1/castsByParent", f.handleCastsByParent)
+	mux.HandleFunc("/v1/userDataByFid", f.handleUserData)
+	mux.HandleFunc("/v1/userNameProofsByFid", f.handleUserNameProofs)
+	mux.HandleFunc("/v1/verificationsByFid", f.handleVerifications)
+	mux.HandleFunc("/v1/onChainIdRegistryEventByAddress", f.handleIDRegistryEvent)
+	mux.HandleFunc("/warpcast/channel-followers", f.handleChannelFollowers)
+	f.server = httptest.NewServer(mux)
+	return f
+}
+
+// Endpoint returns the HTTP API endpoint of the fake hub.
+func (f *FakeHub) Endpoint() string {
+	return f.server.URL + "/v1"
+}
+
+// ChannelFollowersEndpoint returns the channel followers API endpoint of the
+// fake hub.
+func (f *FakeHub) ChannelFollowersEndpoint() string {
+	return f.server.URL + "/warpcast/channel-followers"
+}
+
+// Close stops the fake hub.
+func (f *FakeHub) Close() {
+	f.server.Close()
+}
+
+// Requests returns the number of requests received by the fake hub.
+func (f *FakeHub) Requests() int {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.requests
+}
+
+// AddCast adds an event that merges a new cast add message of the author
+// provided with the text and mentions provided, and returns the ID of the
+// event and the hash of the cast. The mentions positions are set to the
+// beginning of the text.
+func (f *FakeHub) AddCast(author uint64, text string, mentions ...uint64) (uint64, string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.addCast(author, text, "", mentions)
+}
+
+// AddChannelCast adds an event that merges a new cast add message of the
+// author provided with the text provided, published in the channel with the
+// parent URL provided, and returns the ID of the event and the hash of the
+// cast.
+func (f *FakeHub) AddChannelCast(author uint64, text, parentURL string) (uint64, string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.addCast(author, text, parentURL, nil)
+}
+
+// addCast adds an event that merges a new cast add message with the
+// parameters provided and returns the ID of the event and the hash of the
+// cast. It must be called with the lock held.
+func (f *FakeHub) addCast(author uint64, text, parentURL string, mentions []uint64) (uint64, string) {
+	hash := "0x" + hex.EncodeToString([]byte(fmt.Sprintf("%020d", f.nextID)))
+	positions := make([]uint64, len(mentions))
+	message := map[string]any{
+		"data": map[string]any{
+			"type":      castAddMessageType,
+			"fid":       author,
+			"timestamp": f.nextID,
+			"castAddBody": map[string]any{
+				"text":              text,
+				"mentions":          mentions,
+				"mentionsPositions": positions,
+				"embeds":            []any{},
+				"parentUrl":         parentURL,
+			},
+		},
+		"hash": hash,
+	}
+	id := f.addEvent(mergeMessageEventType, map[string]any{
+		"mergeMessageBody": map[string]any{"message": message},
+	})
+	return id, hash
+}
+
+// AddChannelFollower adds the FID provided to the followers of the channel
+// provided. The follows are timestamped in the order they are added.
+func (f *FakeHub) AddChannelFollower(channelID string, fid uint64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.followers[channelID] = append(f.followers[channelID], fid)
+}
+
+// AddUser adds a user to the fake hub with the FID, username, custody
+// address and verified addresses provided.
+func (f *FakeHub) AddUser(fid uint64, username, custodyAddress string, verifications ...string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.users[fid] = &fakeUser{
+		username:       username,
+		custodyAddress: custodyAddress,
+		verifications:  verifications,
+	}
+}
+
+// AddVerification adds an event that merges a new verification message of
+// the address provided by the user with the FID provided, and returns the ID
+// of the event. If the user exists, the address is added to its verified
+// addresses.
+func (f *FakeHub) AddVerification(fid uint64, address string) uint64 {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if user, ok := f.users[fid]; ok {
+		user.verifications = append(user.verifications, address)
+	}
+	return f.addEvent(mergeMessageEventType, map[string]any{
+		"mergeMessageBody": map[string]any{"message": verificationMessage(fid, address)},
+	})
+}
+
+// AddPruneEvent adds an event that is not a merge message event, and returns
+// its ID.
+func (f *FakeHub) AddPruneEvent() uint64 {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.addEvent(pruneMessageEventType, nil)
+}
+
+// addEvent adds a new event of the type provided with the body fields
+// provided and returns its ID. It must be called with the lock held.
+func (f *FakeHub) addEvent(eventType string, body map[string]any) uint64 {
+	id := f.nextID
+	f.nextID++
+	event := map[string]any{"type": eventType, "id": id}
+	for k, v := range body {
+		event[k] = v
+	}
+	f.events = append(f.events, event)
+	return id
+}
+
+// handleEvents serves a page of events from the event ID provided in the
+// from_event_id query parameter, including the ID of the next page.
+func (f *FakeHub) handleEvents(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.requests++
+	from, err := strconv.ParseUint(r.URL.Query().Get("from_event_id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid from_event_id", http.StatusBadRequest)
+		return
+	}
+	page := []map[string]any{}
+	nextPageEventID := from
+	for _, event := range f.events {
+		id := event["id"].(uint64)
+		if id < from {
+			continue
+		}
+		if len(page) == f.PageSize {
+			break
+		}
+		page = append(page, event)
+		nextPageEventID = id + 1
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		"events":          page,
+		"nextPageEventId": nextPageEventID,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleCastsByParent serves a page of the casts whose parent URL is the one
+// provided in the url query parameter, from the newest to the oldest. The
+// page token is the number of casts already served.
+func (f *FakeHub) handleCastsByParent(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.requests++
+	query := r.URL.Query()
+	pageSize, err := strconv.Atoi(query.Get("pageSize"))
+	if err != nil || pageSize <= 0 {
+		pageSize = f.PageSize
+	}
+	offset := 0
+	if token := query.Get("pageToken"); token != "" {
+		if offset, err = strconv.Atoi(token); err != nil {
+			http.Error(w, "invalid pageToken", http.StatusBadRequest)
+			return
+		}
+	}
+	casts := []any{}
+	for i := len(f.events) - 1; i >= 0; i-- {
+		body, ok := f.events[i]["mergeMessageBody"].(map[string]any)
+		if !ok {
+			continue
+		}
+		message := body["message"].(map[string]any)
+		castAddBody, ok := message["data"].(map[string]any)["castAddBody"].(map[string]any)
+		if ok && castAddBody["parentUrl"] == query.Get("url") {
+			casts = append(casts, message)
+		}
+	}
+	nextPageToken := ""
+	if offset > len(casts) {
+		offset = len(casts)
+	}
+	end := offset + pageSize
+	if end < len(casts) {
+		nextPageToken = strconv.Itoa(end)
+	} else {
+		end = len(casts)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{
+		"messages":      casts[offset:end],
+		"nextPageToken": nextPageToken,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleChannelFollowers serves a page of the followers of the channel
+// provided in the channelId query parameter, from the newest to the oldest.
+// The cursor is the number of followers already served.
+func (f *FakeHub) handleChannelFollowers(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.requests++
+	query := r.URL.Query()
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = f.PageSize
+	}
+	offset := 0
+	if cursor := query.Get("cursor"); cursor != "" {
+		if offset, err = strconv.Atoi(cursor); err != nil {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+	followers := f.followers[query.Get("channelId")]
+	users := []any{}
+	for i := len(followers) - 1 - offset; i >= 0 && len(users) < limit; i-- {
+		users = append(users, map[string]any{"fid": followers[i], "followedAt": i + 1})
+	}
+	next := map[string]any{}
+	if offset+len(users) < len(followers) {
+		next["cursor"] = strconv.Itoa(offset + len(users))
+	}
+	writeJSON(w, map[string]any{
+		"result": map[string]any{"users": users},
+		"next":   next,
+	})
+}
+
+// handleUserData serves the username of the user with the FID provided in the
+// fid query parameter.
+func (f *FakeHub) handleUserData(w http.ResponseWriter, r *http.Request) {
+	user, fid, ok := f.requestedUser(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, map[string]any{"messages": []any{map[string]any{
+		"data": map[string]any{
+			"type":      "MESSAGE_TYPE_USER_DATA_ADD",
+			"fid":       fid,
+			"timestamp": 1,
+			"userDataBody": map[string]any{
+				"type":  "USER_DATA_TYPE_USERNAME",
+				"value": user.username,
+			},
+		},
+	}}})
+}
+
+// handleUserNameProofs serves the username proof, that includes the custody
+// address, of the user with the FID provided in the fid query parameter.
+func (f *FakeHub) handleUserNameProofs(w http.ResponseWriter, r *http.Request) {
+	user, fid, ok := f.requestedUser(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, map[string]any{"proofs": []any{map[string]any{
+		"name":      user.username,
+		"owner":     user.custodyAddress,
+		"fid":       fid,
+		"type":      "USERNAME_TYPE_FNAME",
+		"timestamp": 1,
+	}}})
+}
+
+// handleVerifications serves the verification messages of the user with the
+// FID provided in the fid query parameter.
+func (f *FakeHub) handleVerifications(w http.ResponseWriter, r *http.Request) {
+	user, fid, ok := f.requestedUser(w, r)
+	if !ok {
+		return
+	}
+	messages := []any{}
+	for _, address := range user.verifications {
+		messages = append(messages, verificationMessage(fid, address))
+	}
+	writeJSON(w, map[string]any{"messages": messages})
+}
+
+// handleIDRegistryEvent serves the ID registry event of the user whose
+// custody address is the one provided in the address query parameter, or a
+// not found error if there is no user with that custody address.
+func (f *FakeHub) handleIDRegistryEvent(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.requests++
+	address := r.URL.Query().Get("address")
+	for fid, user := range f.users {
+		if strings.EqualFold(user.custodyAddress, address) {
+			writeJSON(w, map[string]any{"type": "EVENT_TYPE_ID_REGISTER", "fid": fid})
+			return
+		}
+	}
+	http.Error(w, "no such id registry event", http.StatusNotFound)
+}
+
+// requestedUser returns the user with the FID provided in the fid query
+// parameter and the FID. If the user does not exist, it writes a not found
+// error and returns false. The user returned is a copy, so it can be used
+// without the lock held.
+func (f *FakeHub) requestedUser(w http.ResponseWriter, r *http.Request) (*fakeUser, uint64, bool) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.requests++
+	fid, err := strconv.ParseUint(r.URL.Query().Get("fid"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid fid", http.StatusBadRequest)
+		return nil, 0, false
+	}
+	user, ok := f.users[fid]
+	if !ok {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return nil, 0, false
+	}
+	return &fakeUser{
+		username:       user.username,
+		custodyAddress: user.custodyAddress,
+		verifications:  append([]string{}, user.verifications...),
+	}, fid, true
+}
+
+// verificationMessage returns the message of the verification of the address
+// provided by the user with the FID provided.
+func verificationMessage(fid uint64, address string) map[string]any {
+	return map[string]any{
+		"data": map[string]any{
+			"type": verificationMessageType,
+			"fid":  fid,
+			"verificationAddEthAddressBody": map[string]any{
+				"address": address,
+			},
+		},
+		"signer": testSigner,
+	}
+}
+
+// writeJSON writes the value provided as the JSON response.
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
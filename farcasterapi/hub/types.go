@@ -19,10 +19,11 @@ type hubCastAddBody struct {
 }
 
 type hubMessageData struct {
-	Type        string          `json:"type"`
-	From        uint64          `json:"fid"`
-	Timestamp   uint64          `json:"timestamp"`
-	CastAddBody *hubCastAddBody `json:"castAddBody,omitempty"`
+	Type         string          `json:"type"`
+	From         uint64          `json:"fid"`
+	Timestamp    uint64          `json:"timestamp"`
+	CastAddBody  *hubCastAddBody `json:"castAddBody,omitempty"`
+	Verification *verification   `json:"verificationAddEthAddressBody,omitempty"`
 }
 
 type hubMessage struct {
@@ -31,7 +32,8 @@ type hubMessage struct {
 }
 
 type hubMessageResponse struct {
-	Messages []*hubMessage `json:"messages"`
+	Messages      []*hubMessage `json:"messages"`
+	NextPageToken string        `json:"nextPageToken"`
 }
 
 type hubReactionBody struct {
@@ -72,11 +74,11 @@ type verification struct {
 type verificationData struct {
 	Type         string        `json:"type"`
 	Verification *verification `json:"verificationAddEthAddressBody"`
-	Signer       string        `json:"signer"`
 }
 
 type verificationMessage struct {
-	Data *verificationData `json:"data"`
+	Data   *verificationData `json:"data"`
+	Signer string            `json:"signer"`
 }
 
 type verificationsResponse struct {
@@ -104,3 +106,37 @@ type hubUserDataMessage struct {
 type hubUserdataResponse struct {
 	Messages []*hubUserDataMessage `json:"messages"`
 }
+
+type hubMergeMessageBody struct {
+	Message *hubMessage `json:"message"`
+}
+
+type hubEvent struct {
+	Type             string               `json:"type"`
+	ID               uint64               `json:"id"`
+	MergeMessageBody *hubMergeMessageBody `json:"mergeMessageBody,omitempty"`
+}
+
+type hubEventsResponse struct {
+	Events          []*hubEvent `json:"events"`
+	NextPageEventID uint64      `json:"nextPageEventId"`
+}
+
+type idRegistryEvent struct {
+	Type string `json:"type"`
+	FID  uint64 `json:"fid"`
+}
+
+type channelFollower struct {
+	FID        uint64 `json:"fid"`
+	FollowedAt uint64 `json:"followedAt"`
+}
+
+type channelFollowersResponse struct {
+	Result struct {
+		Users []*channelFollower `json:"users"`
+	} `json:"result"`
+	Next struct {
+		Cursor string `json:"cursor"`
+	} `json:"next"`
+}
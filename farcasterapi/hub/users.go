@@ -0,0 +1,262 @@
+package hub
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	c3web3 "github.com/vocdoni/census3/helpers/web3"
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"github.com/vocdoni/vote-frame/farcasterapi/web3"
+	"go.vocdoni.io/dvote/log"
+)
+
+const (
+	// signersBatchSize is the number of signers requests to the KeyRegistry
+	// contract performed concurrently in bulk lookups
+	signersBatchSize = 10
+	// userdataBatchSize is the number of user data requests to the hub
+	// performed concurrently in bulk lookups
+	userdataBatchSize = 10
+)
+
+// appKeysProvider interface defines the methods required to get the app keys
+// (signers) of a FID from the Farcaster KeyRegistry contract. It is
+// implemented by the web3.FarcasterProvider.
+type appKeysProvider interface {
+	GetAppKeysByFid(fid *big.Int) ([][]byte, error)
+}
+
+// verificationsIndex struct is a local index of the verified addresses of the
+// users known by the hub API. The hubs only resolve the custody addresses to
+// FIDs, so the verified addresses are indexed from the verifications of the
+// users and from the verification messages of the hub events processed.
+type verificationsIndex struct {
+	mtx  sync.RWMutex
+	fids map[string]uint64
+}
+
+// newVerificationsIndex creates a new empty verifications index.
+func newVerificationsIndex() *verificationsIndex {
+	return &verificationsIndex{fids: map[string]uint64{}}
+}
+
+// add sets the FID provided as the owner of the verified address provided.
+func (vi *verificationsIndex) add(address string, fid uint64) {
+	vi.mtx.Lock()
+	defer vi.mtx.Unlock()
+	vi.fids[strings.ToLower(address)] = fid
+}
+
+// fid returns the FID that verified the address provided and if it is in the
+// index.
+func (vi *verificationsIndex) fid(address string) (uint64, bool) {
+	vi.mtx.RLock()
+	defer vi.mtx.RUnlock()
+	fid, ok := vi.fids[strings.ToLower(address)]
+	return fid, ok
+}
+
+// SetWeb3Pool method sets the web3 pool used to get the signers of the users
+// from the Farcaster KeyRegistry contract. Until it is set, the signers of
+// the users are the ones that signed their verifications.
+func (h *Hub) SetWeb3Pool(w3p *c3web3.Web3Pool) error {
+	provider, err := web3.NewFarcasterProvider(w3p)
+	if err != nil {
+		return fmt.Errorf("error creating web3 provider: %w", err)
+	}
+	h.signersProvider = provider
+	return nil
+}
+
+// SignersFromFID method returns the signers (app keys) of the user with the
+// given FID from the Farcaster KeyRegistry contract. It returns a
+// farcasterapi.ErrNotSupported error if no web3 pool has been set.
+func (h *Hub) SignersFromFID(fid uint64) ([]string, error) {
+	if h.signersProvider == nil {
+		return nil, fmt.Errorf("hub api has no web3 provider: %w", farcasterapi.ErrNotSupported)
+	}
+	keys, err := h.signersProvider.GetAppKeysByFid(new(big.Int).SetUint64(fid))
+	if err != nil {
+		return nil, fmt.Errorf("error getting signers: %w", err)
+	}
+	signers := []string{}
+	for _, key := range keys {
+		signers = append(signers, hex.EncodeToString(key))
+	}
+	return signers, nil
+}
+
+// UserDataByVerificationAddress method returns the user data of the users
+// with the given custody or verified addresses. The custody addresses are
+// resolved with the ID registry events of the hub and the verified addresses
+// with the local verifications index. The user data of every user is
+// returned once, including the signers from the KeyRegistry contract if a
+// web3 pool has been set. The hubs cannot resolve the verified addresses, and
+// the local index is in-memory and only contains the verifications of the
+// users and events processed since the API started, so if any address is not
+// resolved, the users found are returned with a farcasterapi.ErrPartialData
+// error. It returns a farcasterapi.ErrNoDataFound error if every address is
+// resolved but no user is found.
+func (h *Hub) UserDataByVerificationAddress(ctx context.Context, addresses []string) ([]*farcasterapi.Userdata, error) {
+	fids := []uint64{}
+	unresolved := 0
+	for _, address := range addresses {
+		fid, ok := h.verifications.fid(address)
+		if !ok {
+			var err error
+			if fid, err = h.fidByCustodyAddress(ctx, address); err != nil {
+				if errors.Is(err, farcasterapi.ErrNoDataFound) {
+					unresolved++
+					continue
+				}
+				return nil, err
+			}
+		}
+		if !slices.Contains(fids, fid) {
+			fids = append(fids, fid)
+		}
+	}
+	users, err := h.usersDataByFIDs(ctx, fids)
+	if err != nil {
+		return nil, err
+	}
+	if h.signersProvider != nil {
+		signers, err := h.signersByFIDs(fids)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			user.Signers = signers[user.FID]
+		}
+	}
+	result := []*farcasterapi.Userdata{}
+	for _, user := range users {
+		if len(user.Signers) == 0 {
+			log.Warnw("no signers found", "user", user.Username, "fid", user.FID)
+			continue
+		}
+		result = append(result, user)
+	}
+	if unresolved > 0 {
+		return result, fmt.Errorf("%w: %d of %d addresses are not indexed", farcasterapi.ErrPartialData,
+			unresolved, len(addresses))
+	}
+	if len(result) == 0 {
+		return nil, farcasterapi.ErrNoDataFound
+	}
+	return result, nil
+}
+
+// fidByCustodyAddress method returns the FID registered by the given custody
+// address in the ID registry. It returns a farcasterapi.ErrNoDataFound error
+// if the address has not registered any FID.
+func (h *Hub) fidByCustodyAddress(ctx context.Context, address string) (uint64, error) {
+	internalCtx, cancel := context.WithTimeout(ctx, userdataTimeout)
+	defer cancel()
+	uri := fmt.Sprintf(ENDPOINT_IDREGISTRY_BY_ADDRESS, strings.ToLower(address))
+	req, err := h.newRequest(internalCtx, http.MethodGet, uri, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error creating id registry request: %w", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error downloading id registry event: %w", err)
+	}
+	defer res.Body.Close()
+	// the hubs respond with a bad request or not found status if the address
+	// has not registered any FID
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusBadRequest {
+		return 0, farcasterapi.ErrNoDataFound
+	}
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("error downloading id registry event: %s", res.Status)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, fmt.Errorf("error reading id registry response body: %w", err)
+	}
+	event := &idRegistryEvent{}
+	if err := json.Unmarshal(body, event); err != nil {
+		return 0, fmt.Errorf("error unmarshalling id registry event: %w", err)
+	}
+	if event.FID == 0 {
+		return 0, farcasterapi.ErrNoDataFound
+	}
+	return event.FID, nil
+}
+
+// usersDataByFIDs method returns the user data of the users with the given
+// FIDs, requesting them to the hub in concurrent batches. The users that are
+// not found are skipped.
+func (h *Hub) usersDataByFIDs(ctx context.Context, fids []uint64) ([]*farcasterapi.Userdata, error) {
+	users := make([]*farcasterapi.Userdata, len(fids))
+	errs := make([]error, len(fids))
+	for from := 0; from < len(fids); from += userdataBatchSize {
+		to := min(from+userdataBatchSize, len(fids))
+		wg := sync.WaitGroup{}
+		for i := from; i < to; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				users[i], errs[i] = h.UserDataByFID(ctx, fids[i])
+			}(i)
+		}
+		wg.Wait()
+	}
+	result := []*farcasterapi.Userdata{}
+	for i, user := range users {
+		if errs[i] != nil {
+			if errors.Is(errs[i], farcasterapi.ErrNoDataFound) {
+				continue
+			}
+			return nil, fmt.Errorf("error getting user data of %d: %w", fids[i], errs[i])
+		}
+		result = append(result, user)
+	}
+	return result, nil
+}
+
+// signersByFIDs method returns the signers of the users with the given FIDs
+// from the KeyRegistry contract, requesting them in concurrent batches.
+func (h *Hub) signersByFIDs(fids []uint64) (map[uint64][]string, error) {
+	signers := make([][]string, len(fids))
+	errs := make([]error, len(fids))
+	for from := 0; from < len(fids); from += signersBatchSize {
+		to := min(from+signersBatchSize, len(fids))
+		wg := sync.WaitGroup{}
+		for i := from; i < to; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				signers[i], errs[i] = h.SignersFromFID(fids[i])
+			}(i)
+		}
+		wg.Wait()
+	}
+	result := make(map[uint64][]string, len(fids))
+	for i, fid := range fids {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("error getting signers of %d: %w", fid, errs[i])
+		}
+		result[fid] = signers[i]
+	}
+	return result, nil
+}
+
+// indexVerification method adds the address of the given verification
+// message to the local verifications index, if it is a verification message.
+func (h *Hub) indexVerification(m *hubMessage) {
+	if m == nil || m.Data == nil || m.Data.Type != MESSAGE_TYPE_VERIFICATION || m.Data.Verification == nil {
+		return
+	}
+	h.verifications.add(m.Data.Verification.Address, m.Data.From)
+}
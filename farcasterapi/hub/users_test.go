@@ -0,0 +1,102 @@
+package hub
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"github.com/vocdoni/vote-frame/farcasterapi/hub/hubtest"
+)
+
+// fakeKeyRegistry implements the appKeysProvider interface returning one app
+// key per FID and counting the requests.
+type fakeKeyRegistry struct {
+	mtx      sync.Mutex
+	requests int
+}
+
+func (f *fakeKeyRegistry) GetAppKeysByFid(fid *big.Int) ([][]byte, error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.requests++
+	return [][]byte{fid.Bytes()}, nil
+}
+
+func TestUserDataByVerificationAddress(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	fakeHub := hubtest.NewFakeHub(1)
+	defer fakeHub.Close()
+	fakeHub.AddUser(1, "alice", "0xA1", "0xA2")
+	fakeHub.AddUser(2, "bob", "0xB1")
+	fakeHub.AddUser(3, "carol", "0xC1")
+
+	h, err := NewHubAPI(fakeHub.Endpoint(), nil)
+	c.Assert(err, qt.IsNil)
+	h.fid = testBotFID
+
+	// without web3 provider, the signers are not supported
+	_, err = h.SignersFromFID(1)
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrNotSupported)
+	h.signersProvider = &fakeKeyRegistry{}
+	signers, err := h.SignersFromFID(1)
+	c.Assert(err, qt.IsNil)
+	c.Assert(signers, qt.DeepEquals, []string{"01"})
+
+	// the custody addresses are resolved with the id registry and every user
+	// is returned once, the addresses not resolved are reported
+	users, err := h.UserDataByVerificationAddress(ctx, []string{"0xa1", "0xB1", "0xA1", "0xunknown"})
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrPartialData)
+	c.Assert(users, qt.HasLen, 2)
+	c.Assert(users[0].FID, qt.Equals, uint64(1))
+	c.Assert(users[0].Username, qt.Equals, "alice")
+	c.Assert(users[0].VerificationsAddresses, qt.DeepEquals, []string{"0xA2"})
+	c.Assert(users[0].Signers, qt.DeepEquals, []string{"01"})
+	c.Assert(users[1].FID, qt.Equals, uint64(2))
+	c.Assert(users[1].Signers, qt.DeepEquals, []string{"02"})
+
+	// the verified addresses of the known users are indexed
+	users, err = h.UserDataByVerificationAddress(ctx, []string{"0xa2"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(users, qt.HasLen, 1)
+	c.Assert(users[0].FID, qt.Equals, uint64(1))
+
+	// the verified addresses are also indexed from the hub events
+	fakeHub.AddVerification(3, "0xC2")
+	_, _, err = h.MentionEvents(ctx, 1)
+	c.Assert(err, qt.IsNil)
+	users, err = h.UserDataByVerificationAddress(ctx, []string{"0xc2"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(users, qt.HasLen, 1)
+	c.Assert(users[0].Username, qt.Equals, "carol")
+
+	users, err = h.UserDataByVerificationAddress(ctx, []string{"0xunknown"})
+	c.Assert(err, qt.ErrorIs, farcasterapi.ErrPartialData)
+	c.Assert(users, qt.HasLen, 0)
+}
+
+func TestSignersByFIDs(t *testing.T) {
+	c := qt.New(t)
+
+	h, err := NewHubAPI("", nil)
+	c.Assert(err, qt.IsNil)
+	keyRegistry := &fakeKeyRegistry{}
+	h.signersProvider = keyRegistry
+
+	fids := []uint64{}
+	for fid := uint64(1); fid <= 2*signersBatchSize+1; fid++ {
+		fids = append(fids, fid)
+	}
+	signers, err := h.signersByFIDs(fids)
+	c.Assert(err, qt.IsNil)
+	c.Assert(signers, qt.HasLen, len(fids))
+	for _, fid := range fids {
+		c.Assert(signers[fid], qt.DeepEquals, []string{fmt.Sprintf("%02x", fid)})
+	}
+	c.Assert(keyRegistry.requests, qt.Equals, len(fids))
+}
@@ -40,6 +40,7 @@ const (
 	neynarSuggestChannels     = NeynarAPIEndpoint + "/v2/farcaster/channel/search?q=%s"
 	neynarUsersByChannelID    = NeynarAPIEndpoint + "/v2/farcaster/channel/followers?id=%s&limit=1000&cursor=%s"
 	neynarVerificationsByFID  = NeynarHubEndpoint + "/verificationsByFid?fid=%d"
+	neynarValidateFrame       = NeynarAPIEndpoint + "/v2/farcaster/frame/validate"
 	warpcastChannelInfo       = WarpcastClientEndpoint + "/channel?key=%s"
 
 	MaxAddressesPerRequest = 340
@@ -191,13 +192,13 @@ func (n *NeynarAPI) Publish(ctx context.Context, content string, _ []uint64, emb
 
 func (n *NeynarAPI) Reply(ctx context.Context, targetMsg *farcasterapi.APIMessage,
 	content string, _ []uint64, embeds ...string,
-) error {
+) (string, error) {
 	if n.fid == 0 {
-		return fmt.Errorf("farcaster user not set")
+		return "", fmt.Errorf("farcaster user not set")
 	}
 	// check if the content is too long
 	if len([]byte(content)) > farcasterapi.MaxCastBytes {
-		return fmt.Errorf("content is too long")
+		return "", fmt.Errorf("content is too long")
 	}
 	castEmbeds := []*castEmbed{}
 	if len(embeds) > 0 {
@@ -214,11 +215,21 @@ func (n *NeynarAPI) Reply(ctx context.Context, targetMsg *farcasterapi.APIMessag
 	}
 	body, err := json.Marshal(castReq)
 	if err != nil {
-		return fmt.Errorf("error marshalling request body: %w", err)
+		return "", fmt.Errorf("error marshalling request body: %w", err)
 	}
 	// create request with the bot fid and set the api key header
-	_, err = n.neynarReq(ctx, neynarReplyEndpoint, http.MethodPost, body, 0)
-	return err
+	resBody, err := n.neynarReq(ctx, neynarReplyEndpoint, http.MethodPost, body, 0)
+	if err != nil {
+		return "", err
+	}
+	castResponse := &castResponseV2{}
+	if err := json.Unmarshal(resBody, castResponse); err != nil {
+		return "", fmt.Errorf("error unmarshalling response body: %w", err)
+	}
+	if castResponse.Data == nil {
+		return "", farcasterapi.ErrNoDataFound
+	}
+	return castResponse.Data.Hash, nil
 }
 
 func (n *NeynarAPI) RecastsFIDs(ctx context.Context, msg *farcasterapi.APIMessage) ([]uint64, error) {
@@ -570,6 +581,13 @@ func (n *NeynarAPI) parseCastData(data *castWebhookData) (*farcasterapi.APIMessa
 			Hash: data.ParentHash,
 		}
 	}
+	// include the channel of the cast, if the API does not include it, try to
+	// get it from the parent URL
+	if data.Channel != nil && data.Channel.ID != "" {
+		message.Channel = data.Channel.ID
+	} else {
+		message.Channel = farcasterapi.ChannelFromParentURL(data.ParentURL)
+	}
 	// parse the embeds and include them in the message
 	if len(data.Embeds) > 0 {
 		message.Embeds = []string{}
@@ -580,6 +598,28 @@ func (n *NeynarAPI) parseCastData(data *castWebhookData) (*farcasterapi.APIMessa
 	return message, nil
 }
 
+// ReportFrameMessage method sends the signed frame message provided to the
+// Neynar API to be validated, which registers it in the Neynar frame
+// analytics. It implements the framevalidator.Reporter interface.
+func (n *NeynarAPI) ReportFrameMessage(ctx context.Context, messageBytes []byte) error {
+	req, err := json.Marshal(&frameValidateRequest{MessageBytes: hex.EncodeToString(messageBytes)})
+	if err != nil {
+		return fmt.Errorf("error marshalling request: %w", err)
+	}
+	body, err := n.neynarReq(ctx, neynarValidateFrame, http.MethodPost, req, defaultRequestTimeout)
+	if err != nil {
+		return fmt.Errorf("error validating frame message: %w", err)
+	}
+	res := &frameValidateResponse{}
+	if err := json.Unmarshal(body, res); err != nil {
+		return fmt.Errorf("error unmarshalling response body: %w", err)
+	}
+	if !res.Valid {
+		return fmt.Errorf("invalid frame message")
+	}
+	return nil
+}
+
 // neynarReq method sends a request to the Neynar API with the given URL, method,
 // body and timeout. It returns the response body and an error if something goes
 // wrong. It retries the request if it fails using sendRequest method.
@@ -620,7 +660,7 @@ func (n *NeynarAPI) sendRequest(req *http.Request) ([]byte, error) {
 		}
 		log.Debugw("retrying request", "attempt", attempt+1, "url", req.URL.String(), "method", req.Method)
 	}
-	return nil, fmt.Errorf("error downloading json: exceeded retry limit")
+	return nil, fmt.Errorf("error downloading json: exceeded retry limit: %w", farcasterapi.ErrRateLimited)
 }
 
 // VerifyRequest method verifies the request signature and returns a boolean
@@ -79,6 +79,11 @@ type castWebhookData struct {
 	Embeds       []*castEmbed      `json:"embeds"`
 	ParentAuthor *parentCastAuthor `json:"parentAuthor"`
 	Reactions    *castReactionsV2  `json:"reactions"`
+	Channel      *castChannel      `json:"channel"`
+}
+
+type castChannel struct {
+	ID string `json:"id"`
 }
 
 type reactionAuthorV2 struct {
@@ -182,3 +187,11 @@ type HubVerificationAddEthAddressBody struct {
 const (
 	HUB_MESSAGE_TYPE_VERIFICATION = "MESSAGE_TYPE_VERIFICATION_ADD_ETH_ADDRESS"
 )
+
+type frameValidateRequest struct {
+	MessageBytes string `json:"message_bytes_in_hex"`
+}
+
+type frameValidateResponse struct {
+	Valid bool `json:"valid"`
+}
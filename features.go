@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/vocdoni/vote-frame/features"
+	"github.com/vocdoni/vote-frame/mongo"
+	"go.vocdoni.io/dvote/httprouter"
+	"go.vocdoni.io/dvote/httprouter/apirest"
+	"go.vocdoni.io/dvote/log"
+)
+
+// loadFeatureThresholds loads the reputation thresholds of the features stored
+// in the database into the default features registry, both the global ones and
+// the overrides by community. The unknown features are ignored.
+func loadFeatureThresholds(db *mongo.MongoStorage) error {
+	stored, err := db.FeatureThresholds()
+	if err != nil {
+		return fmt.Errorf("failed to get feature thresholds: %w", err)
+	}
+	for _, thresholds := range stored {
+		for strFeature, reputation := range thresholds.Thresholds {
+			feature, ok := features.FromString(strFeature)
+			if !ok {
+				log.Warnw("unknown feature threshold stored", "feature", strFeature)
+				continue
+			}
+			if thresholds.CommunityID == "" {
+				features.DefaultRegistry.SetReputation(feature, reputation)
+			} else {
+				features.DefaultRegistry.SetCommunityReputation(feature, thresholds.CommunityID, reputation)
+			}
+		}
+	}
+	return nil
+}
+
+// featureAllowed returns true if the feature provided is allowed in the
+// community provided for the user with the FID provided, based on their
+// reputation. If the community is empty, the global threshold is used.
+func (v *vocdoniHandler) featureAllowed(feature features.Feature, communityID string, userFID uint64) bool {
+	var reputation uint32
+	if accessProfile, err := v.db.UserAccessProfile(userFID); err != nil {
+		log.Warnw("failed to get user access profile", "fid", userFID, "error", err)
+	} else if accessProfile != nil {
+		reputation = accessProfile.Reputation
+	}
+	return features.IsAllowedInCommunity(feature, communityID, reputation)
+}
+
+// featureThresholdsHandler returns the current reputation thresholds of the
+// features, the global ones and the overrides by community.
+func (v *vocdoniHandler) featureThresholdsHandler(_ *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	data, err := json.Marshal(features.DefaultRegistry.Thresholds())
+	if err != nil {
+		return fmt.Errorf("could not marshal response: %v", err)
+	}
+	return ctx.Send(data, apirest.HTTPstatusOK)
+}
+
+// setFeatureThresholdHandler tunes the reputation threshold of a feature,
+// globally or for a community, and stores it in the database to keep it after
+// restarts. If no reputation is provided for a community, its override is
+// removed.
+func (v *vocdoniHandler) setFeatureThresholdHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	req := &FeatureThresholdRequest{}
+	if err := json.Unmarshal(msg.Data, req); err != nil {
+		return ctx.Send([]byte("could not parse request"), http.StatusBadRequest)
+	}
+	feature, ok := features.FromString(req.Feature)
+	if !ok {
+		return ctx.Send([]byte("unknown feature"), http.StatusBadRequest)
+	}
+	if req.Reputation == nil {
+		if req.CommunityID == "" {
+			return ctx.Send([]byte("missing reputation"), http.StatusBadRequest)
+		}
+		if err := v.db.DelFeatureThreshold(req.CommunityID, req.Feature); err != nil {
+			return err
+		}
+		features.DefaultRegistry.DelCommunityReputation(feature, req.CommunityID)
+		return ctx.Send([]byte("Ok"), apirest.HTTPstatusOK)
+	}
+	if req.CommunityID != "" {
+		community, err := v.db.Community(req.CommunityID)
+		if err != nil {
+			return fmt.Errorf("failed to get community: %w", err)
+		}
+		if community == nil {
+			return ctx.Send([]byte("community not found"), http.StatusNotFound)
+		}
+	}
+	if err := v.db.SetFeatureThreshold(req.CommunityID, req.Feature, *req.Reputation); err != nil {
+		return err
+	}
+	if req.CommunityID == "" {
+		features.DefaultRegistry.SetReputation(feature, *req.Reputation)
+	} else {
+		features.DefaultRegistry.SetCommunityReputation(feature, req.CommunityID, *req.Reputation)
+	}
+	log.Infow("feature threshold updated", "feature", req.Feature,
+		"communityID", req.CommunityID, "reputation", *req.Reputation)
+	return ctx.Send([]byte("Ok"), apirest.HTTPstatusOK)
+}
@@ -2,8 +2,12 @@
 // the user reputation. Include constants for the features and the related
 // reputation thresholds. Also, include the features names and descriptions,
 // and methods to check if a feature is allowed based on the user reputation.
+// The thresholds are kept in a thread-safe registry that supports overriding
+// them for specific communities.
 package features
 
+import "time"
+
 // Feature represents a feature that the service supports based on the user
 // reputation, and it is an alias of int.
 type Feature int
@@ -12,28 +16,84 @@ const (
 	// NOTIFY_USERS is a feature that allows to notify users when the election
 	// starts.
 	NOTIFY_USERS Feature = iota
+	// SEND_REMINDERS is a feature that allows to send reminders to the users
+	// that have not voted yet in an election.
+	SEND_REMINDERS
+	// SEND_ANNOUNCEMENTS is a feature that allows to send announcements to the
+	// members of a community.
+	SEND_ANNOUNCEMENTS
+	// LONG_POLLS is a feature that allows to create polls longer than
+	// LongPollsMinDuration.
+	LONG_POLLS
+	// LARGE_CSV_CENSUS is a feature that allows to create censuses from CSV
+	// files with more than LargeCSVCensusMinAddresses addresses.
+	LARGE_CSV_CENSUS
+	// CUSTOM_IMAGES is a feature that allows to upload custom images.
+	CUSTOM_IMAGES
+)
+
+const (
+	// LongPollsMinDuration is the duration from which a poll requires the
+	// LONG_POLLS feature.
+	LongPollsMinDuration = 7 * 24 * time.Hour
+	// LargeCSVCensusMinAddresses is the number of addresses of a CSV census
+	// from which the census requires the LARGE_CSV_CENSUS feature.
+	LargeCSVCensusMinAddresses = 10000
 )
 
-// reputationThresholdsByFeature is a map that contains the reputation
-// thresholds for each feature.
-var reputationThresholdsByFeature = map[Feature]uint32{
-	NOTIFY_USERS: 15,
+// allFeatures contains every feature sorted by its value.
+var allFeatures = []Feature{
+	NOTIFY_USERS,
+	SEND_REMINDERS,
+	SEND_ANNOUNCEMENTS,
+	LONG_POLLS,
+	LARGE_CSV_CENSUS,
+	CUSTOM_IMAGES,
+}
+
+// defaultReputationThresholds is a map that contains the default reputation
+// thresholds for each feature. The features that were available to every user
+// before being gated by reputation default to 0, so they keep being available
+// unless a threshold is configured.
+var defaultReputationThresholds = map[Feature]uint32{
+	NOTIFY_USERS:       15,
+	SEND_REMINDERS:     0,
+	SEND_ANNOUNCEMENTS: 0,
+	LONG_POLLS:         0,
+	LARGE_CSV_CENSUS:   0,
+	CUSTOM_IMAGES:      0,
 }
 
 // featuresStr is a map that contains the string representation of each feature.
 var featuresStr = map[Feature]string{
-	NOTIFY_USERS: "notifyUsers",
+	NOTIFY_USERS:       "notifyUsers",
+	SEND_REMINDERS:     "sendReminders",
+	SEND_ANNOUNCEMENTS: "sendAnnouncements",
+	LONG_POLLS:         "longPolls",
+	LARGE_CSV_CENSUS:   "largeCSVCensus",
+	CUSTOM_IMAGES:      "customImages",
 }
 
 // featuresNames is a map that contains the name of each feature.
 var featuresNames = map[Feature]string{
-	NOTIFY_USERS: "Notify users",
+	NOTIFY_USERS:       "Notify users",
+	SEND_REMINDERS:     "Send reminders",
+	SEND_ANNOUNCEMENTS: "Send announcements",
+	LONG_POLLS:         "Long polls",
+	LARGE_CSV_CENSUS:   "Large CSV censuses",
+	CUSTOM_IMAGES:      "Custom images",
 }
 
 // featuresDescription is a map that contains the description of each feature.
 var featuresDescription = map[Feature]string{
 	NOTIFY_USERS: "Allows to notify users when the election starts." +
 		"The users must accept receiving notifications and can mute them at any time.",
+	SEND_REMINDERS: "Allows to send reminders to the users that have not voted yet " +
+		"in a community poll.",
+	SEND_ANNOUNCEMENTS: "Allows to send announcements to the members of a community.",
+	LONG_POLLS:         "Allows to create polls that last more than 7 days.",
+	LARGE_CSV_CENSUS:   "Allows to create censuses from CSV files with more than 10000 addresses.",
+	CUSTOM_IMAGES:      "Allows to upload custom images for communities and avatars.",
 }
 
 // String returns the string representation of the feature.
@@ -51,13 +111,39 @@ func (f Feature) Description() string {
 	return featuresDescription[f]
 }
 
+// Features returns every feature supported.
+func Features() []Feature {
+	return append([]Feature{}, allFeatures...)
+}
+
+// FromString returns the feature that has the string representation provided
+// and true, or false if the string does not represent any feature.
+func FromString(s string) (Feature, bool) {
+	for f, str := range featuresStr {
+		if str == s {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+// DefaultRegistry is the features registry used by the package level
+// functions.
+var DefaultRegistry = NewRegistry()
+
 // IsAllowed returns true if the feature is allowed based on the user reputation
 // provided.
 func IsAllowed(f Feature, userReputation uint32) bool {
-	return userReputation >= reputationThresholdsByFeature[f]
+	return DefaultRegistry.IsAllowed(f, "", userReputation)
+}
+
+// IsAllowedInCommunity returns true if the feature is allowed in the community
+// provided based on the user reputation provided.
+func IsAllowedInCommunity(f Feature, communityID string, userReputation uint32) bool {
+	return DefaultRegistry.IsAllowed(f, communityID, userReputation)
 }
 
 // SetReputation sets the reputation threshold for the feature provided.
 func SetReputation(f Feature, reputation uint32) {
-	reputationThresholdsByFeature[f] = reputation
+	DefaultRegistry.SetReputation(f, reputation)
 }
@@ -0,0 +1,135 @@
+package features
+
+import "sync"
+
+// FeatureStatus struct contains the information of a feature for a user,
+// including the reputation required to unlock it and if the user has unlocked
+// it.
+type FeatureStatus struct {
+	ID                 string `json:"id"`
+	Name               string `json:"name"`
+	Description        string `json:"description"`
+	RequiredReputation uint32 `json:"requiredReputation"`
+	Unlocked           bool   `json:"unlocked"`
+}
+
+// Thresholds struct contains the reputation thresholds of the features, the
+// global ones and the overrides by community, indexed by the string
+// representation of the features.
+type Thresholds struct {
+	Global      map[string]uint32            `json:"global"`
+	Communities map[string]map[string]uint32 `json:"communities"`
+}
+
+// Registry struct contains the reputation thresholds of the features. Every
+// feature has a global threshold that can be overridden for specific
+// communities. It is safe for concurrent use.
+type Registry struct {
+	mtx         sync.RWMutex
+	thresholds  map[Feature]uint32
+	communities map[string]map[Feature]uint32
+}
+
+// NewRegistry creates a new Registry with the default reputation thresholds
+// and without community overrides.
+func NewRegistry() *Registry {
+	thresholds := make(map[Feature]uint32, len(defaultReputationThresholds))
+	for f, threshold := range defaultReputationThresholds {
+		thresholds[f] = threshold
+	}
+	return &Registry{
+		thresholds:  thresholds,
+		communities: make(map[string]map[Feature]uint32),
+	}
+}
+
+// Threshold returns the reputation threshold of the feature provided for the
+// community provided. If the community does not override the threshold or it
+// is empty, the global threshold is returned.
+func (r *Registry) Threshold(f Feature, communityID string) uint32 {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	if overrides, ok := r.communities[communityID]; ok && communityID != "" {
+		if threshold, ok := overrides[f]; ok {
+			return threshold
+		}
+	}
+	return r.thresholds[f]
+}
+
+// IsAllowed returns true if the feature is allowed in the community provided
+// based on the user reputation provided. If the community is empty, the
+// global threshold is used.
+func (r *Registry) IsAllowed(f Feature, communityID string, userReputation uint32) bool {
+	return userReputation >= r.Threshold(f, communityID)
+}
+
+// SetReputation sets the global reputation threshold for the feature
+// provided.
+func (r *Registry) SetReputation(f Feature, reputation uint32) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.thresholds[f] = reputation
+}
+
+// SetCommunityReputation overrides the reputation threshold for the feature
+// provided in the community provided.
+func (r *Registry) SetCommunityReputation(f Feature, communityID string, reputation uint32) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.communities[communityID]; !ok {
+		r.communities[communityID] = make(map[Feature]uint32)
+	}
+	r.communities[communityID][f] = reputation
+}
+
+// DelCommunityReputation removes the override of the reputation threshold for
+// the feature provided in the community provided, so the global threshold is
+// used again.
+func (r *Registry) DelCommunityReputation(f Feature, communityID string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	delete(r.communities[communityID], f)
+	if len(r.communities[communityID]) == 0 {
+		delete(r.communities, communityID)
+	}
+}
+
+// Thresholds returns a copy of the current reputation thresholds, the global
+// ones and the overrides by community.
+func (r *Registry) Thresholds() *Thresholds {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	thresholds := &Thresholds{
+		Global:      make(map[string]uint32, len(r.thresholds)),
+		Communities: make(map[string]map[string]uint32, len(r.communities)),
+	}
+	for f, threshold := range r.thresholds {
+		thresholds.Global[f.String()] = threshold
+	}
+	for communityID, overrides := range r.communities {
+		thresholds.Communities[communityID] = make(map[string]uint32, len(overrides))
+		for f, threshold := range overrides {
+			thresholds.Communities[communityID][f.String()] = threshold
+		}
+	}
+	return thresholds
+}
+
+// Status returns the status of every feature for a user with the reputation
+// provided in the community provided. If the community is empty, the global
+// thresholds are used.
+func (r *Registry) Status(communityID string, userReputation uint32) []*FeatureStatus {
+	status := make([]*FeatureStatus, 0, len(allFeatures))
+	for _, f := range allFeatures {
+		threshold := r.Threshold(f, communityID)
+		status = append(status, &FeatureStatus{
+			ID:                 f.String(),
+			Name:               f.Name(),
+			Description:        f.Description(),
+			RequiredReputation: threshold,
+			Unlocked:           userReputation >= threshold,
+		})
+	}
+	return status
+}
@@ -0,0 +1,61 @@
+package features
+
+import (
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestRegistry(t *testing.T) {
+	c := qt.New(t)
+	r := NewRegistry()
+	// default thresholds
+	c.Assert(r.Threshold(NOTIFY_USERS, ""), qt.Equals, defaultReputationThresholds[NOTIFY_USERS])
+	c.Assert(r.IsAllowed(NOTIFY_USERS, "", 14), qt.IsFalse)
+	c.Assert(r.IsAllowed(NOTIFY_USERS, "", 15), qt.IsTrue)
+	// the features that were available to every user keep being available
+	for _, f := range []Feature{SEND_REMINDERS, SEND_ANNOUNCEMENTS, LONG_POLLS, LARGE_CSV_CENSUS, CUSTOM_IMAGES} {
+		c.Assert(r.IsAllowed(f, "", 0), qt.IsTrue)
+	}
+	// global threshold
+	r.SetReputation(NOTIFY_USERS, 30)
+	c.Assert(r.IsAllowed(NOTIFY_USERS, "", 15), qt.IsFalse)
+	c.Assert(r.IsAllowed(NOTIFY_USERS, "degen:1", 15), qt.IsFalse)
+	// community override
+	r.SetCommunityReputation(NOTIFY_USERS, "degen:1", 5)
+	c.Assert(r.IsAllowed(NOTIFY_USERS, "degen:1", 5), qt.IsTrue)
+	c.Assert(r.IsAllowed(NOTIFY_USERS, "degen:2", 5), qt.IsFalse)
+	c.Assert(r.Thresholds().Communities["degen:1"], qt.DeepEquals, map[string]uint32{"notifyUsers": 5})
+	// remove override
+	r.DelCommunityReputation(NOTIFY_USERS, "degen:1")
+	c.Assert(r.IsAllowed(NOTIFY_USERS, "degen:1", 5), qt.IsFalse)
+	c.Assert(r.Thresholds().Communities, qt.HasLen, 0)
+	// status
+	status := r.Status("", 20)
+	c.Assert(status, qt.HasLen, len(allFeatures))
+	for _, s := range status {
+		f, ok := FromString(s.ID)
+		c.Assert(ok, qt.IsTrue)
+		c.Assert(s.Unlocked, qt.Equals, r.IsAllowed(f, "", 20))
+	}
+}
+
+func TestRegistryConcurrency(t *testing.T) {
+	r := NewRegistry()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			r.SetReputation(LONG_POLLS, uint32(i))
+			r.SetCommunityReputation(LONG_POLLS, "degen:1", uint32(i))
+		}(i)
+		go func() {
+			defer wg.Done()
+			r.IsAllowed(LONG_POLLS, "degen:1", 5)
+			r.Status("", 5)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/vocdoni/vote-frame/frameanalytics"
+	"github.com/vocdoni/vote-frame/mongo"
+	"go.vocdoni.io/dvote/httprouter"
+	"go.vocdoni.io/dvote/httprouter/apirest"
+	"go.vocdoni.io/dvote/types"
+)
+
+// frameHandler wraps the handler of the frame provided to record its events:
+// a button press if the frame is requested by a frame action or an impression
+// if it is fetched, and an error if the handler fails. The events that depend
+// on the frame sent, like the voters not eligible, are recorded by the handler.
+// The events of unknown elections are not recorded.
+func (v *vocdoniHandler) frameHandler(frame frameanalytics.Frame, handler apirest.APIhandler) apirest.APIhandler {
+	return func(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+		electionID, _ := hex.DecodeString(ctx.URLParam("electionID"))
+		known := v.knownElection(electionID)
+		if known {
+			event := frameanalytics.EventImpression
+			if ctx.Request.Method == http.MethodPost {
+				event = frameanalytics.EventButton
+			}
+			v.analytics.Record(electionID, frame, event)
+		}
+		err := handler(msg, ctx)
+		if err != nil && known {
+			v.analytics.Record(electionID, frame, frameanalytics.EventError)
+		}
+		return err
+	}
+}
+
+// knownElection returns if the election provided is cached or stored in the
+// database, to avoid recording the events of arbitrary election IDs.
+func (v *vocdoniHandler) knownElection(electionID types.HexBytes) bool {
+	if len(electionID) != types.ProcessIDsize {
+		return false
+	}
+	if v.electionLRU.Contains(electionID.String()) {
+		return true
+	}
+	_, err := v.db.Election(electionID)
+	return err == nil
+}
+
+// recordImageImpression records an impression of the image provided if it is
+// an image of an election, whose ID starts with the election ID.
+func (v *vocdoniHandler) recordImageImpression(imageID string) {
+	prefix, _, ok := strings.Cut(imageID, "_")
+	if !ok {
+		return
+	}
+	if electionID, err := hex.DecodeString(prefix); err == nil && len(electionID) == types.ProcessIDsize {
+		v.analytics.Record(electionID, frameanalytics.FrameImage, frameanalytics.EventImpression)
+	}
+}
+
+// voteErrorEvent returns the frame event of the vote error provided.
+func voteErrorEvent(err error) frameanalytics.Event {
+	switch {
+	case errors.Is(err, ErrNotInCensus):
+		return frameanalytics.EventNotEligible
+	case errors.Is(err, ErrAlreadyVoted):
+		return frameanalytics.EventAlreadyVoted
+	default:
+		return frameanalytics.EventError
+	}
+}
+
+// pollFunnelHandler returns the funnel of the frame events of the poll
+// requested, from the impressions to the votes.
+func (v *vocdoniHandler) pollFunnelHandler(_ *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	electionID, err := hex.DecodeString(ctx.URLParam("electionID"))
+	if err != nil {
+		return ctx.Send([]byte("invalid electionID"), http.StatusBadRequest)
+	}
+	election, err := v.db.Election(electionID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrElectionUnknown) {
+			return ctx.Send([]byte("election not found"), http.StatusNotFound)
+		}
+		return ctx.Send([]byte(err.Error()), http.StatusInternalServerError)
+	}
+	return v.sendFunnel(ctx, election)
+}
+
+// communityFunnelHandler returns the funnel of the frame events of all the
+// polls of the community requested, from the impressions to the votes.
+func (v *vocdoniHandler) communityFunnelHandler(_ *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	communityID, _, _, err := v.parseCommunityIDFromURL(ctx)
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusBadRequest)
+	}
+	elections, err := v.db.ElectionsByCommunity(communityID)
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusInternalServerError)
+	}
+	return v.sendFunnel(ctx, elections...)
+}
+
+// sendFunnel sends the funnel of the frame events of the elections provided,
+// with the votes cast in them.
+func (v *vocdoniHandler) sendFunnel(ctx *httprouter.HTTPContext, elections ...*mongo.Election) error {
+	electionIDs := []string{}
+	for _, election := range elections {
+		electionIDs = append(electionIDs, election.ElectionID)
+	}
+	analytics, err := v.db.FrameAnalyticsOfElections(electionIDs...)
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusInternalServerError)
+	}
+	funnel := frameanalytics.NewFunnel()
+	for _, election := range elections {
+		var events map[string]map[string]uint64
+		if a, ok := analytics[election.ElectionID]; ok {
+			events = a.Events
+		}
+		funnel.Add(events, election.CastedVotes)
+	}
+	data, err := json.Marshal(funnel)
+	if err != nil {
+		return ctx.Send([]byte("error encoding funnel"), http.StatusInternalServerError)
+	}
+	ctx.SetResponseContentType("application/json")
+	return ctx.Send(data, http.StatusOK)
+}
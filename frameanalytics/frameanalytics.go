@@ -0,0 +1,188 @@
+// frameanalytics package records the interactions of the users with the poll
+// frames, such as the impressions, the button presses or the errors shown,
+// and aggregates them by election. The events are counted in memory and
+// flushed periodically to the storage, so recording them does not hit the
+// database on every request.
+package frameanalytics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+// DefaultFlushInterval is the default interval between the flushes of the
+// recorded events to the storage.
+const DefaultFlushInterval = 30 * time.Second
+
+// Frame identifies the frame, or the frame image, where an event happens.
+type Frame string
+
+const (
+	// FrameLanding is the landing frame of a poll
+	FrameLanding Frame = "landing"
+	// FrameShowElection is the frame with the question and the options of a
+	// poll
+	FrameShowElection Frame = "showElection"
+	// FrameVote is the frame that casts the vote
+	FrameVote Frame = "vote"
+	// FrameChangeVote is the frame with the question and the options of a
+	// poll shown to the voters that want to change their vote
+	FrameChangeVote Frame = "changeVote"
+	// FrameResults is the frame with the results of a poll
+	FrameResults Frame = "results"
+	// FrameInfo is the frame with the information of a poll
+	FrameInfo Frame = "info"
+	// FrameImage is the image of a poll frame
+	FrameImage Frame = "image"
+)
+
+// Event identifies the type of an event of a frame.
+type Event string
+
+const (
+	// EventImpression is the rendering of a frame or its image
+	EventImpression Event = "impression"
+	// EventButton is a press of a frame button that leads to the frame
+	EventButton Event = "button"
+	// EventNotEligible is a voter that is not in the census of the poll
+	EventNotEligible Event = "notEligible"
+	// EventAlreadyVoted is a voter that has already voted in the poll
+	EventAlreadyVoted Event = "alreadyVoted"
+	// EventError is any other error shown in the frame
+	EventError Event = "error"
+)
+
+// Events contains the number of events of an election, by frame and event
+// type.
+type Events map[string]map[string]uint64
+
+// Add adds the number of events provided of the frame and event type
+// provided.
+func (e Events) Add(frame, event string, count uint64) {
+	if _, ok := e[frame]; !ok {
+		e[frame] = map[string]uint64{}
+	}
+	e[frame][event] += count
+}
+
+// Storage interface defines the storage of the aggregated events, which must
+// increase the stored counters of the election provided by the events
+// provided.
+type Storage interface {
+	AddFrameEvents(electionID types.HexBytes, events map[string]map[string]uint64) error
+}
+
+// Recorder struct counts the frame events of every election and flushes them
+// to the storage. It is safe for concurrent use.
+type Recorder struct {
+	storage  Storage
+	interval time.Duration
+	mtx      sync.Mutex
+	pending  map[string]Events
+}
+
+// New creates a new Recorder that flushes the events to the storage provided
+// every interval provided, or every DefaultFlushInterval if it is zero.
+func New(storage Storage, interval time.Duration) *Recorder {
+	if interval == 0 {
+		interval = DefaultFlushInterval
+	}
+	return &Recorder{
+		storage:  storage,
+		interval: interval,
+		pending:  map[string]Events{},
+	}
+}
+
+// Record counts an event of the type provided in the frame provided of the
+// election provided. The events without a valid election ID are ignored.
+func (r *Recorder) Record(electionID types.HexBytes, frame Frame, event Event) {
+	if len(electionID) != types.ProcessIDsize {
+		return
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	id := electionID.String()
+	if _, ok := r.pending[id]; !ok {
+		r.pending[id] = Events{}
+	}
+	r.pending[id].Add(string(frame), string(event), 1)
+}
+
+// Start flushes the recorded events to the storage periodically in the
+// background until the context provided is done, when the remaining events
+// are flushed.
+func (r *Recorder) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				r.Flush()
+				return
+			case <-ticker.C:
+				r.Flush()
+			}
+		}
+	}()
+}
+
+// Flush writes the recorded events to the storage and resets the counters.
+// The events of the elections that cannot be stored are discarded.
+func (r *Recorder) Flush() {
+	r.mtx.Lock()
+	pending := r.pending
+	r.pending = map[string]Events{}
+	r.mtx.Unlock()
+
+	for id, events := range pending {
+		if err := r.storage.AddFrameEvents(types.HexStringToHexBytes(id), events); err != nil {
+			log.Warnw("failed to store frame events", "electionID", id, "error", err)
+		}
+	}
+}
+
+// Funnel struct summarizes the frame events of one or more elections as the
+// steps from the frames shown to the votes cast. The events of every frame
+// are included too.
+type Funnel struct {
+	Impressions   uint64 `json:"impressions"`
+	ButtonPresses uint64 `json:"buttonPresses"`
+	NotEligible   uint64 `json:"notEligible"`
+	AlreadyVoted  uint64 `json:"alreadyVoted"`
+	Errors        uint64 `json:"errors"`
+	Votes         uint64 `json:"votes"`
+	Frames        Events `json:"frames"`
+}
+
+// NewFunnel creates an empty funnel.
+func NewFunnel() *Funnel {
+	return &Funnel{Frames: Events{}}
+}
+
+// Add adds the events and the votes of an election to the funnel.
+func (f *Funnel) Add(events map[string]map[string]uint64, votes uint64) {
+	for frame, counters := range events {
+		for event, count := range counters {
+			f.Frames.Add(frame, event, count)
+			switch Event(event) {
+			case EventImpression:
+				f.Impressions += count
+			case EventButton:
+				f.ButtonPresses += count
+			case EventNotEligible:
+				f.NotEligible += count
+			case EventAlreadyVoted:
+				f.AlreadyVoted += count
+			case EventError:
+				f.Errors += count
+			}
+		}
+	}
+	f.Votes += votes
+}
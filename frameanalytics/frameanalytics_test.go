@@ -0,0 +1,115 @@
+package frameanalytics
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"go.vocdoni.io/dvote/types"
+)
+
+// testStorage stores the events flushed in memory, failing for the elections
+// in the failing set.
+type testStorage struct {
+	mtx     sync.Mutex
+	events  map[string]Events
+	failing map[string]bool
+}
+
+func (s *testStorage) AddFrameEvents(electionID types.HexBytes, events map[string]map[string]uint64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.failing[electionID.String()] {
+		return fmt.Errorf("storage failure")
+	}
+	if _, ok := s.events[electionID.String()]; !ok {
+		s.events[electionID.String()] = Events{}
+	}
+	for frame, counters := range events {
+		for event, count := range counters {
+			s.events[electionID.String()].Add(frame, event, count)
+		}
+	}
+	return nil
+}
+
+// testElectionID returns an election ID of the valid size filled with the
+// byte provided.
+func testElectionID(b byte) types.HexBytes {
+	id := make(types.HexBytes, types.ProcessIDsize)
+	for i := range id {
+		id[i] = b
+	}
+	return id
+}
+
+func TestRecorder(t *testing.T) {
+	c := qt.New(t)
+
+	electionA, electionB, electionC := testElectionID(0x0a), testElectionID(0x0b), testElectionID(0x0c)
+	storage := &testStorage{events: map[string]Events{}, failing: map[string]bool{electionC.String(): true}}
+	r := New(storage, 0)
+	c.Assert(r.interval, qt.Equals, DefaultFlushInterval)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.Record(electionA, FrameLanding, EventImpression)
+			r.Record(electionA, FrameImage, EventImpression)
+		}()
+	}
+	wg.Wait()
+	r.Record(electionA, FrameShowElection, EventButton)
+	r.Record(electionA, FrameShowElection, EventNotEligible)
+	r.Record(electionB, FrameVote, EventAlreadyVoted)
+	r.Record(electionC, FrameVote, EventError)
+	// the events without a valid election ID are ignored
+	r.Record(nil, FrameInfo, EventButton)
+	r.Record(types.HexBytes{0x0a}, FrameInfo, EventButton)
+	r.Flush()
+
+	c.Assert(storage.events, qt.DeepEquals, map[string]Events{
+		electionA.String(): {
+			"landing":      {"impression": 10},
+			"image":        {"impression": 10},
+			"showElection": {"button": 1, "notEligible": 1},
+		},
+		electionB.String(): {"vote": {"alreadyVoted": 1}},
+	})
+
+	// the counters are reset once flushed, and the new events are added to the
+	// stored ones
+	r.Flush()
+	r.Record(electionB, FrameVote, EventAlreadyVoted)
+	r.Flush()
+	c.Assert(storage.events[electionB.String()], qt.DeepEquals, Events{"vote": {"alreadyVoted": 2}})
+}
+
+func TestFunnel(t *testing.T) {
+	c := qt.New(t)
+
+	funnel := NewFunnel()
+	funnel.Add(Events{
+		"landing":      {"impression": 10},
+		"showElection": {"button": 6, "notEligible": 2, "alreadyVoted": 1},
+		"vote":         {"button": 3, "error": 1},
+	}, 2)
+	funnel.Add(Events{"landing": {"impression": 5}, "results": {"button": 1}}, 1)
+	c.Assert(funnel, qt.DeepEquals, &Funnel{
+		Impressions:   15,
+		ButtonPresses: 10,
+		NotEligible:   2,
+		AlreadyVoted:  1,
+		Errors:        1,
+		Votes:         3,
+		Frames: Events{
+			"landing":      {"impression": 15},
+			"showElection": {"button": 6, "notEligible": 2, "alreadyVoted": 1},
+			"vote":         {"button": 3, "error": 1},
+			"results":      {"button": 1},
+		},
+	})
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vocdoni/vote-frame/framevalidator"
+	"github.com/vocdoni/vote-frame/shortener"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+	"go.vocdoni.io/dvote/vochain/transaction/proofs/farcasterproof"
+)
+
+const (
+	// signerCheckDisabled disables the check of the app keys of the frame
+	// messages
+	signerCheckDisabled = "disabled"
+	// signerCheckLenient rejects the revoked app keys but accepts the frame
+	// messages whose app keys cannot be checked
+	signerCheckLenient = "lenient"
+	// signerCheckStrict rejects the revoked app keys and the frame messages
+	// whose app keys cannot be checked
+	signerCheckStrict = "strict"
+	// signerSourceKeyRegistry reads the active app keys from the Farcaster
+	// KeyRegistry contract
+	signerSourceKeyRegistry = "keyregistry"
+	// signerSourceAPI reads the active app keys from the Farcaster API
+	signerSourceAPI = "api"
+)
+
+var (
+	ErrFrameMessageExpired = fmt.Errorf("frame message expired")
+	ErrFrameURLMismatch    = fmt.Errorf("frame message URL does not match the poll")
+	ErrFrameStateMismatch  = fmt.Errorf("frame message state does not match the poll")
+)
+
+// checkFrameMessage checks that the validated frame message provided was
+// created for the election provided and recently enough to be accepted. The
+// signed timestamp must be within the frameMaxAge window (if it is not zero),
+// and the signed URL, or the URL it redirects to if it is a short URL, must
+// reference the election (if frameURLCheck is enabled). If the signed message
+// includes a frame state, its process ID must be the election ID. If
+// requireState is true, the frame state is mandatory, which is the case for
+// the vote frames.
+func checkFrameMessage(frameMsg *framevalidator.Result, electionID types.HexBytes, requireState bool) error {
+	// check the message freshness, in both directions to limit the clock skew
+	if frameMaxAge > 0 {
+		if age := time.Since(frameMsg.Timestamp); age > frameMaxAge || age < -frameMaxAge {
+			return fmt.Errorf("%w: signed at %s", ErrFrameMessageExpired, frameMsg.Timestamp.UTC().Format(time.RFC3339))
+		}
+	}
+	return checkFrameTarget(frameMsg.URL, frameMsg.State, electionID, requireState)
+}
+
+// checkFrameTarget checks that the signed URL and frame state provided belong
+// to the election provided, as described in checkFrameMessage.
+func checkFrameTarget(frameURL string, frameState []byte, electionID types.HexBytes, requireState bool) error {
+	// check the signed URL references the election
+	if frameURLCheck && !urlReferencesElection(frameURL, electionID) {
+		return fmt.Errorf("%w: %s", ErrFrameURLMismatch, frameURL)
+	}
+	// check the signed state, if any, belongs to the election
+	if len(frameState) == 0 {
+		if requireState {
+			return fmt.Errorf("%w: missing state", ErrFrameStateMismatch)
+		}
+		return nil
+	}
+	state := &farcasterproof.FarcasterState{}
+	if err := json.Unmarshal(frameState, state); err != nil {
+		return fmt.Errorf("%w: invalid state", ErrFrameStateMismatch)
+	}
+	if !bytes.Equal(state.ProcessID, electionID) {
+		return fmt.Errorf("%w: state of %x", ErrFrameStateMismatch, state.ProcessID)
+	}
+	return nil
+}
+
+// urlReferencesElection returns if any segment of the path of the URL
+// provided is the election ID provided. The short URLs of the vocdoni
+// shortener, used in the bot polls, are resolved to the URL they redirect to
+// before checking it.
+func urlReferencesElection(rawURL string, electionID types.HexBytes) bool {
+	if shortener.IsShortURL(rawURL) {
+		target, err := shortener.Resolve(context.Background(), rawURL)
+		if err != nil {
+			log.Warnw("cannot resolve frame short URL", "url", rawURL, "error", err)
+			return false
+		}
+		rawURL = target
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	for _, segment := range strings.Split(u.Path, "/") {
+		if strings.EqualFold(segment, electionID.String()) {
+			return true
+		}
+	}
+	return false
+}
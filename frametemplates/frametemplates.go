@@ -0,0 +1,197 @@
+// frametemplates package renders the HTML pages of the Farcaster frames using
+// html/template, so every value inserted in the pages, like the poll titles,
+// is escaped. Every frame has a typed view model and a template, rendered
+// inside a common layout. The default templates are embedded in the binary,
+// but they can be overridden per community with the templates found in a
+// directory, to support community branded frames.
+package frametemplates
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// layoutName is the name of the template that renders the full page,
+	// including the frame template, which must be named frameName
+	layoutName = "layout"
+	frameName  = "frame"
+	// templateExt is the extension of the template files
+	templateExt = ".html"
+)
+
+//go:embed templates/*.html
+var defaultTemplates embed.FS
+
+// Links struct contains the base URLs referenced by the frames, which are
+// available to the templates as the server, explorer and onvote functions.
+type Links struct {
+	Server   string
+	Explorer string
+	Onvote   string
+}
+
+// Renderer struct renders the frames with the default templates or the
+// templates of the community of the poll, if it has any.
+type Renderer struct {
+	defaults    map[string]*template.Template
+	communities map[string]map[string]*template.Template
+}
+
+// New creates a new frame renderer with the links provided and the default
+// templates. If the overrides directory is not empty, every subdirectory of it
+// is treated as the templates of the community with the same ID, and every
+// template file found in it (layout.html or the file of any frame, like
+// vote.html) replaces the default one for the polls of that community. It
+// returns an error if any template cannot be parsed.
+func New(links Links, overridesDir string) (*Renderer, error) {
+	funcs := template.FuncMap{
+		"server":   func() string { return links.Server },
+		"explorer": func() string { return links.Explorer },
+		"onvote":   func() string { return links.Onvote },
+		"inc":      func(i int) int { return i + 1 },
+	}
+	defaultSources := map[string]string{}
+	for _, name := range append(templateNames, layoutName) {
+		source, err := defaultTemplates.ReadFile("templates/" + name + templateExt)
+		if err != nil {
+			return nil, fmt.Errorf("default template %s not found: %w", name, err)
+		}
+		defaultSources[name] = string(source)
+	}
+	r := &Renderer{
+		defaults:    map[string]*template.Template{},
+		communities: map[string]map[string]*template.Template{},
+	}
+	for _, name := range templateNames {
+		tmpl, err := parse(funcs, name, defaultSources[layoutName], defaultSources[name])
+		if err != nil {
+			return nil, err
+		}
+		r.defaults[name] = tmpl
+	}
+	if overridesDir == "" {
+		return r, nil
+	}
+	entries, err := os.ReadDir(overridesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		communityID := entry.Name()
+		overrides, err := readOverrides(filepath.Join(overridesDir, communityID), defaultSources)
+		if err != nil {
+			return nil, fmt.Errorf("community %s: %w", communityID, err)
+		}
+		if len(overrides) == 0 {
+			continue
+		}
+		r.communities[communityID] = map[string]*template.Template{}
+		for _, name := range templateNames {
+			_, layoutOverridden := overrides[layoutName]
+			_, frameOverridden := overrides[name]
+			if !layoutOverridden && !frameOverridden {
+				r.communities[communityID][name] = r.defaults[name]
+				continue
+			}
+			layout, frame := defaultSources[layoutName], defaultSources[name]
+			if layoutOverridden {
+				layout = overrides[layoutName]
+			}
+			if frameOverridden {
+				frame = overrides[name]
+			}
+			tmpl, err := parse(funcs, name, layout, frame)
+			if err != nil {
+				return nil, fmt.Errorf("community %s: %w", communityID, err)
+			}
+			r.communities[communityID][name] = tmpl
+		}
+	}
+	return r, nil
+}
+
+// Communities returns the IDs of the communities with custom templates.
+func (r *Renderer) Communities() []string {
+	ids := make([]string, 0, len(r.communities))
+	for id := range r.communities {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Render writes the frame of the view provided to the writer, using the
+// templates of the community provided if it has any, or the default ones
+// otherwise.
+func (r *Renderer) Render(w io.Writer, communityID string, view View) error {
+	tmpl, ok := r.communities[communityID][view.template()]
+	if !ok {
+		if tmpl, ok = r.defaults[view.template()]; !ok {
+			return fmt.Errorf("unknown frame template %s", view.template())
+		}
+	}
+	if err := tmpl.ExecuteTemplate(w, layoutName, view); err != nil {
+		return fmt.Errorf("failed to render frame %s: %w", view.template(), err)
+	}
+	return nil
+}
+
+// Bytes returns the frame of the view provided rendered as Render does.
+func (r *Renderer) Bytes(communityID string, view View) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := r.Render(buf, communityID, view); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// parse parses the layout and the frame template sources provided into a new
+// template with the name provided.
+func parse(funcs template.FuncMap, name, layout, frame string) (*template.Template, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Parse(layout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse layout of template %s: %w", name, err)
+	}
+	if tmpl, err = tmpl.Parse(frame); err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	if tmpl.Lookup(layoutName) == nil || tmpl.Lookup(frameName) == nil {
+		return nil, fmt.Errorf("template %s must define %q and %q", name, layoutName, frameName)
+	}
+	return tmpl, nil
+}
+
+// readOverrides reads the template files of the directory provided, returning
+// their sources by template name. It returns an error if any file does not
+// match a known template.
+func readOverrides(dir string, known map[string]string) (map[string]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read templates directory: %w", err)
+	}
+	overrides := map[string]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != templateExt {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), templateExt)
+		if _, ok := known[name]; !ok {
+			return nil, fmt.Errorf("unknown template file %s", entry.Name())
+		}
+		source, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", entry.Name(), err)
+		}
+		overrides[name] = string(source)
+	}
+	return overrides, nil
+}
@@ -0,0 +1,141 @@
+package frametemplates
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// update flag regenerates the golden files with the current output of the
+// templates: go test ./frametemplates -update
+var update = flag.Bool("update", false, "update the golden files")
+
+var testLinks = Links{
+	Server:   "https://farcaster.vote",
+	Explorer: "https://explorer.vote",
+	Onvote:   "https://onvote.app",
+}
+
+const (
+	testProcessID = "4ae20a8eb4ca4d7c7fa4f6ff7d0f2f2a1a6cc25e8cc1b09dd2e5e6b3ec9bf17d"
+	testImage     = "https://farcaster.vote/images/abcd.png"
+)
+
+// testViews contains a view of every frame variant, by golden file name.
+var testViews = map[string]View{
+	"main": Main{Page: Page{Image: testImage, Title: "Best pizza?"}, ProcessID: testProcessID},
+	"mainv2": Main{
+		Page:      Page{Image: testImage, Title: "Best pizza?"},
+		ProcessID: testProcessID,
+		Embed:     `{"version":"next","imageUrl":"` + testImage + `","button":{"title":"Vote"}}`,
+	},
+	"vote": Vote{
+		Page:      Page{Image: testImage, Title: "Best pizza?"},
+		ProcessID: testProcessID,
+		Options:   []string{"A", "B", "C"},
+		State:     `{"processID":"` + testProcessID + `"}`,
+	},
+	"voteopenframes": Vote{
+		Page:      Page{Image: testImage, Title: "Best pizza?"},
+		ProcessID: testProcessID,
+		Options:   []string{"A", "B"},
+		State:     `{"processID":"` + testProcessID + `"}`,
+		Accepts:   []Protocol{{Name: "farcaster", Version: "vNext"}, {Name: "lens", Version: "1.0.0"}},
+	},
+	"aftervote": AfterVote{Page: Page{Image: testImage, Title: "Best pizza?"}, ProcessID: testProcessID, Nullifier: "0a0b"},
+	"changevote": ChangeVote{
+		Page:      Page{Image: testImage, Title: "Best pizza?"},
+		ProcessID: testProcessID,
+		Nullifier: "0a0b",
+	},
+	"results": Results{Page: Page{Image: testImage, Title: "Best pizza?"}, ProcessID: testProcessID},
+	"finalresults": FinalResults{
+		Page:      Page{Image: testImage, Title: "Final results"},
+		ProcessID: testProcessID,
+	},
+	"info":          Info{Page: Page{Image: testImage, Title: "Best pizza?"}, ProcessID: testProcessID},
+	"error":         Error{Page: Page{Image: testImage}, ProcessID: testProcessID},
+	"noteligible":   Error{Page: Page{Image: testImage}, Kind: ErrorNotEligible, ProcessID: testProcessID},
+	"alreadyvoted":  Error{Page: Page{Image: testImage}, Kind: ErrorAlreadyVoted, ProcessID: testProcessID, Nullifier: "0a0b"},
+	"delegated":     Error{Page: Page{Image: testImage}, Kind: ErrorDelegated, ProcessID: testProcessID},
+	"revokedsigner": Error{Page: Page{Image: testImage}, Kind: ErrorRevokedSigner, ProcessID: testProcessID},
+	"outdated":      Error{Page: Page{Image: testImage}, Kind: ErrorOutdated, ProcessID: testProcessID},
+	"notifications": Notifications{Page: Page{Image: testImage}},
+	"notificationsmanager": Notifications{
+		Page: Page{Image: testImage},
+		Kind: NotificationsManager,
+	},
+	"notificationsresponse": Notifications{Page: Page{Image: testImage}, Kind: NotificationsResponse},
+	// the user provided values are escaped
+	"escaped": Main{Page: Page{Image: testImage, Title: `"/><script>alert('pwned')</script>`}, ProcessID: testProcessID},
+}
+
+func TestGolden(t *testing.T) {
+	c := qt.New(t)
+
+	r, err := New(testLinks, "")
+	c.Assert(err, qt.IsNil)
+	// every frame template has a golden file
+	for _, name := range templateNames {
+		_, ok := testViews[name]
+		c.Assert(ok, qt.IsTrue, qt.Commentf("missing test view of frame %s", name))
+	}
+	for name, view := range testViews {
+		c.Run(name, func(c *qt.C) {
+			output, err := r.Bytes("", view)
+			c.Assert(err, qt.IsNil)
+			goldenFile := filepath.Join("testdata", name+".golden")
+			if *update {
+				c.Assert(os.WriteFile(goldenFile, output, 0o644), qt.IsNil)
+			}
+			golden, err := os.ReadFile(goldenFile)
+			c.Assert(err, qt.IsNil)
+			c.Assert(string(output), qt.Equals, string(golden))
+		})
+	}
+}
+
+func TestCommunityOverrides(t *testing.T) {
+	c := qt.New(t)
+
+	dir := c.TempDir()
+	brandedDir := filepath.Join(dir, "degen:1")
+	c.Assert(os.Mkdir(brandedDir, 0o755), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(brandedDir, "layout.html"),
+		[]byte(`{{define "layout"}}<html><head>{{template "frame" .}}</head><body>Degen: {{.Title}}</body></html>{{end}}`), 0o644), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(brandedDir, "vote.html"),
+		[]byte(`{{define "frame"}}<meta property="fc:frame:post_url" content="{{server}}/vote/{{.ProcessID}}" />{{end}}`), 0o644), qt.IsNil)
+
+	r, err := New(testLinks, dir)
+	c.Assert(err, qt.IsNil)
+	c.Assert(r.Communities(), qt.DeepEquals, []string{"degen:1"})
+
+	// the community frames are rendered with the custom templates
+	output, err := r.Bytes("degen:1", testViews["vote"])
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(output), qt.Equals, `<html><head><meta property="fc:frame:post_url" content="https://farcaster.vote/vote/`+
+		testProcessID+`" /></head><body>Degen: Best pizza?</body></html>`)
+	// the frames not overridden use the custom layout
+	output, err = r.Bytes("degen:1", testViews["results"])
+	c.Assert(err, qt.IsNil)
+	c.Assert(strings.HasPrefix(string(output), "<html><head>\n    <meta property=\"fc:frame\" content=\"vNext\" />"), qt.IsTrue)
+	// the other communities use the default templates
+	output, err = r.Bytes("other", testViews["vote"])
+	c.Assert(err, qt.IsNil)
+	golden, err := os.ReadFile(filepath.Join("testdata", "vote.golden"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(output), qt.Equals, string(golden))
+
+	// the unknown or invalid templates are rejected
+	c.Assert(os.WriteFile(filepath.Join(brandedDir, "vote.html"), []byte(`{{define "frame"}}{{.Unclosed`), 0o644), qt.IsNil)
+	_, err = New(testLinks, dir)
+	c.Assert(err, qt.ErrorMatches, "community degen:1: failed to parse template vote.*")
+	c.Assert(os.Remove(filepath.Join(brandedDir, "vote.html")), qt.IsNil)
+	c.Assert(os.WriteFile(filepath.Join(brandedDir, "votes.html"), []byte(`{{define "frame"}}{{end}}`), 0o644), qt.IsNil)
+	_, err = New(testLinks, dir)
+	c.Assert(err, qt.ErrorMatches, "community degen:1: unknown template file votes.html")
+}
@@ -0,0 +1,187 @@
+package frametemplates
+
+// Page struct contains the fields shared by every frame: the URL of the frame
+// image and the title of the page, which is shown to the browsers that open
+// the frame URL.
+type Page struct {
+	Image string
+	Title string
+}
+
+// View interface defines the typed view models of the frames. Every view
+// knows the name of the template that renders it.
+type View interface {
+	template() string
+}
+
+// Protocol is an Open Frames client protocol accepted by a frame, with the
+// minimum version supported.
+type Protocol struct {
+	Name    string
+	Version string
+}
+
+// Main is the view of the landing frame of a poll, with the buttons to vote,
+// see the results and the information of the poll. If the embed is set, it is
+// the JSON encoded Frames v2 embed that launches the poll as a mini app, used
+// as the fc:frame value instead of vNext. The vNext buttons are kept for the
+// clients that do not support it.
+// If the accepted protocols are set, the frame is announced as an Open Frame
+// for them.
+type Main struct {
+	Page
+	ProcessID string
+	Embed     string
+	Accepts   []Protocol
+}
+
+func (Main) template() string { return "main" }
+
+// Vote is the view of the frame that shows the question of a poll and a button
+// for every option. The state is the JSON encoded frame state that the signed
+// vote must include. The accepted protocols are the Open Frames client
+// protocols that can interact with the frame, if any.
+type Vote struct {
+	Page
+	ProcessID string
+	Options   []string
+	State     string
+	Accepts   []Protocol
+}
+
+func (Vote) template() string { return "vote" }
+
+// AfterVote is the view of the frame shown once the vote is sent, with the
+// link to verify it on the explorer.
+type AfterVote struct {
+	Page
+	ProcessID string
+	Nullifier string
+}
+
+func (AfterVote) template() string { return "aftervote" }
+
+// ChangeVote is the view of the frame shown to the users that already voted
+// in a poll that allows to overwrite the votes, with a button to change their
+// vote and the link to verify it.
+type ChangeVote struct {
+	Page
+	ProcessID string
+	Nullifier string
+}
+
+func (ChangeVote) template() string { return "changevote" }
+
+// Results is the view of the frame with the current results of a poll.
+type Results struct {
+	Page
+	ProcessID string
+}
+
+func (Results) template() string { return "results" }
+
+// FinalResults is the view of the frame with the final results of a finished
+// poll.
+type FinalResults struct {
+	Page
+	ProcessID string
+}
+
+func (FinalResults) template() string { return "finalresults" }
+
+// Info is the view of the frame with the information of a poll.
+type Info struct {
+	Page
+	ProcessID string
+}
+
+func (Info) template() string { return "info" }
+
+// ErrorKind identifies the error frames, each one with its own template.
+type ErrorKind string
+
+const (
+	// ErrorGeneric is the frame of any error, described by its image.
+	ErrorGeneric ErrorKind = "error"
+	// ErrorNotEligible is the frame shown to the users that are not in the
+	// census of the poll.
+	ErrorNotEligible ErrorKind = "noteligible"
+	// ErrorAlreadyVoted is the frame shown to the users that already voted,
+	// with the link to verify their vote.
+	ErrorAlreadyVoted ErrorKind = "alreadyvoted"
+	// ErrorDelegated is the frame shown to the users that delegated their
+	// vote.
+	ErrorDelegated ErrorKind = "delegated"
+	// ErrorRevokedSigner is the frame shown when the frame message is signed
+	// with an app key revoked.
+	ErrorRevokedSigner ErrorKind = "revokedsigner"
+	// ErrorOutdated is the frame shown when the frame message is too old or
+	// has a wrong state, with a button to reload the poll.
+	ErrorOutdated ErrorKind = "outdated"
+)
+
+// Error is the view of the error frames of a poll. The nullifier is only
+// used by the ErrorAlreadyVoted frame.
+type Error struct {
+	Page
+	Kind      ErrorKind
+	ProcessID string
+	Nullifier string
+}
+
+func (e Error) template() string {
+	if e.Kind == "" {
+		return string(ErrorGeneric)
+	}
+	return string(e.Kind)
+}
+
+// NotificationsKind identifies the notifications frames, each one with its own
+// template.
+type NotificationsKind string
+
+const (
+	// NotificationsSettings is the frame to allow or disable the
+	// notifications.
+	NotificationsSettings NotificationsKind = "notifications"
+	// NotificationsManager is the frame to mute or allow the notifications of
+	// a user.
+	NotificationsManager NotificationsKind = "notificationsmanager"
+	// NotificationsResponse is the frame shown once the notifications
+	// settings are updated.
+	NotificationsResponse NotificationsKind = "notificationsresponse"
+)
+
+// Notifications is the view of the notifications settings frames.
+type Notifications struct {
+	Page
+	Kind NotificationsKind
+}
+
+func (n Notifications) template() string {
+	if n.Kind == "" {
+		return string(NotificationsSettings)
+	}
+	return string(n.Kind)
+}
+
+// templateNames contains the names of every frame template, which must be
+// defined by the default templates.
+var templateNames = []string{
+	Main{}.template(),
+	Vote{}.template(),
+	AfterVote{}.template(),
+	ChangeVote{}.template(),
+	Results{}.template(),
+	FinalResults{}.template(),
+	Info{}.template(),
+	string(ErrorGeneric),
+	string(ErrorNotEligible),
+	string(ErrorAlreadyVoted),
+	string(ErrorDelegated),
+	string(ErrorRevokedSigner),
+	string(ErrorOutdated),
+	string(NotificationsSettings),
+	string(NotificationsManager),
+	string(NotificationsResponse),
+}
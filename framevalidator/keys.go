@@ -0,0 +1,164 @@
+package framevalidator
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"go.vocdoni.io/dvote/log"
+)
+
+const (
+	// keysCacheSize is the maximum number of FIDs whose app keys are cached
+	keysCacheSize = 10000
+	// keysRefreshInterval is the minimum time between two refreshes of the app
+	// keys of the same FID, to bound the requests to the provider caused by
+	// the keys that are not active
+	keysRefreshInterval = 30 * time.Second
+)
+
+// ErrKeysRefreshLimited is returned when the app keys of a FID cannot be
+// refreshed because they were refreshed less than keysRefreshInterval ago and
+// the previous refresh failed.
+var ErrKeysRefreshLimited = fmt.Errorf("app keys refreshed too recently")
+
+// ErrRevokedSigner is returned when the app key that signed a frame message is
+// not an active key of the signing FID.
+var ErrRevokedSigner = fmt.Errorf("revoked signer")
+
+// AppKeysProvider interface defines the methods required to get the active app
+// keys of a FID. It is implemented by the farcasterapi/web3.FarcasterProvider,
+// which reads them from the Farcaster KeyRegistry contract.
+type AppKeysProvider interface {
+	GetAppKeysByFid(fid *big.Int) ([][]byte, error)
+}
+
+// SignersAPI interface defines the method of the Farcaster APIs that lists the
+// hex encoded signers of a FID, such as the hub or the Neynar APIs.
+type SignersAPI interface {
+	SignersFromFID(fid uint64) ([]string, error)
+}
+
+// signersAPIProvider adapts a SignersAPI to the AppKeysProvider interface.
+type signersAPIProvider struct {
+	api SignersAPI
+}
+
+// FromSignersAPI returns an AppKeysProvider that gets the active app keys of
+// the FIDs from the Farcaster API provided.
+func FromSignersAPI(api SignersAPI) AppKeysProvider {
+	return &signersAPIProvider{api: api}
+}
+
+// GetAppKeysByFid returns the decoded signers of the FID provided.
+func (p *signersAPIProvider) GetAppKeysByFid(fid *big.Int) ([][]byte, error) {
+	signers, err := p.api.SignersFromFID(fid.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	keys := [][]byte{}
+	for _, signer := range signers {
+		key, err := hex.DecodeString(strings.TrimPrefix(signer, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid signer %s: %w", signer, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// KeysChecker struct checks that the app keys that sign the frame messages are
+// active keys of the signing FIDs, caching the active keys of every FID. In
+// strict mode, the keys that cannot be checked because the provider fails are
+// rejected, otherwise they are accepted. The keys of every FID are refreshed
+// at most once every keysRefreshInterval.
+type KeysChecker struct {
+	provider   AppKeysProvider
+	strict     bool
+	cache      *expirable.LRU[uint64, [][]byte]
+	refreshMtx sync.Mutex
+	refreshed  *expirable.LRU[uint64, struct{}]
+}
+
+// NewKeysChecker creates a new keys checker with the provider and the mode
+// provided, that caches the active keys of every FID for the time-to-live
+// provided.
+func NewKeysChecker(provider AppKeysProvider, strict bool, ttl time.Duration) *KeysChecker {
+	return &KeysChecker{
+		provider:  provider,
+		strict:    strict,
+		cache:     expirable.NewLRU[uint64, [][]byte](keysCacheSize, nil, ttl),
+		refreshed: expirable.NewLRU[uint64, struct{}](keysCacheSize, nil, keysRefreshInterval),
+	}
+}
+
+// Check checks that the public key provided is an active app key of the FID
+// provided. If the key is not in the cached keys of the FID, they are
+// refreshed from the provider, so the keys added after caching them are
+// accepted, unless they were refreshed less than keysRefreshInterval ago. It
+// returns ErrRevokedSigner if the key is not active. If the provider fails, or
+// the keys cannot be refreshed yet after a failure, the error is returned in
+// strict mode and ignored otherwise. A nil checker accepts every key.
+func (kc *KeysChecker) Check(fid uint64, pubKey ed25519.PublicKey) error {
+	if kc == nil {
+		return nil
+	}
+	keys, cached := kc.cache.Get(fid)
+	if cached && containsKey(keys, pubKey) {
+		return nil
+	}
+	if !kc.startRefresh(fid) {
+		if cached {
+			return ErrRevokedSigner
+		}
+		return kc.uncheckedSigner(fid, ErrKeysRefreshLimited)
+	}
+	keys, err := kc.provider.GetAppKeysByFid(new(big.Int).SetUint64(fid))
+	if err != nil {
+		return kc.uncheckedSigner(fid, err)
+	}
+	kc.cache.Add(fid, keys)
+	if !containsKey(keys, pubKey) {
+		return ErrRevokedSigner
+	}
+	return nil
+}
+
+// startRefresh returns if the keys of the FID provided can be refreshed, that
+// is, if they were not refreshed in the last keysRefreshInterval, and if so,
+// registers the refresh.
+func (kc *KeysChecker) startRefresh(fid uint64) bool {
+	kc.refreshMtx.Lock()
+	defer kc.refreshMtx.Unlock()
+	if _, ok := kc.refreshed.Get(fid); ok {
+		return false
+	}
+	kc.refreshed.Add(fid, struct{}{})
+	return true
+}
+
+// uncheckedSigner returns the error provided, that prevents checking the
+// signer of the FID provided, in strict mode, and ignores it otherwise.
+func (kc *KeysChecker) uncheckedSigner(fid uint64, err error) error {
+	if kc.strict {
+		return fmt.Errorf("could not check the signer: %w", err)
+	}
+	log.Warnw("could not check the signer, accepting it", "fid", fid, "error", err)
+	return nil
+}
+
+// containsKey returns if the public key provided is in the keys provided.
+func containsKey(keys [][]byte, pubKey ed25519.PublicKey) bool {
+	for _, key := range keys {
+		if bytes.Equal(key, pubKey) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package framevalidator
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+// testKeysProvider implements the AppKeysProvider interface with the keys
+// provided, returning the error provided if any.
+type testKeysProvider struct {
+	keys     map[uint64][][]byte
+	err      error
+	requests int
+}
+
+func (p *testKeysProvider) GetAppKeysByFid(fid *big.Int) ([][]byte, error) {
+	p.requests++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.keys[fid.Uint64()], nil
+}
+
+func TestKeysChecker(t *testing.T) {
+	c := qt.New(t)
+
+	pubKey, _, err := ed25519.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	provider := &testKeysProvider{keys: map[uint64][][]byte{}}
+	checker := NewKeysChecker(provider, true, time.Minute)
+
+	// the keys not owned by the fid are rejected, and they are not refreshed
+	// again until the refresh interval passes
+	c.Assert(checker.Check(10, pubKey), qt.ErrorIs, ErrRevokedSigner)
+	provider.keys[10] = [][]byte{pubKey}
+	c.Assert(checker.Check(10, pubKey), qt.ErrorIs, ErrRevokedSigner)
+	c.Assert(provider.requests, qt.Equals, 1)
+
+	// the keys added are accepted and cached
+	checker.refreshed.Purge()
+	c.Assert(checker.Check(10, pubKey), qt.IsNil)
+	c.Assert(checker.Check(10, pubKey), qt.IsNil)
+	c.Assert(provider.requests, qt.Equals, 2)
+
+	// the provider errors are returned in strict mode and ignored otherwise,
+	// and the failed refreshes are not retried until the interval passes
+	provider.err = fmt.Errorf("rpc down")
+	checker = NewKeysChecker(provider, true, time.Minute)
+	c.Assert(checker.Check(10, pubKey), qt.ErrorMatches, ".*rpc down")
+	c.Assert(checker.Check(10, pubKey), qt.ErrorIs, ErrKeysRefreshLimited)
+	c.Assert(provider.requests, qt.Equals, 3)
+	checker = NewKeysChecker(provider, false, time.Minute)
+	c.Assert(checker.Check(10, pubKey), qt.IsNil)
+	c.Assert(checker.Check(10, pubKey), qt.IsNil)
+	c.Assert(provider.requests, qt.Equals, 4)
+
+	// a nil checker accepts every key
+	var nilChecker *KeysChecker
+	c.Assert(nilChecker.Check(10, pubKey), qt.IsNil)
+
+	// the signers of the farcaster APIs are decoded
+	keys, err := FromSignersAPI(testSignersAPI{"0x" + hex.EncodeToString(pubKey)}).GetAppKeysByFid(big.NewInt(10))
+	c.Assert(err, qt.IsNil)
+	c.Assert(keys, qt.DeepEquals, [][]byte{pubKey})
+}
+
+// testSignersAPI implements the SignersAPI interface returning the signers
+// provided for every FID.
+type testSignersAPI []string
+
+func (s testSignersAPI) SignersFromFID(uint64) ([]string, error) {
+	return s, nil
+}
@@ -0,0 +1,184 @@
+// framevalidator package validates the signed Farcaster frame messages
+// received by the frame server locally. It verifies the structure of the frame
+// signature packets, the Ed25519 signature of the frame messages and that the
+// signing app keys are owned by the FIDs that sign them. External validators
+// can be plugged in as reporters, which receive every message validated but do
+// not affect the result.
+package framevalidator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/vochain/transaction/proofs/farcasterproof"
+)
+
+const (
+	// farcasterEpoch is the Unix timestamp of the Farcaster epoch, the origin
+	// of the timestamps of the Farcaster messages
+	farcasterEpoch int64 = 1609459200 // January 1, 2021 UTC
+	// reportTimeout is the maximum time that a reporter can take to report a
+	// frame message
+	reportTimeout = 30 * time.Second
+)
+
+var (
+	// ErrInvalidPacket is returned when the frame signature packet or the
+	// frame message are malformed or inconsistent.
+	ErrInvalidPacket = fmt.Errorf("invalid frame packet")
+	// ErrInvalidSignature is returned when the signature of the frame message
+	// cannot be verified.
+	ErrInvalidSignature = fmt.Errorf("invalid frame signature")
+)
+
+// Reporter interface defines the external validators that can be plugged into
+// the validator to receive the frame messages validated, for example for
+// analytics. Their errors are logged and do not affect the validation result.
+type Reporter interface {
+	ReportFrameMessage(ctx context.Context, messageBytes []byte) error
+}
+
+// Packet struct mirrors the JSON structure of the frame signature packets
+// received by the frame server, including only the fields checked against the
+// signed message.
+type Packet struct {
+	UntrustedData struct {
+		FID         uint64 `json:"fid"`
+		ButtonIndex uint32 `json:"buttonIndex"`
+	} `json:"untrustedData"`
+	TrustedData struct {
+		MessageBytes string `json:"messageBytes"`
+	} `json:"trustedData"`
+}
+
+// Result struct contains the data of a frame message validated. All its
+// fields come from the signed message, so they can be trusted.
+type Result struct {
+	FID          uint64
+	PubKey       ed25519.PublicKey
+	ButtonIndex  uint32
+	InputText    string
+	URL          string
+	State        []byte
+	CastFID      uint64
+	CastHash     []byte
+	Timestamp    time.Time
+	MessageBytes []byte
+}
+
+// Config struct contains the configuration of a frame validator. If no keys
+// checker is provided, the ownership of the signing app keys is not checked.
+type Config struct {
+	Keys      *KeysChecker
+	Reporters []Reporter
+}
+
+// Validator struct validates the frame messages received by the frame server
+// and reports them to the reporters configured.
+type Validator struct {
+	keys      *KeysChecker
+	reporters []Reporter
+}
+
+// New creates a new frame validator with the configuration provided.
+func New(conf *Config) *Validator {
+	if conf == nil {
+		conf = &Config{}
+	}
+	return &Validator{
+		keys:      conf.Keys,
+		reporters: conf.Reporters,
+	}
+}
+
+// Validate decodes the frame signature packet provided and validates it. It
+// checks that the signed message is a frame action signed with a valid Ed25519
+// signature, that the untrusted data of the packet matches the signed one and
+// that the signing app key is owned by the signing FID. It returns the data of
+// the signed message or an error that wraps ErrInvalidPacket,
+// ErrInvalidSignature or ErrRevokedSigner. The packet is reported to the
+// reporters in the background in any case.
+func (v *Validator) Validate(body []byte) (*Result, error) {
+	packet := &Packet{}
+	if err := json.Unmarshal(body, packet); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPacket, err)
+	}
+	messageBytes, err := hex.DecodeString(packet.TrustedData.MessageBytes)
+	if err != nil || len(messageBytes) == 0 {
+		return nil, fmt.Errorf("%w: invalid message bytes", ErrInvalidPacket)
+	}
+	v.report(messageBytes)
+	// verify the signature and decode the signed message
+	action, pubKey, fid, err := farcasterproof.VerifyFrameSignature(messageBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	_, msg, err := farcasterproof.DecodeMessage(messageBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPacket, err)
+	}
+	// check the structure of the signed message and its consistency with the
+	// untrusted data of the packet
+	if fid == 0 || action.ButtonIndex == 0 {
+		return nil, fmt.Errorf("%w: missing fid or button index", ErrInvalidPacket)
+	}
+	if packet.UntrustedData.FID != 0 && packet.UntrustedData.FID != fid {
+		return nil, fmt.Errorf("%w: untrusted fid %d does not match signed fid %d",
+			ErrInvalidPacket, packet.UntrustedData.FID, fid)
+	}
+	if packet.UntrustedData.ButtonIndex != 0 && packet.UntrustedData.ButtonIndex != action.ButtonIndex {
+		return nil, fmt.Errorf("%w: untrusted button %d does not match signed button %d",
+			ErrInvalidPacket, packet.UntrustedData.ButtonIndex, action.ButtonIndex)
+	}
+	// check the ownership of the signing key
+	if err := v.keys.Check(fid, pubKey); err != nil {
+		return nil, err
+	}
+	result := &Result{
+		FID:          fid,
+		PubKey:       pubKey,
+		ButtonIndex:  action.ButtonIndex,
+		InputText:    string(action.InputText),
+		URL:          string(action.Url),
+		State:        action.State,
+		Timestamp:    time.Unix(farcasterEpoch+int64(msg.Data.Timestamp), 0),
+		MessageBytes: messageBytes,
+	}
+	if castID := action.GetCastId(); castID != nil {
+		result.CastFID = castID.Fid
+		result.CastHash = castID.Hash
+	}
+	return result, nil
+}
+
+// Report reports the frame signature packet provided to the reporters in the
+// background, without validating it. It is intended for the frames that do
+// not require a valid message.
+func (v *Validator) Report(body []byte) {
+	packet := &Packet{}
+	if err := json.Unmarshal(body, packet); err != nil {
+		return
+	}
+	if messageBytes, err := hex.DecodeString(packet.TrustedData.MessageBytes); err == nil && len(messageBytes) > 0 {
+		v.report(messageBytes)
+	}
+}
+
+// report sends the message provided to every reporter in the background,
+// logging their errors.
+func (v *Validator) report(messageBytes []byte) {
+	for _, reporter := range v.reporters {
+		go func(reporter Reporter) {
+			ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+			defer cancel()
+			if err := reporter.ReportFrameMessage(ctx, messageBytes); err != nil {
+				log.Warnw("failed to report frame message", "error", err)
+			}
+		}(reporter)
+	}
+}
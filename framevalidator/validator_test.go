@@ -0,0 +1,141 @@
+package framevalidator
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/zeebo/blake3"
+	farcasterpb "go.vocdoni.io/dvote/vochain/transaction/proofs/farcasterproof/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// testReporter implements the Reporter interface recording the messages
+// reported.
+type testReporter struct {
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+	messages [][]byte
+}
+
+func (r *testReporter) ReportFrameMessage(_ context.Context, messageBytes []byte) error {
+	defer r.wg.Done()
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.messages = append(r.messages, messageBytes)
+	return nil
+}
+
+// signedPacket returns the JSON frame signature packet of a frame action of
+// the FID and button provided signed with the private key provided, and its
+// untrusted data set to the untrusted FID provided.
+func signedPacket(c *qt.C, privKey ed25519.PrivateKey, fid, untrustedFID uint64, button uint32) []byte {
+	data := &farcasterpb.MessageData{
+		Type:      farcasterpb.MessageType_MESSAGE_TYPE_FRAME_ACTION,
+		Fid:       fid,
+		Timestamp: uint32(time.Now().Unix() - farcasterEpoch),
+		Network:   farcasterpb.FarcasterNetwork_FARCASTER_NETWORK_MAINNET,
+		Body: &farcasterpb.MessageData_FrameActionBody{
+			FrameActionBody: &farcasterpb.FrameActionBody{
+				Url:         []byte("https://farcaster.vote/abcd"),
+				ButtonIndex: button,
+				State:       []byte(`{"ProcessID":"abcd"}`),
+				CastId:      &farcasterpb.CastId{Fid: 3, Hash: []byte{0x01}},
+			},
+		},
+	}
+	dataBytes, err := proto.Marshal(data)
+	c.Assert(err, qt.IsNil)
+	digest := blake3.Sum256(dataBytes)
+	hash := digest[:20]
+	msgBytes, err := proto.Marshal(&farcasterpb.Message{
+		Data:            data,
+		Hash:            hash,
+		HashScheme:      farcasterpb.HashScheme_HASH_SCHEME_BLAKE3,
+		Signature:       ed25519.Sign(privKey, hash),
+		SignatureScheme: farcasterpb.SignatureScheme_SIGNATURE_SCHEME_ED25519,
+		Signer:          privKey.Public().(ed25519.PublicKey),
+	})
+	c.Assert(err, qt.IsNil)
+	packet := &Packet{}
+	packet.UntrustedData.FID = untrustedFID
+	packet.UntrustedData.ButtonIndex = button
+	packet.TrustedData.MessageBytes = hex.EncodeToString(msgBytes)
+	body, err := json.Marshal(packet)
+	c.Assert(err, qt.IsNil)
+	return body
+}
+
+func TestValidate(t *testing.T) {
+	c := qt.New(t)
+
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	reporter := &testReporter{}
+	v := New(&Config{Reporters: []Reporter{reporter}})
+
+	// a valid message is decoded and reported
+	reporter.wg.Add(1)
+	result, err := v.Validate(signedPacket(c, privKey, 10, 10, 2))
+	c.Assert(err, qt.IsNil)
+	c.Assert(result.FID, qt.Equals, uint64(10))
+	c.Assert(result.PubKey, qt.DeepEquals, pubKey)
+	c.Assert(result.ButtonIndex, qt.Equals, uint32(2))
+	c.Assert(result.URL, qt.Equals, "https://farcaster.vote/abcd")
+	c.Assert(string(result.State), qt.Equals, `{"ProcessID":"abcd"}`)
+	c.Assert(result.CastFID, qt.Equals, uint64(3))
+	c.Assert(time.Since(result.Timestamp) < time.Minute, qt.IsTrue)
+	reporter.wg.Wait()
+	c.Assert(reporter.messages, qt.HasLen, 1)
+	c.Assert(reporter.messages[0], qt.DeepEquals, result.MessageBytes)
+
+	// the untrusted data must match the signed data
+	reporter.wg.Add(1)
+	_, err = v.Validate(signedPacket(c, privKey, 10, 11, 2))
+	c.Assert(err, qt.ErrorIs, ErrInvalidPacket)
+	reporter.wg.Wait()
+
+	// a tampered message is rejected
+	packet := &Packet{}
+	c.Assert(json.Unmarshal(signedPacket(c, privKey, 10, 10, 2), packet), qt.IsNil)
+	messageBytes, err := hex.DecodeString(packet.TrustedData.MessageBytes)
+	c.Assert(err, qt.IsNil)
+	messageBytes[len(messageBytes)-1] ^= 0xff
+	packet.TrustedData.MessageBytes = hex.EncodeToString(messageBytes)
+	body, err := json.Marshal(packet)
+	c.Assert(err, qt.IsNil)
+	reporter.wg.Add(1)
+	_, err = v.Validate(body)
+	c.Assert(err, qt.ErrorIs, ErrInvalidSignature)
+	reporter.wg.Wait()
+
+	// a malformed packet is rejected without reporting it
+	_, err = v.Validate([]byte(`{"trustedData":{"messageBytes":"zz"}}`))
+	c.Assert(err, qt.ErrorIs, ErrInvalidPacket)
+}
+
+func TestValidateKeys(t *testing.T) {
+	c := qt.New(t)
+
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	c.Assert(err, qt.IsNil)
+	provider := &testKeysProvider{keys: map[uint64][][]byte{}}
+	checker := NewKeysChecker(provider, true, time.Minute)
+	v := New(&Config{Keys: checker})
+
+	// the frame messages signed by keys not owned by the fid are rejected
+	_, err = v.Validate(signedPacket(c, privKey, 10, 10, 1))
+	c.Assert(err, qt.ErrorIs, ErrRevokedSigner)
+
+	// the frame messages signed by the keys added are accepted once the keys
+	// can be refreshed
+	provider.keys[10] = [][]byte{pubKey}
+	checker.refreshed.Purge()
+	_, err = v.Validate(signedPacket(c, privKey, 10, 10, 1))
+	c.Assert(err, qt.IsNil)
+}
@@ -6,6 +6,7 @@ go 1.23.0
 
 require (
 	github.com/Khan/genqlient v0.6.0
+	github.com/VictoriaMetrics/metrics v1.24.0
 	github.com/ethereum/go-ethereum v1.14.7
 	github.com/frankban/quicktest v1.14.6
 	github.com/google/uuid v1.6.0
@@ -19,6 +20,7 @@ require (
 	go.mongodb.org/mongo-driver v1.14.0
 	go.vocdoni.io/dvote v1.10.2-0.20240823065813-50c3f988683c
 	go.vocdoni.io/proto v1.15.10-0.20240807160537-0161d6191151
+	golang.org/x/sync v0.8.0
 	google.golang.org/protobuf v1.34.2
 )
 
@@ -31,7 +33,6 @@ require (
 	github.com/DataDog/zstd v1.5.2 // indirect
 	github.com/Jorropo/jsync v1.0.1 // indirect
 	github.com/Microsoft/go-winio v0.6.2 // indirect
-	github.com/VictoriaMetrics/metrics v1.24.0 // indirect
 	github.com/agnivade/levenshtein v1.1.1 // indirect
 	github.com/alecthomas/units v0.0.0-20231202071711-9a357b53e9c9 // indirect
 	github.com/alexbrainman/goissue34681 v0.0.0-20191006012335-3fc7a47baff5 // indirect
@@ -331,7 +332,6 @@ require (
 	golang.org/x/mod v0.20.0 // indirect
 	golang.org/x/net v0.28.0 // indirect
 	golang.org/x/oauth2 v0.22.0 // indirect
-	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.23.0 // indirect
 	golang.org/x/text v0.17.0 // indirect
 	golang.org/x/tools v0.24.0 // indirect
@@ -19,9 +19,15 @@ import (
 	"github.com/vocdoni/vote-frame/airstack"
 	"github.com/vocdoni/vote-frame/communityhub"
 	"github.com/vocdoni/vote-frame/farcasterapi"
+	"github.com/vocdoni/vote-frame/frameanalytics"
+	"github.com/vocdoni/vote-frame/frametemplates"
+	"github.com/vocdoni/vote-frame/framevalidator"
 	"github.com/vocdoni/vote-frame/helpers"
 	"github.com/vocdoni/vote-frame/imageframe"
+	"github.com/vocdoni/vote-frame/miniapp"
 	"github.com/vocdoni/vote-frame/mongo"
+	"github.com/vocdoni/vote-frame/openframes"
+	"github.com/vocdoni/vote-frame/quota"
 	"github.com/vocdoni/vote-frame/reputation"
 	"github.com/vocdoni/vote-frame/shortener"
 	"go.vocdoni.io/dvote/api"
@@ -47,6 +53,12 @@ type vocdoniHandler struct {
 	census3       *c3cli.HTTPclient
 	comhub        *communityhub.CommunityHub
 	repUpdater    *reputation.Updater
+	quotas        *quota.Limiter
+	frames        *framevalidator.Validator
+	templates     *frametemplates.Renderer
+	miniApp       *miniapp.Manifest
+	openFrames    *openframes.Adapter
+	analytics     *frameanalytics.Recorder
 
 	backgroundQueue  sync.Map
 	addAuthTokenFunc func(uint64, string)
@@ -66,6 +78,9 @@ func NewVocdoniHandler(
 	census3 *c3cli.HTTPclient,
 	comhub *communityhub.CommunityHub,
 	repUpdater *reputation.Updater,
+	quotas *quota.Limiter,
+	frames *framevalidator.Validator,
+	templates *frametemplates.Renderer,
 	adminFID uint64,
 ) (*vocdoniHandler, error) {
 	// Get the vocdoni account
@@ -106,6 +121,11 @@ func NewVocdoniHandler(
 		census3:       census3,
 		comhub:        comhub,
 		repUpdater:    repUpdater,
+		quotas:        quotas,
+		frames:        frames,
+		templates:     templates,
+		openFrames:    openframes.New(),
+		analytics:     frameanalytics.New(db, frameanalytics.DefaultFlushInterval),
 		adminFID:      adminFID,
 		electionLRU: func() *lru.Cache[string, *api.Election] {
 			lru, err := lru.New[string, *api.Election](100)
@@ -119,6 +139,7 @@ func NewVocdoniHandler(
 	// Add the election callback to the mongo database to fetch the election information
 	db.AddElectionCallback(vh.election)
 	go finalizeElectionsAtBackround(ctx, vh)
+	vh.analytics.Start(ctx)
 	return vh, ensureAccountExist(cli)
 }
 
@@ -137,10 +158,8 @@ func (v *vocdoniHandler) landing(msg *apirest.APIdata, ctx *httprouter.HTTPConte
 		return nil
 	}
 
-	// validate the frame package to airstack
-	if v.airstack != nil {
-		airstack.ValidateFrameMessage(msg.Data, v.airstack.ApiKey())
-	}
+	// report the frame message to the external validators
+	v.frames.Report(msg.Data)
 
 	election, err := v.election(electionID)
 	if err != nil {
@@ -151,13 +170,17 @@ func (v *vocdoniHandler) landing(msg *apirest.APIdata, ctx *httprouter.HTTPConte
 		return fmt.Errorf("election has no questions")
 	}
 
-	response := strings.ReplaceAll(frame(frameMain), "{processID}", election.ElectionID.String())
-	response = strings.ReplaceAll(response, "{title}", metadata.Title["default"])
-	response = strings.ReplaceAll(response, "{image}", landingPNGfile(election))
-
-	ctx.SetResponseContentType("text/html; charset=utf-8")
+	image := landingPNGfile(election)
 	ctx.SetHeader("Cache-Control", "no-cache, max-age=0")
-	return ctx.Send([]byte(response), http.StatusOK)
+	return v.sendFrame(ctx, electionID, frametemplates.Main{
+		Page: frametemplates.Page{
+			Image: image,
+			Title: metadata.Title["default"],
+		},
+		ProcessID: election.ElectionID.String(),
+		Embed:     v.pollEmbed(electionID, image),
+		Accepts:   v.acceptedProtocols(electionID),
+	})
 }
 
 func landingPNGfile(election *api.Election) string {
@@ -170,10 +193,8 @@ func landingPNGfile(election *api.Election) string {
 }
 
 func (v *vocdoniHandler) info(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
-	// validate the frame package to airstack
-	if v.airstack != nil {
-		airstack.ValidateFrameMessage(msg.Data, v.airstack.ApiKey())
-	}
+	// report the frame message to the external validators
+	v.frames.Report(msg.Data)
 
 	// get the electionID from the URL and fetch the election from the vochain
 	electionID := ctx.URLParam("electionID")
@@ -244,11 +265,13 @@ func (v *vocdoniHandler) info(msg *apirest.APIdata, ctx *httprouter.HTTPContext)
 	}
 
 	// send the response
-	response := strings.ReplaceAll(frame(frameInfo), "{image}", imageLink(png))
-	response = strings.ReplaceAll(response, "{title}", title)
-	response = strings.ReplaceAll(response, "{processID}", electionID)
-	ctx.SetResponseContentType("text/html; charset=utf-8")
-	return ctx.Send([]byte(response), http.StatusOK)
+	return v.sendFrame(ctx, electionIDbytes, frametemplates.Info{
+		Page: frametemplates.Page{
+			Image: imageLink(png),
+			Title: title,
+		},
+		ProcessID: electionID,
+	})
 }
 
 func (v *vocdoniHandler) staticHandler(w http.ResponseWriter, r *http.Request) {
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -9,6 +10,15 @@ import (
 	"go.vocdoni.io/dvote/log"
 )
 
+const (
+	// DefaultVoteOverwrites is the number of times that the voters can change
+	// their vote in the elections that allow it, if no number is provided.
+	DefaultVoteOverwrites = 1
+	// MaxVoteOverwrites is the maximum number of times that the voters can
+	// change their vote in an election.
+	MaxVoteOverwrites = 10
+)
+
 // ExtractResults extracts the choices and results from an election. It returns nil if there is an issue processing the data.
 func ExtractResults(election *api.Election, censusTokenDecimals uint32) (choices []string, results []*big.Int) {
 	if election == nil || election.Metadata == nil || election.Results == nil {
@@ -145,3 +155,38 @@ func NormalizeAddressStringSlice(addresses []string) []string {
 	}
 	return normalizedAddresses
 }
+
+// ValidateVoteOverwrites checks the number of times that the voters can change
+// their vote requested for an election, which requires the overwrite to be
+// enabled and cannot be greater than MaxVoteOverwrites. Zero means the
+// default number.
+func ValidateVoteOverwrites(overwrite bool, maxOverwrites uint32) error {
+	if maxOverwrites > 0 && !overwrite {
+		return fmt.Errorf("max overwrites requires overwrite to be enabled")
+	}
+	if maxOverwrites > MaxVoteOverwrites {
+		return fmt.Errorf("max overwrites cannot be greater than %d", MaxVoteOverwrites)
+	}
+	return nil
+}
+
+// VoteOverwrites returns the number of times that the voters can change their
+// vote in an election: none if the overwrite is not enabled,
+// DefaultVoteOverwrites if no number is provided and up to MaxVoteOverwrites
+// otherwise.
+func VoteOverwrites(overwrite bool, maxOverwrites uint32) uint32 {
+	if !overwrite {
+		return 0
+	}
+	if maxOverwrites == 0 {
+		return DefaultVoteOverwrites
+	}
+	return min(maxOverwrites, MaxVoteOverwrites)
+}
+
+// CanOverwriteVote returns if a voter that already voted, whose vote has been
+// overwritten the number of times provided, can change it again in an
+// election that allows the number of overwrites provided.
+func CanOverwriteVote(overwrites, maxOverwrites uint32) bool {
+	return overwrites < maxOverwrites
+}
@@ -140,3 +140,28 @@ func TestExtractResults(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateVoteOverwrites(t *testing.T) {
+	assert.NoError(t, ValidateVoteOverwrites(false, 0))
+	assert.NoError(t, ValidateVoteOverwrites(true, 0))
+	assert.NoError(t, ValidateVoteOverwrites(true, MaxVoteOverwrites))
+	// the max overwrites requires the overwrite to be enabled
+	assert.Error(t, ValidateVoteOverwrites(false, 1))
+	assert.Error(t, ValidateVoteOverwrites(true, MaxVoteOverwrites+1))
+}
+
+func TestVoteOverwrites(t *testing.T) {
+	assert.Equal(t, uint32(0), VoteOverwrites(false, 5))
+	assert.Equal(t, uint32(DefaultVoteOverwrites), VoteOverwrites(true, 0))
+	assert.Equal(t, uint32(5), VoteOverwrites(true, 5))
+	assert.Equal(t, uint32(MaxVoteOverwrites), VoteOverwrites(true, MaxVoteOverwrites+1))
+}
+
+func TestCanOverwriteVote(t *testing.T) {
+	// the votes cannot be overwritten if the election does not allow it
+	assert.False(t, CanOverwriteVote(0, 0))
+	assert.True(t, CanOverwriteVote(0, 1))
+	assert.False(t, CanOverwriteVote(1, 1))
+	assert.True(t, CanOverwriteVote(2, 3))
+	assert.False(t, CanOverwriteVote(4, 3))
+}
@@ -6,9 +6,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"regexp"
 	"strings"
 
+	"github.com/vocdoni/vote-frame/features"
 	"github.com/vocdoni/vote-frame/helpers"
 	"github.com/vocdoni/vote-frame/imageframe"
 	"github.com/vocdoni/vote-frame/mongo"
@@ -24,6 +26,15 @@ var (
 
 func (v *vocdoniHandler) imagesHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
 	id := ctx.URLParam("id")
+	v.recordImageImpression(id)
+	// the vote receipt images are created again if they are not cached
+	if electionID, nullifier, ok := imageframe.ParseReceiptImageID(id); ok {
+		png, err := v.receiptImage(electionID, nullifier)
+		if err != nil {
+			return errorImageResponse(ctx, err)
+		}
+		return imageResponse(ctx, png)
+	}
 	data := imageframe.FromCache(id)
 	if data != nil {
 		return imageResponse(ctx, data)
@@ -123,6 +134,10 @@ func (v *vocdoniHandler) updloadAvatarHandler(msg *apirest.APIdata, ctx *httprou
 	if err := json.Unmarshal(msg.Data, &req); err != nil {
 		return fmt.Errorf("cannot parse request: %w", err)
 	}
+	// check if the user has enough reputation to upload custom images
+	if !v.featureAllowed(features.CUSTOM_IMAGES, req.CommunityID, userFID) {
+		return ctx.Send([]byte("user does not have enough reputation to upload custom images"), http.StatusForbidden)
+	}
 	// upload the avatar and return the URL
 	avatarURL, err := v.uploadAvatar(req.AvatarID, userFID, req.CommunityID, req.Data)
 	if err != nil {
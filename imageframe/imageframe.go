@@ -2,12 +2,14 @@ package imageframe
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"path"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -16,6 +18,8 @@ import (
 	"github.com/vocdoni/vote-frame/mongo"
 	"go.vocdoni.io/dvote/api"
 	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -38,10 +42,17 @@ const (
 	imageTypePreview
 )
 
+// receiptImageInfix separates the election ID and the nullifier in the ID of
+// the vote receipt images.
+const receiptImageInfix = "_receipt_"
+
 var (
 	backgroundFrames           map[string][]byte
 	imagesLRU                  *lru.Cache[string, []byte]
 	hitsCounter, missesCounter atomic.Int64
+	// receiptGenerations coalesces the concurrent generations of the same
+	// vote receipt image
+	receiptGenerations singleflight.Group
 )
 
 func init() {
@@ -133,6 +144,66 @@ func InfoImage(infoLines []string) (string, error) {
 	return imgCacheKey, nil
 }
 
+// ReceiptImageID returns the ID of the image with the receipt of the vote of
+// the election and the nullifier provided. The ID is prefixed by the election
+// ID and it is stable, so it can be shared and the image can be created again
+// if it is not in the cache.
+func ReceiptImageID(electionID, nullifier types.HexBytes) string {
+	return electionID.String() + receiptImageInfix + nullifier.String()
+}
+
+// ParseReceiptImageID returns the election ID and the nullifier of the vote
+// receipt image ID provided, and false if it is not a receipt image ID.
+func ParseReceiptImageID(id string) (types.HexBytes, types.HexBytes, bool) {
+	electionHex, nullifierHex, ok := strings.Cut(id, receiptImageInfix)
+	if !ok {
+		return nil, nil, false
+	}
+	electionID, err := hex.DecodeString(electionHex)
+	if err != nil || len(electionID) != types.ProcessIDsize {
+		return nil, nil, false
+	}
+	nullifier, err := hex.DecodeString(nullifierHex)
+	if err != nil || len(nullifier) == 0 {
+		return nil, nil, false
+	}
+	return electionID, nullifier, true
+}
+
+// ReceiptImage returns the image with the receipt of the vote of the election
+// and the nullifier provided. If it is not in the cache, it is created with
+// the lines returned by the function provided and cached with its
+// ReceiptImageID. The concurrent requests of the same receipt share a single
+// generation.
+func ReceiptImage(electionID, nullifier types.HexBytes, lines func() ([]string, error)) ([]byte, error) {
+	id := ReceiptImageID(electionID, nullifier)
+	if png, ok := imagesLRU.Get(id); ok {
+		hitsCounter.Add(1)
+		return png, nil
+	}
+	missesCounter.Add(1)
+	png, err, _ := receiptGenerations.Do(id, func() (any, error) {
+		info, err := lines()
+		if err != nil {
+			return nil, err
+		}
+		png, err := makeRequest(ImageRequest{
+			Type:  "info",
+			Title: "Vote receipt",
+			Info:  info,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create image: %w", err)
+		}
+		AddImageToCacheWithID(id, png)
+		return png, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return png.([]byte), nil
+}
+
 // QuestionImage creates an image representing a question with choices.
 func QuestionImage(election *api.Election) (string, error) {
 	if election == nil || election.Metadata == nil {
@@ -270,6 +341,31 @@ func AlreadyVotedImage() string {
 	return emptyBodyImage("alreadyvoted")
 }
 
+// ChangeVoteImage creates an image to be displayed when a user has already
+// voted but can still change their vote the number of times provided.
+func ChangeVoteImage(overwritesLeft uint32) string {
+	times := "once more"
+	if overwritesLeft > 1 {
+		times = fmt.Sprintf("%d more times", overwritesLeft)
+	}
+	requestData := ImageRequest{
+		Type:  "info",
+		Title: "You already voted",
+		Info:  []string{"You can change your vote " + times},
+	}
+	imgCacheKey := oneTimeImageCacheKey()
+	go func() {
+		png, err := makeRequest(requestData)
+		if err != nil {
+			log.Errorw(fmt.Errorf("failed to create image: %w", err), "change vote image")
+			return
+		}
+		AddImageToCacheWithID(imgCacheKey, png)
+	}()
+	time.Sleep(1 * time.Second)
+	return imgCacheKey
+}
+
 // NotElegibleImage creates a static image to be displayed when a user is not elegible to vote.
 func NotElegibleImage() string {
 	return emptyBodyImage("noteligible")
@@ -280,6 +376,23 @@ func AlreadyDelegated() string {
 	return notEligibleImage("You cannot vote as your voting power was delegated for this community poll")
 }
 
+// RevokedSignerImage creates a static image to be displayed when the app key that signed the vote has been revoked.
+func RevokedSignerImage() string {
+	return notEligibleImage("You cannot vote as the app key you are using was revoked, sign in again with your Farcaster client")
+}
+
+// ExpiredFrameImage creates a static image to be displayed when a frame message is outdated.
+func ExpiredFrameImage() string {
+	png, _ := ErrorImage("This frame is outdated, reload the poll to vote")
+	return png
+}
+
+// ForeignFrameImage creates a static image to be displayed when a frame message was created for another poll.
+func ForeignFrameImage() string {
+	png, _ := ErrorImage("This frame does not belong to this poll")
+	return png
+}
+
 // NotFoundImage creates a static image to be displayed when an election is not found.
 func NotFoundImage() string {
 	png, _ := ErrorImage("Election not found")
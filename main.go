@@ -23,12 +23,20 @@ import (
 	"github.com/vocdoni/vote-frame/communityhub"
 	"github.com/vocdoni/vote-frame/discover"
 	"github.com/vocdoni/vote-frame/farcasterapi"
+	"github.com/vocdoni/vote-frame/farcasterapi/composite"
+	"github.com/vocdoni/vote-frame/farcasterapi/fake"
 	"github.com/vocdoni/vote-frame/farcasterapi/hub"
 	"github.com/vocdoni/vote-frame/farcasterapi/neynar"
+	fcweb3 "github.com/vocdoni/vote-frame/farcasterapi/web3"
 	"github.com/vocdoni/vote-frame/features"
+	"github.com/vocdoni/vote-frame/frameanalytics"
+	"github.com/vocdoni/vote-frame/frametemplates"
+	"github.com/vocdoni/vote-frame/framevalidator"
 	"github.com/vocdoni/vote-frame/helpers"
+	"github.com/vocdoni/vote-frame/miniapp"
 	"github.com/vocdoni/vote-frame/mongo"
 	"github.com/vocdoni/vote-frame/notifications"
+	"github.com/vocdoni/vote-frame/quota"
 	"github.com/vocdoni/vote-frame/reputation"
 	urlapi "go.vocdoni.io/dvote/api"
 	"go.vocdoni.io/dvote/httprouter"
@@ -41,6 +49,8 @@ var (
 	explorerURL       = "https://dev.explorer.vote"
 	onvoteURL         = "https://dev.onvote.app"
 	maxDirectMessages = uint64(10000)
+	frameMaxAge       = 10 * time.Minute
+	frameURLCheck     = true
 )
 
 func main() {
@@ -82,9 +92,14 @@ func main() {
 	flag.Uint64("botFid", 0, "FID to be used for the bot")
 	flag.String("botPrivKey", "", "The bot private key to use for signing the vote (hex)")
 	flag.String("botHubEndpoint", "", "The hub endpoint to use")
+	flag.Bool("botStreamMentions", false, "Stream the bot mentions from the hub events instead of polling them (requires botHubEndpoint)")
 	flag.String("neynarAPIKey", "", "neynar API key")
 	flag.String("neynarSignerUUID", "", "neynar signer UUID")
 	flag.String("neynarWebhookSecret", "", "neynar Webhook shared secret")
+	flag.String("farcasterFallbackHub", "", "A hub endpoint to fall back to when the Neynar API fails (its channel members are the casters and followers of the channel, never removed, instead of the current followers)")
+	flag.String("farcasterChannelFollowersAPI", "https://api.warpcast.com/v1/channel-followers", "The API endpoint to get the channel followers from when using a hub (empty to disable it)")
+	flag.Bool("fakeFarcasterAPI", false, "Use an in-memory fake Farcaster API instead of Neynar, for local development and testing")
+	flag.String("fakeFarcasterSeed", "", "The JSON file with the users and channels to populate the fake Farcaster API")
 
 	// Airstack flags
 	flag.String("airstackAPIEndpoint", "https://api.airstack.xyz/gql", "The Airstack API endpoint to use")
@@ -97,8 +112,25 @@ func main() {
 	// Limited features flags
 	flag.Int32("featureNotificationReputation", 15, "Reputation threshold to enable the notification feature")
 	flag.Int32("maxDirectMessages", 10000, "The maximum number of direct messages that any user can send. It will be scaled based on the reputation of the user.")
-	flag.Duration("reputationUpdateInterval", time.Hour*6, "The interval to update the reputation of the users")
+	flag.Duration("reputationUpdateInterval", time.Hour*6, "The interval to fully rebuild the reputation of the users and communities")
+	flag.Duration("reputationIncrementalUpdateInterval", time.Minute*10, "The interval to update the reputation of the users and communities with new activity")
 	flag.Int("concurrentReputationUpdates", 5, "The number of concurrent reputation updates")
+	flag.String("reputationBoostersConfig", "./boosters_config.json", "The JSON configuration file for the reputation boosters")
+	flag.Duration("reputationActivityHalfLife", time.Hour*24*180, "The half-life of the user activity decay in the reputation (0 to disable the decay)")
+	flag.Int("pollsQuota", quota.DefaultLimits[quota.PollCreation].Max, "The number of polls that a user can create per quota window. It will be scaled based on the reputation of the user (0 to disable the limit)")
+	flag.Int("censusesQuota", quota.DefaultLimits[quota.CensusCreation].Max, "The number of CSV and channel censuses that a user can create per quota window. It will be scaled based on the reputation of the user (0 to disable the limit)")
+	flag.Duration("quotaWindow", time.Hour, "The sliding time window of the polls and censuses quotas")
+	flag.Duration("frameMaxAge", frameMaxAge, "The maximum age of the signed frame messages accepted to vote (0 to disable the check)")
+	flag.Bool("frameURLCheck", frameURLCheck, "Check that the URL of the signed frame messages, or the URL it redirects to if it is a frame.vote short URL, references the poll voted")
+	flag.String("signerCheck", signerCheckLenient, "How the app keys of the frame messages are checked: strict (reject if they cannot be checked), lenient (accept if they cannot be checked) or disabled")
+	flag.String("signerCheckSource", signerSourceKeyRegistry, "Where the active app keys of the users are read from: keyregistry (the Farcaster KeyRegistry contract) or api (the Farcaster API)")
+	flag.Duration("signerCacheTTL", 10*time.Minute, "The time the active app keys of every user are cached")
+	flag.Bool("neynarFrameReporter", false, "Report the frame messages received to the Neynar frame validation API (requires neynarAPIKey)")
+	flag.Bool("miniApp", false, "Enable the Farcaster Frames v2 support: serve the mini app manifest, embed the polls as mini apps and accept in-app votes")
+	flag.String("miniAppAccountHeader", "", "The header of the account association of the mini app manifest")
+	flag.String("miniAppAccountPayload", "", "The payload of the account association of the mini app manifest")
+	flag.String("miniAppAccountSignature", "", "The signature of the account association of the mini app manifest")
+	flag.String("frameTemplatesDir", "", "The directory with the custom frame templates of the communities, one subdirectory per community ID")
 
 	// Parse the command line flags
 	flag.Parse()
@@ -147,8 +179,13 @@ func main() {
 	botFid := viper.GetUint64("botFid")
 	botPrivKey := viper.GetString("botPrivKey")
 	botHubEndpoint := viper.GetString("botHubEndpoint")
+	botStreamMentions := viper.GetBool("botStreamMentions")
 	neynarSignerUUID := viper.GetString("neynarSignerUUID")
 	neynarWebhookSecret := viper.GetString("neynarWebhookSecret")
+	farcasterFallbackHub := viper.GetString("farcasterFallbackHub")
+	farcasterChannelFollowersAPI := viper.GetString("farcasterChannelFollowersAPI")
+	fakeFarcasterAPI := viper.GetBool("fakeFarcasterAPI")
+	fakeFarcasterSeed := viper.GetString("fakeFarcasterSeed")
 
 	// airstack vars
 	airstackEndpoint := viper.GetString("airstackAPIEndpoint")
@@ -162,7 +199,24 @@ func main() {
 	featureNotificationReputation := uint32(viper.GetInt32("featureNotificationReputation"))
 	maxDirectMessages = viper.GetUint64("maxDirectMessages")
 	reputationUpdateInterval := viper.GetDuration("reputationUpdateInterval")
+	reputationIncrementalUpdateInterval := viper.GetDuration("reputationIncrementalUpdateInterval")
 	concurrentReputationUpdates := viper.GetInt("concurrentReputationUpdates")
+	reputationBoostersConfigPath := viper.GetString("reputationBoostersConfig")
+	reputationActivityHalfLife := viper.GetDuration("reputationActivityHalfLife")
+	pollsQuota := viper.GetInt("pollsQuota")
+	censusesQuota := viper.GetInt("censusesQuota")
+	quotaWindow := viper.GetDuration("quotaWindow")
+	frameMaxAge = viper.GetDuration("frameMaxAge")
+	frameURLCheck = viper.GetBool("frameURLCheck")
+	signerCheck := viper.GetString("signerCheck")
+	signerCheckSource := viper.GetString("signerCheckSource")
+	signerCacheTTL := viper.GetDuration("signerCacheTTL")
+	neynarFrameReporter := viper.GetBool("neynarFrameReporter")
+	frameTemplatesDir := viper.GetString("frameTemplatesDir")
+	miniApp := viper.GetBool("miniApp")
+	miniAppAccountHeader := viper.GetString("miniAppAccountHeader")
+	miniAppAccountPayload := viper.GetString("miniAppAccountPayload")
+	miniAppAccountSignature := viper.GetString("miniAppAccountSignature")
 
 	// overwrite features thesholds
 	if featureNotificationReputation > 0 {
@@ -203,7 +257,12 @@ func main() {
 		"communityHubAdmin", communityHubAdminPrivKey != "",
 		"botFid", botFid,
 		"botHubEndpoint", botHubEndpoint,
+		"botStreamMentions", botStreamMentions,
 		"neynarSignerUUID", neynarSignerUUID,
+		"farcasterFallbackHub", farcasterFallbackHub,
+		"farcasterChannelFollowersAPI", farcasterChannelFollowersAPI,
+		"fakeFarcasterAPI", fakeFarcasterAPI,
+		"fakeFarcasterSeed", fakeFarcasterSeed,
 		"web3endpoint", web3endpoint,
 		"indexer", indexer,
 		"apiToken", apiToken,
@@ -214,6 +273,16 @@ func main() {
 		"airstackMaxHolders", airstackMaxHolders,
 		"airstackSupportAPIEndpoint", airstackSupportAPIEndpoint,
 		"airstackTokenWhitelist", airstackTokenWhitelist,
+		"reputationBoostersConfig", reputationBoostersConfigPath,
+		"reputationActivityHalfLife", reputationActivityHalfLife,
+		"frameMaxAge", frameMaxAge,
+		"frameURLCheck", frameURLCheck,
+		"signerCheck", signerCheck,
+		"signerCheckSource", signerCheckSource,
+		"signerCacheTTL", signerCacheTTL,
+		"neynarFrameReporter", neynarFrameReporter,
+		"frameTemplatesDir", frameTemplatesDir,
+		"miniApp", miniApp,
 	)
 
 	// Start the pprof http endpoints
@@ -267,6 +336,11 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	// load the feature thresholds tuned by the admin, they overwrite the
+	// thresholds provided by flags
+	if err := loadFeatureThresholds(db); err != nil {
+		log.Fatal(err)
+	}
 
 	// Start the discovery user profile background process
 	mainCtx, mainCtxCancel := context.WithCancel(context.Background())
@@ -306,14 +380,47 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Create the Farcaster API client
-	neynarcli, err := neynar.NewNeynarAPI(neynarAPIKey, web3pool)
-	if err != nil {
-		log.Fatal(err)
+	// Create the Farcaster API client, or the in-memory fake one if enabled
+	var fcapi farcasterapi.API
+	var neynarcli *neynar.NeynarAPI
+	if fakeFarcasterAPI {
+		fakecli := fake.New()
+		if fakeFarcasterSeed != "" {
+			seed, err := os.ReadFile(fakeFarcasterSeed)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := fakecli.LoadJSON(seed); err != nil {
+				log.Fatal(err)
+			}
+		}
+		fcapi = fakecli
+		log.Warn("using the in-memory fake farcaster API")
+	} else {
+		if neynarcli, err = neynar.NewNeynarAPI(neynarAPIKey, web3pool); err != nil {
+			log.Fatal(err)
+		}
+		// use neynar through the composite api to cache its responses, and
+		// fall back to the hub, if any
+		backends := []*composite.Backend{{Name: "neynar", API: neynarcli}}
+		if farcasterFallbackHub != "" {
+			hubcli, err := hub.NewHubAPI(farcasterFallbackHub, nil)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := hubcli.SetWeb3Pool(web3pool); err != nil {
+				log.Fatal(err)
+			}
+			hubcli.SetChannelFollowersEndpoint(farcasterChannelFollowersAPI)
+			backends = append(backends, &composite.Backend{Name: "hub", API: hubcli})
+		}
+		if fcapi, err = composite.New(&composite.Config{Backends: backends}); err != nil {
+			log.Fatal(err)
+		}
 	}
 
 	// Start the discovery user profile background process
-	discover.NewFarcasterDiscover(db, neynarcli).Run(mainCtx, indexer)
+	discover.NewFarcasterDiscover(db, fcapi).Run(mainCtx, indexer)
 
 	// Create the community hub service
 	var comHub *communityhub.CommunityHub
@@ -350,25 +457,99 @@ func main() {
 		}
 	}
 
+	// load reputation boosters registry
+	boostersRegistry, err := reputation.LoadBoostersRegistry(reputationBoostersConfigPath)
+	if err != nil {
+		log.Fatalf("failed to load reputation boosters config: %v", err)
+	}
+	log.Infow("reputation boosters loaded", "boosters", len(boostersRegistry.Boosters))
+
 	// start reputation updater
-	repUpdater, err := reputation.NewUpdater(mainCtx, db, neynarcli, census3Client, concurrentReputationUpdates)
+	repUpdater, err := reputation.NewUpdater(mainCtx, db, fcapi, census3Client,
+		boostersRegistry, reputationActivityHalfLife, concurrentReputationUpdates)
 	if err != nil {
 		log.Fatal(err)
 	}
-	if err := repUpdater.Start(reputationUpdateInterval); err != nil {
+	if err := repUpdater.Start(reputationUpdateInterval, reputationIncrementalUpdateInterval); err != nil {
 		log.Fatal(err)
 	}
 	defer repUpdater.Stop()
 
+	// Create the quotas limiter
+	quotas := quota.NewLimiter(map[quota.Action]quota.Limit{
+		quota.PollCreation:   {Max: pollsQuota, Window: quotaWindow},
+		quota.CensusCreation: {Max: censusesQuota, Window: quotaWindow},
+	}, quota.DefaultTiers)
+
+	// Create the frame messages validator, that checks the app keys of the
+	// frame messages and reports them to the external validators
+	frameConf := &framevalidator.Config{}
+	if signerCheck != signerCheckDisabled {
+		if signerCheck != signerCheckLenient && signerCheck != signerCheckStrict {
+			log.Fatalf("invalid signer check mode %s", signerCheck)
+		}
+		var keys framevalidator.AppKeysProvider
+		switch signerCheckSource {
+		case signerSourceKeyRegistry:
+			if keys, err = fcweb3.NewFarcasterProvider(web3pool); err != nil {
+				log.Fatal(err)
+			}
+		case signerSourceAPI:
+			keys = framevalidator.FromSignersAPI(fcapi)
+		default:
+			log.Fatalf("invalid signer check source %s", signerCheckSource)
+		}
+		frameConf.Keys = framevalidator.NewKeysChecker(keys, signerCheck == signerCheckStrict, signerCacheTTL)
+	}
+	if as != nil {
+		frameConf.Reporters = append(frameConf.Reporters, as)
+	}
+	if neynarFrameReporter && neynarcli != nil {
+		frameConf.Reporters = append(frameConf.Reporters, neynarcli)
+	}
+	frames := framevalidator.New(frameConf)
+
+	// Load the frame templates, including the custom ones of the communities
+	templates, err := frametemplates.New(frametemplates.Links{
+		Server:   serverURL,
+		Explorer: explorerURL,
+		Onvote:   onvoteURL,
+	}, frameTemplatesDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if communities := templates.Communities(); len(communities) > 0 {
+		log.Infow("custom frame templates loaded", "communities", communities)
+	}
+
 	// Create the Vocdoni handler
 	apiTokenUUID := uuid.MustParse(apiToken)
 	handler, err := NewVocdoniHandler(apiEndpoint, vocdoniPrivKey, censusInfo,
-		webAppDir, db, mainCtx, neynarcli, &apiTokenUUID, as, census3Client,
-		comHub, repUpdater, adminFID)
+		webAppDir, db, mainCtx, fcapi, &apiTokenUUID, as, census3Client,
+		comHub, repUpdater, quotas, frames, templates, adminFID)
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	// Enable the Frames v2 support if requested
+	if miniApp {
+		var association *miniapp.AccountAssociation
+		if miniAppAccountHeader != "" || miniAppAccountPayload != "" || miniAppAccountSignature != "" {
+			association = &miniapp.AccountAssociation{
+				Header:    miniAppAccountHeader,
+				Payload:   miniAppAccountPayload,
+				Signature: miniAppAccountSignature,
+			}
+		} else {
+			log.Warn("mini app account association not set, the clients will not list the mini app")
+		}
+		manifest, err := newMiniAppManifest(association)
+		if err != nil {
+			log.Fatal(err)
+		}
+		handler.SetMiniApp(manifest)
+	}
+
 	// Create the HTTP API router
 	router := new(httprouter.HTTProuter)
 	if tlsDomain {
@@ -394,6 +575,9 @@ func main() {
 		})
 	}
 
+	// Add the mini app manifest endpoint
+	router.AddRawHTTPHandler(miniapp.ManifestPath, http.MethodGet, handler.miniAppManifestHandler)
+
 	// Add the Prometheus endpoint
 	router.ExposePrometheusEndpoint("/metrics")
 
@@ -455,11 +639,11 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/{electionID}", http.MethodPost, "public", handler.landing); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/{electionID}", http.MethodPost, "public", handler.frameHandler(frameanalytics.FrameLanding, handler.landing)); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/{electionID}", http.MethodGet, "public", handler.landing); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/{electionID}", http.MethodGet, "public", handler.frameHandler(frameanalytics.FrameLanding, handler.landing)); err != nil {
 		log.Fatal(err)
 	}
 
@@ -479,6 +663,14 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if err := uAPI.Endpoint.RegisterMethod("/features/thresholds", http.MethodGet, "admin", handler.featureThresholdsHandler); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := uAPI.Endpoint.RegisterMethod("/features/thresholds", http.MethodPut, "admin", handler.setFeatureThresholdHandler); err != nil {
+		log.Fatal(err)
+	}
+
 	if err := uAPI.Endpoint.RegisterMethod("/rankings/usersByCreatedPolls", http.MethodGet, "public", handler.rankingByElectionsCreated); err != nil {
 		log.Fatal(err)
 	}
@@ -532,11 +724,11 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/poll/results/{electionID}", http.MethodGet, "public", handler.results); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/poll/results/{electionID}", http.MethodGet, "public", handler.frameHandler(frameanalytics.FrameResults, handler.results)); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/poll/results/{electionID}", http.MethodPost, "public", handler.results); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/poll/results/{electionID}", http.MethodPost, "public", handler.frameHandler(frameanalytics.FrameResults, handler.results)); err != nil {
 		log.Fatal(err)
 	}
 
@@ -548,7 +740,7 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/poll/{electionID}", http.MethodGet, "public", handler.showElection); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/poll/{electionID}", http.MethodGet, "public", handler.frameHandler(frameanalytics.FrameShowElection, handler.showElection)); err != nil {
 		log.Fatal(err)
 	}
 
@@ -568,23 +760,39 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/poll/{electionID}", http.MethodPost, "public", handler.showElection); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/poll/{electionID}", http.MethodPost, "public", handler.frameHandler(frameanalytics.FrameShowElection, handler.showElection)); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := uAPI.Endpoint.RegisterMethod("/poll/{electionID}/change", http.MethodPost, "public", handler.frameHandler(frameanalytics.FrameChangeVote, handler.changeVote)); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := uAPI.Endpoint.RegisterMethod("/vote/{electionID}", http.MethodPost, "public", handler.frameHandler(frameanalytics.FrameVote, handler.vote)); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := uAPI.Endpoint.RegisterMethod("/vote/{electionID}", http.MethodGet, "public", handler.frameHandler(frameanalytics.FrameVote, handler.vote)); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := uAPI.Endpoint.RegisterMethod("/vote/{electionID}/receipt/{nullifier}", http.MethodGet, "public", handler.voteReceiptHandler); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/vote/{electionID}", http.MethodPost, "public", handler.vote); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/poll/{electionID}/funnel", http.MethodGet, "public", handler.pollFunnelHandler); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/vote/{electionID}", http.MethodGet, "public", handler.vote); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/miniapp/vote/{electionID}", http.MethodPost, "private", handler.miniAppVoteHandler); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/info/{electionID}", http.MethodGet, "public", handler.info); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/info/{electionID}", http.MethodGet, "public", handler.frameHandler(frameanalytics.FrameInfo, handler.info)); err != nil {
 		log.Fatal(err)
 	}
 
-	if err := uAPI.Endpoint.RegisterMethod("/info/{electionID}", http.MethodPost, "public", handler.info); err != nil {
+	if err := uAPI.Endpoint.RegisterMethod("/info/{electionID}", http.MethodPost, "public", handler.frameHandler(frameanalytics.FrameInfo, handler.info)); err != nil {
 		log.Fatal(err)
 	}
 
@@ -696,6 +904,14 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if err := uAPI.Endpoint.RegisterMethod("/profile/fid/{fid}/reputation/history", http.MethodGet, "public", handler.userReputationHistoryHandler); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := uAPI.Endpoint.RegisterMethod("/profile/fid/{fid}/reputation/improvements", http.MethodGet, "public", handler.userReputationImprovementsHandler); err != nil {
+		log.Fatal(err)
+	}
+
 	if err := uAPI.Endpoint.RegisterMethod("/profile/mutedUsers", http.MethodPost, "private", handler.muteUserHandler); err != nil {
 		log.Fatal(err)
 	}
@@ -740,10 +956,18 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if err := uAPI.Endpoint.RegisterMethod("/communities/{chainAlias}:{communityID}/reputation/history", http.MethodGet, "public", handler.communityReputationHistoryHandler); err != nil {
+		log.Fatal(err)
+	}
+
 	if err := uAPI.Endpoint.RegisterMethod("/communities/{chainAlias}:{communityID}", http.MethodPut, "private", handler.communitySettingsHandler); err != nil {
 		log.Fatal(err)
 	}
 
+	if err := uAPI.Endpoint.RegisterMethod("/communities/{chainAlias}:{communityID}/funnel", http.MethodGet, "public", handler.communityFunnelHandler); err != nil {
+		log.Fatal(err)
+	}
+
 	if err := uAPI.Endpoint.RegisterMethod("/communities/{chainAlias}:{communityID}/delegations", http.MethodGet, "public", handler.communityDelegationsHandler); err != nil {
 		log.Fatal(err)
 	}
@@ -767,7 +991,14 @@ func main() {
 	// if a bot FID is provided, start the bot background process
 	if botFid > 0 {
 		var botAPI farcasterapi.API
-		if botPrivKey != "" && botHubEndpoint != "" {
+		if fakeFarcasterAPI {
+			// fake in-memory bot
+			botAPI = fcapi
+			if err := botAPI.SetFarcasterUser(botFid, botPrivKey); err != nil {
+				log.Fatal(err)
+			}
+			log.Info("trying to init fake bot")
+		} else if botPrivKey != "" && botHubEndpoint != "" {
 			// Hub based bot
 			botAPI, err = hub.NewHubAPI(botHubEndpoint, nil)
 			if err != nil {
@@ -791,7 +1022,7 @@ func main() {
 		} else {
 			log.Fatalf("botFid is set but botPrivKey and botHubEndpoint or neynarAPIKey, neynarSignerUUID and neynarWebhookSecret are not")
 		}
-		voteBot, err := initBot(mainCtx, handler, botAPI, censusInfo)
+		voteBot, err := initBot(mainCtx, handler, botAPI, censusInfo, botStreamMentions)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vocdoni/vote-frame/framevalidator"
+	"github.com/vocdoni/vote-frame/miniapp"
+	"go.vocdoni.io/dvote/httprouter"
+	"go.vocdoni.io/dvote/httprouter/apirest"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+const (
+	// miniAppButtonTitle is the title of the button of the poll embeds that
+	// opens the poll in the mini app
+	miniAppButtonTitle = "🗳️ Vote"
+	// miniAppSplashBackgroundColor is the background color of the splash
+	// screen of the mini app
+	miniAppSplashBackgroundColor = "#603cba"
+)
+
+// ErrMiniAppFIDMismatch is returned when the frame message provided to vote
+// from the mini app is not signed by the authenticated user.
+var ErrMiniAppFIDMismatch = fmt.Errorf("frame message not signed by the authenticated user")
+
+// miniAppVoteResponse is the response of the in-app votes.
+type miniAppVoteResponse struct {
+	ElectionID  string `json:"electionID"`
+	Nullifier   string `json:"nullifier"`
+	ExplorerURL string `json:"explorerURL"`
+}
+
+// newMiniAppManifest returns the manifest of the mini app served by the server
+// with the account association provided, which can be nil.
+func newMiniAppManifest(association *miniapp.AccountAssociation) (*miniapp.Manifest, error) {
+	manifest := &miniapp.Manifest{
+		AccountAssociation: association,
+		Frame: &miniapp.Config{
+			Version:               miniapp.ManifestVersion,
+			Name:                  "Votecaster",
+			IconURL:               serverURL + "/app/apple-touch-icon.png",
+			HomeURL:               serverURL + "/app/",
+			ImageURL:              serverURL + "/app/opengraph.png",
+			ButtonTitle:           miniAppButtonTitle,
+			SplashImageURL:        serverURL + "/app/apple-touch-icon.png",
+			SplashBackgroundColor: miniAppSplashBackgroundColor,
+		},
+	}
+	if err := manifest.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid mini app manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// SetMiniApp enables the Frames v2 support with the manifest provided: the
+// manifest is served and the polls are embedded as mini apps.
+func (v *vocdoniHandler) SetMiniApp(manifest *miniapp.Manifest) {
+	v.miniApp = manifest
+}
+
+// miniAppManifestHandler serves the manifest of the mini app, or a not found
+// error if the Frames v2 support is not enabled.
+func (v *vocdoniHandler) miniAppManifestHandler(w http.ResponseWriter, _ *http.Request) {
+	if v.miniApp == nil {
+		http.NotFound(w, nil)
+		return
+	}
+	data, err := json.Marshal(v.miniApp)
+	if err != nil {
+		log.Warnw("failed to encode mini app manifest", "error", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		log.Warnw("failed to send mini app manifest", "error", err)
+	}
+}
+
+// pollEmbed returns the JSON encoded Frames v2 embed of the poll provided,
+// which opens the poll in the mini app, or an empty string if the Frames v2
+// support is not enabled.
+func (v *vocdoniHandler) pollEmbed(electionID types.HexBytes, imageURL string) string {
+	if v.miniApp == nil {
+		return ""
+	}
+	url := fmt.Sprintf("%s/app/#poll/%x", serverURL, electionID)
+	return v.miniApp.Frame.Embed(imageURL, miniAppButtonTitle, url).String()
+}
+
+// miniAppVoteHandler casts the vote of the authenticated user from the mini
+// app. The vochain only accepts votes proven by a frame action signed by an
+// app key of the voter, so the request body must be a frame signature packet
+// of the poll, like the ones received by the vote frame, signed by the
+// authenticated user. The selected option is the button index of the signed
+// action. The vote is cast with the same transaction builder as the frames.
+func (v *vocdoniHandler) miniAppVoteHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	if v.miniApp == nil {
+		return ctx.Send([]byte("mini app not enabled"), apirest.HTTPstatusNotFound)
+	}
+	userFID, err := v.db.UserFromAuthToken(msg.AuthToken)
+	if err != nil {
+		return fmt.Errorf("cannot get user from auth token: %w", err)
+	}
+	electionID, err := hex.DecodeString(ctx.URLParam("electionID"))
+	if err != nil {
+		return ctx.Send([]byte("invalid electionID"), apirest.HTTPstatusBadRequest)
+	}
+	if v.db.FinalResultsPNG(electionID) != nil {
+		return ctx.Send([]byte("the poll is finished"), apirest.HTTPstatusBadRequest)
+	}
+	election, err := v.election(electionID)
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), apirest.HTTPstatusNotFound)
+	}
+	if election.FinalResults {
+		return ctx.Send([]byte("the poll is finished"), apirest.HTTPstatusBadRequest)
+	}
+
+	// validate the frame message and check it is signed by the authenticated
+	// user, recently and for this election
+	frameMsg, err := v.frames.Validate(msg.Data)
+	if err == nil && frameMsg.FID != userFID {
+		err = ErrMiniAppFIDMismatch
+	}
+	if err == nil {
+		err = checkFrameMessage(frameMsg, electionID, true)
+	}
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), miniAppVoteErrorStatus(err))
+	}
+
+	// cast the vote
+	voteCount, err := v.cli.ElectionVoteCount(electionID)
+	if err != nil {
+		log.Warnw("failed to fetch vote count", "error", err)
+	}
+	vote, err := vote(frameMsg, electionID, election.Census.CensusRoot, maxVoteOverwrites(election), v.cli)
+	if err != nil {
+		if v.voteDelegated(electionID, frameMsg.FID) {
+			err = ErrVoteDelegated
+		}
+		return ctx.Send([]byte(err.Error()), miniAppVoteErrorStatus(err))
+	}
+	v.recordVote(vote, electionID, election.Census.CensusRoot, voteCount)
+	log.Infow("mini app vote cast", "electionID", fmt.Sprintf("%x", electionID), "fid", userFID)
+
+	data, err := json.Marshal(&miniAppVoteResponse{
+		ElectionID:  fmt.Sprintf("%x", electionID),
+		Nullifier:   vote.Nullifier.String(),
+		ExplorerURL: fmt.Sprintf("%s/verify/#/%s", explorerURL, vote.Nullifier.String()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return ctx.Send(data, apirest.HTTPstatusOK)
+}
+
+// miniAppVoteErrorStatus returns the HTTP status of the in-app vote error
+// provided.
+func miniAppVoteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, framevalidator.ErrInvalidPacket), errors.Is(err, framevalidator.ErrInvalidSignature),
+		errors.Is(err, ErrFrameMessageExpired), errors.Is(err, ErrFrameURLMismatch),
+		errors.Is(err, ErrFrameStateMismatch):
+		return apirest.HTTPstatusBadRequest
+	case errors.Is(err, ErrMiniAppFIDMismatch), errors.Is(err, framevalidator.ErrRevokedSigner),
+		errors.Is(err, ErrNotInCensus), errors.Is(err, ErrVoteDelegated):
+		return http.StatusForbidden
+	case errors.Is(err, ErrAlreadyVoted):
+		return http.StatusConflict
+	default:
+		return apirest.HTTPstatusInternalErr
+	}
+}
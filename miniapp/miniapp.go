@@ -0,0 +1,177 @@
+// miniapp package defines the documents of the Farcaster mini apps (Frames
+// v2): the manifest served at /.well-known/farcaster.json, which describes the
+// app and associates it to the Farcaster account of its domain, and the
+// embeds included in the fc:frame meta tag of every page that can be launched
+// as a mini app from a cast.
+package miniapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+)
+
+const (
+	// ManifestPath is the path where the manifest must be served
+	ManifestPath = "/.well-known/farcaster.json"
+	// ManifestVersion is the version of the manifest supported
+	ManifestVersion = "1"
+	// EmbedVersion is the version of the embeds supported
+	EmbedVersion = "next"
+	// ActionLaunchFrame is the type of the action that opens the mini app
+	ActionLaunchFrame = "launch_frame"
+	// maxNameLength and maxButtonTitleLength are the maximum number of
+	// characters of the app name and the button titles
+	maxNameLength        = 32
+	maxButtonTitleLength = 32
+	// maxURLLength is the maximum length of the URLs of the documents
+	maxURLLength = 1024
+)
+
+// hexColorRgx matches the hex colors accepted as splash background colors.
+var hexColorRgx = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// AccountAssociation struct contains the JSON Farcaster Signature that
+// associates the domain of the mini app to a Farcaster account. It is
+// generated once by the owner of the account, so it is provided as it is.
+type AccountAssociation struct {
+	Header    string `json:"header"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// Config struct describes the mini app in the manifest.
+type Config struct {
+	Version               string `json:"version"`
+	Name                  string `json:"name"`
+	IconURL               string `json:"iconUrl"`
+	HomeURL               string `json:"homeUrl"`
+	ImageURL              string `json:"imageUrl,omitempty"`
+	ButtonTitle           string `json:"buttonTitle,omitempty"`
+	SplashImageURL        string `json:"splashImageUrl,omitempty"`
+	SplashBackgroundColor string `json:"splashBackgroundColor,omitempty"`
+	WebhookURL            string `json:"webhookUrl,omitempty"`
+}
+
+// Manifest struct is the document served at ManifestPath. The account
+// association is optional, but the clients do not list the mini apps without
+// it.
+type Manifest struct {
+	AccountAssociation *AccountAssociation `json:"accountAssociation,omitempty"`
+	Frame              *Config             `json:"frame"`
+}
+
+// Validate checks that the manifest contains the required fields and that
+// they do not exceed the limits of the specification.
+func (m *Manifest) Validate() error {
+	if m.Frame == nil {
+		return fmt.Errorf("missing frame config")
+	}
+	if m.Frame.Version != ManifestVersion {
+		return fmt.Errorf("unsupported manifest version %q", m.Frame.Version)
+	}
+	if m.Frame.Name == "" || utf8.RuneCountInString(m.Frame.Name) > maxNameLength {
+		return fmt.Errorf("name must have between 1 and %d characters", maxNameLength)
+	}
+	if utf8.RuneCountInString(m.Frame.ButtonTitle) > maxButtonTitleLength {
+		return fmt.Errorf("button title must have up to %d characters", maxButtonTitleLength)
+	}
+	for name, url := range map[string]string{
+		"icon":   m.Frame.IconURL,
+		"home":   m.Frame.HomeURL,
+		"image":  m.Frame.ImageURL,
+		"splash": m.Frame.SplashImageURL,
+	} {
+		if len(url) > maxURLLength {
+			return fmt.Errorf("%s URL must have up to %d characters", name, maxURLLength)
+		}
+	}
+	if m.Frame.IconURL == "" || m.Frame.HomeURL == "" {
+		return fmt.Errorf("missing icon or home URL")
+	}
+	if c := m.Frame.SplashBackgroundColor; c != "" && !hexColorRgx.MatchString(c) {
+		return fmt.Errorf("invalid splash background color %q", c)
+	}
+	if a := m.AccountAssociation; a != nil && (a.Header == "" || a.Payload == "" || a.Signature == "") {
+		return fmt.Errorf("incomplete account association")
+	}
+	return nil
+}
+
+// Action struct defines the action of the button of an embed, which launches
+// the mini app at the URL provided.
+type Action struct {
+	Type                  string `json:"type"`
+	Name                  string `json:"name"`
+	URL                   string `json:"url,omitempty"`
+	SplashImageURL        string `json:"splashImageUrl,omitempty"`
+	SplashBackgroundColor string `json:"splashBackgroundColor,omitempty"`
+}
+
+// Button struct defines the button of an embed.
+type Button struct {
+	Title  string `json:"title"`
+	Action Action `json:"action"`
+}
+
+// Embed struct is the content of the fc:frame meta tag of the pages that can
+// be launched as a mini app. Its image should have a 3:2 aspect ratio.
+type Embed struct {
+	Version  string `json:"version"`
+	ImageURL string `json:"imageUrl"`
+	Button   Button `json:"button"`
+}
+
+// Validate checks that the embed contains the required fields and that they do
+// not exceed the limits of the specification.
+func (e *Embed) Validate() error {
+	if e.Version != EmbedVersion {
+		return fmt.Errorf("unsupported embed version %q", e.Version)
+	}
+	if e.ImageURL == "" || len(e.ImageURL) > maxURLLength || len(e.Button.Action.URL) > maxURLLength {
+		return fmt.Errorf("the URLs must have between 1 and %d characters", maxURLLength)
+	}
+	if e.Button.Title == "" || utf8.RuneCountInString(e.Button.Title) > maxButtonTitleLength {
+		return fmt.Errorf("button title must have between 1 and %d characters", maxButtonTitleLength)
+	}
+	if e.Button.Action.Type != ActionLaunchFrame || e.Button.Action.Name == "" {
+		return fmt.Errorf("the button action must launch a named frame")
+	}
+	if c := e.Button.Action.SplashBackgroundColor; c != "" && !hexColorRgx.MatchString(c) {
+		return fmt.Errorf("invalid splash background color %q", c)
+	}
+	return nil
+}
+
+// String returns the JSON encoded embed, as it must be included in the
+// content of the fc:frame meta tag, or an empty string if it is not valid.
+func (e *Embed) String() string {
+	if err := e.Validate(); err != nil {
+		return ""
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Embed returns the embed that launches the mini app described by the config
+// at the URL provided, with the image and button title provided.
+func (c *Config) Embed(imageURL, buttonTitle, url string) *Embed {
+	return &Embed{
+		Version:  EmbedVersion,
+		ImageURL: imageURL,
+		Button: Button{
+			Title: buttonTitle,
+			Action: Action{
+				Type:                  ActionLaunchFrame,
+				Name:                  c.Name,
+				URL:                   url,
+				SplashImageURL:        c.SplashImageURL,
+				SplashBackgroundColor: c.SplashBackgroundColor,
+			},
+		},
+	}
+}
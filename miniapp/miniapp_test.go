@@ -0,0 +1,63 @@
+package miniapp
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func testConfig() *Config {
+	return &Config{
+		Version:               ManifestVersion,
+		Name:                  "Votecaster",
+		IconURL:               "https://farcaster.vote/app/icon.png",
+		HomeURL:               "https://farcaster.vote/app/",
+		SplashImageURL:        "https://farcaster.vote/app/splash.png",
+		SplashBackgroundColor: "#603cba",
+	}
+}
+
+func TestManifest(t *testing.T) {
+	c := qt.New(t)
+
+	manifest := &Manifest{Frame: testConfig()}
+	c.Assert(manifest.Validate(), qt.IsNil)
+	// the account association is omitted if it is not set
+	data, err := json.Marshal(manifest)
+	c.Assert(err, qt.IsNil)
+	c.Assert(string(data), qt.Not(qt.Contains), "accountAssociation")
+
+	manifest.AccountAssociation = &AccountAssociation{Header: "h", Payload: "p"}
+	c.Assert(manifest.Validate(), qt.ErrorMatches, "incomplete account association")
+	manifest.AccountAssociation.Signature = "s"
+	c.Assert(manifest.Validate(), qt.IsNil)
+
+	manifest.Frame.Version = "0"
+	c.Assert(manifest.Validate(), qt.ErrorMatches, "unsupported manifest version.*")
+	manifest.Frame = testConfig()
+	manifest.Frame.Name = strings.Repeat("a", maxNameLength+1)
+	c.Assert(manifest.Validate(), qt.ErrorMatches, "name must have.*")
+	manifest.Frame = testConfig()
+	manifest.Frame.SplashBackgroundColor = "purple"
+	c.Assert(manifest.Validate(), qt.ErrorMatches, "invalid splash background color.*")
+}
+
+func TestEmbed(t *testing.T) {
+	c := qt.New(t)
+
+	embed := testConfig().Embed("https://farcaster.vote/images/abcd.png", "🗳️ Vote", "https://farcaster.vote/app/#poll/abcd")
+	c.Assert(embed.Validate(), qt.IsNil)
+	c.Assert(embed.String(), qt.Equals, `{"version":"next","imageUrl":"https://farcaster.vote/images/abcd.png",`+
+		`"button":{"title":"🗳️ Vote","action":{"type":"launch_frame","name":"Votecaster",`+
+		`"url":"https://farcaster.vote/app/#poll/abcd","splashImageUrl":"https://farcaster.vote/app/splash.png",`+
+		`"splashBackgroundColor":"#603cba"}}}`)
+
+	// the invalid embeds are not encoded
+	embed.Button.Title = strings.Repeat("a", maxButtonTitleLength+1)
+	c.Assert(embed.Validate(), qt.ErrorMatches, "button title must have.*")
+	c.Assert(embed.String(), qt.Equals, "")
+	embed = testConfig().Embed("", "Vote", "")
+	c.Assert(embed.Validate(), qt.ErrorMatches, "the URLs must have.*")
+}
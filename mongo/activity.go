@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UserActivities method returns the activities done by the user provided or
+// on the elections created by them after the given time. It includes the
+// elections created and the votes cast by the user, and the votes cast by
+// any user on the elections created by the user.
+func (ms *MongoStorage) UserActivities(userID uint64, since time.Time) ([]*Activity, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cur, err := ms.activities.Find(ctx, bson.M{
+		"$or": []bson.M{
+			{"userID": userID},
+			{"electionOwnerID": userID},
+		},
+		"createdAt": bson.M{"$gte": since},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	activities := []*Activity{}
+	if err := cur.All(ctx, &activities); err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
+
+// UsersWithActivitySince method returns the IDs of the users that did some
+// activity after the given time, including the owners of the elections that
+// received votes.
+func (ms *MongoStorage) UsersWithActivitySince(since time.Time) ([]uint64, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	filter := bson.M{"createdAt": bson.M{"$gte": since}}
+	users := map[uint64]bool{}
+	for _, field := range []string{"userID", "electionOwnerID"} {
+		values, err := ms.activities.Distinct(ctx, field, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			switch id := value.(type) {
+			case int64:
+				users[uint64(id)] = true
+			case int32:
+				users[uint64(id)] = true
+			}
+		}
+	}
+	userIDs := make([]uint64, 0, len(users))
+	for id := range users {
+		if id != 0 {
+			userIDs = append(userIDs, id)
+		}
+	}
+	return userIDs, nil
+}
+
+// CommunitiesWithActivitySince method returns the IDs of the communities that
+// created elections or received votes after the given time.
+func (ms *MongoStorage) CommunitiesWithActivitySince(since time.Time) ([]string, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	values, err := ms.elections.Distinct(ctx, "community.id", bson.M{
+		"community.id": bson.M{"$exists": true, "$ne": ""},
+		"$or": []bson.M{
+			{"createdTime": bson.M{"$gte": since}},
+			{"lastVoteTime": bson.M{"$gte": since}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	communityIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			communityIDs = append(communityIDs, id)
+		}
+	}
+	return communityIDs, nil
+}
+
+// addActivity registers a new activity of the type provided done by the user
+// on the election provided at the current time.
+func (ms *MongoStorage) addActivity(activityType string, userID uint64, election *Election, weight uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := ms.activities.InsertOne(ctx, &Activity{
+		ID:              primitive.NewObjectID(),
+		Type:            activityType,
+		UserID:          userID,
+		ElectionID:      election.ElectionID,
+		ElectionOwnerID: election.UserID,
+		Weight:          weight,
+		CreatedAt:       time.Now(),
+	})
+	return err
+}
@@ -0,0 +1,66 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.vocdoni.io/dvote/types"
+)
+
+// AddFrameEvents method increases the frame event counters of the election
+// provided by the events provided, by frame and event type, creating them if
+// they do not exist.
+func (ms *MongoStorage) AddFrameEvents(electionID types.HexBytes, events map[string]map[string]uint64) error {
+	inc := bson.M{}
+	for frame, counters := range events {
+		for event, count := range counters {
+			inc[fmt.Sprintf("events.%s.%s", frame, event)] = int64(count)
+		}
+	}
+	if len(inc) == 0 {
+		return nil
+	}
+
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Update().SetUpsert(true)
+	update := bson.M{"$inc": inc, "$set": bson.M{"updatedAt": time.Now()}}
+	if _, err := ms.frameAnalytics.UpdateOne(ctx, bson.M{"_id": electionID.String()}, update, opts); err != nil {
+		return fmt.Errorf("failed to add frame events: %w", err)
+	}
+	return nil
+}
+
+// FrameAnalyticsOfElections method returns the aggregated frame events of the
+// elections provided, by election ID. The elections without events are not
+// included.
+func (ms *MongoStorage) FrameAnalyticsOfElections(electionIDs ...string) (map[string]*FrameAnalytics, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cursor, err := ms.frameAnalytics.Find(ctx, bson.M{"_id": bson.M{"$in": electionIDs}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find frame analytics: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	analytics := map[string]*FrameAnalytics{}
+	for cursor.Next(ctx) {
+		a := &FrameAnalytics{}
+		if err := cursor.Decode(a); err != nil {
+			return nil, fmt.Errorf("failed to decode frame analytics: %w", err)
+		}
+		analytics[a.ElectionID] = a
+	}
+	return analytics, nil
+}
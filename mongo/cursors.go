@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EventCursor method returns the last event ID stored for the event cursor
+// with the name provided. If the cursor does not exist, it returns zero.
+func (ms *MongoStorage) EventCursor(name string) (uint64, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var cursor EventCursor
+	if err := ms.eventCursors.FindOne(ctx, bson.M{"_id": name}).Decode(&cursor); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get event cursor: %w", err)
+	}
+	return cursor.EventID, nil
+}
+
+// SetEventCursor method stores the event ID provided for the event cursor
+// with the name provided, creating it if it does not exist.
+func (ms *MongoStorage) SetEventCursor(name string, eventID uint64) error {
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Update().SetUpsert(true)
+	update := bson.M{"$set": bson.M{"eventId": eventID, "updatedAt": time.Now()}}
+	if _, err := ms.eventCursors.UpdateOne(ctx, bson.M{"_id": name}, update, opts); err != nil {
+		return fmt.Errorf("failed to set event cursor: %w", err)
+	}
+	return nil
+}
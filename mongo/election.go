@@ -35,6 +35,14 @@ func (ms *MongoStorage) AddElection(
 	}
 	ms.keysLock.Lock()
 	err := ms.addElection(&election)
+	if err == nil {
+		// register the election creation as a timestamped activity of the
+		// user
+		if err := ms.addActivity(ActivityTypeElection, userFID, &election, 1); err != nil {
+			log.Warnw("failed to register election activity", "userID", userFID,
+				"electionID", electionID.String(), "error", err)
+		}
+	}
 	ms.keysLock.Unlock()
 	if err != nil {
 		return fmt.Errorf("failed to add election: %w", err)
@@ -254,3 +262,130 @@ func (ms *MongoStorage) SetElectionQuestion(electionID types.HexBytes, question
 	log.Infow("updated election question", "electionID", electionID.String(), "question", question)
 	return nil
 }
+
+// SetElectionCast sets the bot cast that published the election provided.
+func (ms *MongoStorage) SetElectionCast(electionID types.HexBytes, cast *ElectionCast) error {
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"cast": cast}}
+	if _, err := ms.elections.UpdateOne(ctx, bson.M{"_id": electionID.String()}, update); err != nil {
+		return fmt.Errorf("cannot update election cast: %w", err)
+	}
+	return nil
+}
+
+// SetElectionProtocols sets the Open Frames client protocols accepted by the
+// election provided.
+func (ms *MongoStorage) SetElectionProtocols(electionID types.HexBytes, protocols []string) error {
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"protocols": protocols}}
+	if _, err := ms.elections.UpdateOne(ctx, bson.M{"_id": electionID.String()}, update); err != nil {
+		return fmt.Errorf("cannot update election protocols: %w", err)
+	}
+	return nil
+}
+
+// SetElectionEndTime sets the end time of the election provided, for example
+// when it is ended before its original end date.
+func (ms *MongoStorage) SetElectionEndTime(electionID types.HexBytes, endTime time.Time) error {
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"endTime": endTime}}
+	if _, err := ms.elections.UpdateOne(ctx, bson.M{"_id": electionID.String()}, update); err != nil {
+		return fmt.Errorf("cannot update election end time: %w", err)
+	}
+	return nil
+}
+
+// ElectionsPendingToPostResults returns the elections created from the bot
+// that have already ended and whose final results must be posted into the
+// thread of their cast but have not been posted yet, nor failed to be posted
+// too many times.
+func (ms *MongoStorage) ElectionsPendingToPostResults() ([]*Election, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"cast.postResults":   true,
+		"cast.resultsPosted": false,
+		"cast.resultsFailed": bson.M{"$ne": true},
+		"endTime":            bson.M{"$lt": time.Now()},
+	}
+	cursor, err := ms.elections.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find elections pending to post results: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var elections []*Election
+	for cursor.Next(ctx) {
+		var election Election
+		if err := cursor.Decode(&election); err != nil {
+			log.Warn("failed to decode election: ", err)
+			continue
+		}
+		elections = append(elections, &election)
+	}
+	return elections, nil
+}
+
+// SetElectionResultsPosted marks the final results of the election provided as
+// posted into the thread of its cast.
+func (ms *MongoStorage) SetElectionResultsPosted(electionID types.HexBytes) error {
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"cast.resultsPosted": true}}
+	if _, err := ms.elections.UpdateOne(ctx, bson.M{"_id": electionID.String()}, update); err != nil {
+		return fmt.Errorf("cannot update election cast: %w", err)
+	}
+	return nil
+}
+
+// AddElectionResultsAttempt registers a failed attempt to post the final
+// results of the election provided into the thread of its cast. Once the
+// attempts reach the maximum provided, the results are marked as failed so
+// they are not retried anymore. It returns if the results have been marked
+// as failed.
+func (ms *MongoStorage) AddElectionResultsAttempt(electionID types.HexBytes, maxAttempts int) (bool, error) {
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	update := bson.M{"$inc": bson.M{"cast.resultsAttempts": 1}}
+	var election Election
+	if err := ms.elections.FindOneAndUpdate(ctx, bson.M{"_id": electionID.String()}, update, opts).
+		Decode(&election); err != nil {
+		return false, fmt.Errorf("cannot update election cast: %w", err)
+	}
+	if election.Cast == nil || election.Cast.ResultsAttempts < maxAttempts {
+		return false, nil
+	}
+	update = bson.M{"$set": bson.M{"cast.resultsFailed": true}}
+	if _, err := ms.elections.UpdateOne(ctx, bson.M{"_id": electionID.String()}, update); err != nil {
+		return false, fmt.Errorf("cannot update election cast: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FeatureThresholds method returns the reputation thresholds of the features
+// stored in the database, the global ones (with empty community ID) and the
+// overrides of every community.
+func (ms *MongoStorage) FeatureThresholds() ([]*FeatureThresholds, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cur, err := ms.featureThresholds.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	thresholds := []*FeatureThresholds{}
+	if err := cur.All(ctx, &thresholds); err != nil {
+		return nil, err
+	}
+	return thresholds, nil
+}
+
+// SetFeatureThreshold method stores the reputation threshold of the feature
+// provided for the community provided. If the community ID is empty, the
+// threshold is the global one.
+func (ms *MongoStorage) SetFeatureThreshold(communityID, feature string, reputation uint32) error {
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Update().SetUpsert(true)
+	if _, err := ms.featureThresholds.UpdateOne(ctx, bson.M{"_id": communityID},
+		bson.M{"$set": bson.M{"thresholds." + feature: reputation}}, opts); err != nil {
+		return fmt.Errorf("failed to set feature threshold: %w", err)
+	}
+	return nil
+}
+
+// DelFeatureThreshold method removes the reputation threshold of the feature
+// provided for the community provided.
+func (ms *MongoStorage) DelFeatureThreshold(communityID, feature string) error {
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := ms.featureThresholds.UpdateOne(ctx, bson.M{"_id": communityID},
+		bson.M{"$unset": bson.M{"thresholds." + feature: ""}}); err != nil {
+		return fmt.Errorf("failed to delete feature threshold: %w", err)
+	}
+	return nil
+}
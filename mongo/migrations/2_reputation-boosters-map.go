@@ -0,0 +1,110 @@
+package migrations
+
+import (
+	"context"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	migrate.MustRegister(upReputationBoostersMap, downReputationBoostersMap)
+}
+
+// legacyBoosters contains the attributes of the reputation documents that
+// stored the boosters as booleans before the boosters registry was introduced.
+// The booster IDs of the default registry match these attribute names.
+var legacyBoosters = []string{
+	"hasVotecasterNFTPass",
+	"hasVotecasterLaunchNFT",
+	"isVotecasterAlphafrensFollower",
+	"isVotecasterFarcasterFollower",
+	"isVocdoniFarcasterFollower",
+	"votecasterAnnouncementRecasted",
+	"hasKIWI",
+	"hasDegenDAONFT",
+	"hasHaberdasheryNFT",
+	"has10kDegenAtLeast",
+	"hasTokyoDAONFT",
+	"hasProxy",
+	"has5ProxyAtLeast",
+	"hasProxyStudioNFT",
+	"hasNameDegen",
+	"hasFarcasterOGNFT",
+	"hasMoxiePass",
+}
+
+func upReputationBoostersMap(ctx context.Context, db *mongo.Database) error {
+	// fetch all documents from the reputations collection
+	reputationsCollection := db.Collection("reputations")
+	reputationsCursor, err := reputationsCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer reputationsCursor.Close(ctx)
+	// iterate over all documents
+	for reputationsCursor.Next(ctx) {
+		var doc bson.M
+		if err = reputationsCursor.Decode(&doc); err != nil {
+			return err
+		}
+		// move every legacy boolean attribute to the 'boosters' map and unset
+		// it
+		boosters := bson.M{}
+		unset := bson.M{}
+		for _, attr := range legacyBoosters {
+			if value, ok := doc[attr].(bool); ok {
+				boosters[attr] = value
+				unset[attr] = ""
+			}
+		}
+		if len(unset) == 0 {
+			continue
+		}
+		filter := bson.M{"_id": doc["_id"]}
+		update := bson.M{"$set": bson.M{"boosters": boosters}, "$unset": unset}
+		if _, err := reputationsCollection.UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+	return reputationsCursor.Err()
+}
+
+func downReputationBoostersMap(ctx context.Context, db *mongo.Database) error {
+	// fetch all documents from the reputations collection
+	reputationsCollection := db.Collection("reputations")
+	reputationsCursor, err := reputationsCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer reputationsCursor.Close(ctx)
+	// iterate over all documents
+	for reputationsCursor.Next(ctx) {
+		var doc bson.M
+		if err = reputationsCursor.Decode(&doc); err != nil {
+			return err
+		}
+		// move back every legacy booster of the 'boosters' map to its own
+		// boolean attribute and unset the map
+		boosters, ok := doc["boosters"].(bson.M)
+		if !ok {
+			continue
+		}
+		set := bson.M{}
+		for _, attr := range legacyBoosters {
+			if value, ok := boosters[attr].(bool); ok {
+				set[attr] = value
+			}
+		}
+		filter := bson.M{"_id": doc["_id"]}
+		update := bson.M{"$unset": bson.M{"boosters": ""}}
+		if len(set) > 0 {
+			update["$set"] = set
+		}
+		if _, err := reputationsCollection.UpdateOne(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+	return reputationsCursor.Err()
+}
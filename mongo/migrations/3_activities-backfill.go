@@ -0,0 +1,126 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	migrate "github.com/xakep666/mongo-migrate"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	migrate.MustRegister(upActivitiesBackfill, downActivitiesBackfill)
+}
+
+// upActivitiesBackfill creates the timestamped activities of the elections and
+// votes registered before the activities collection was introduced. The exact
+// time of the old votes is unknown, so they are registered at the creation
+// time of the election. The weight of the votes is the participation of the
+// voter in the census of the election, as IncreaseVoteCount registers it.
+func upActivitiesBackfill(ctx context.Context, db *mongo.Database) error {
+	activitiesCollection := db.Collection("activities")
+	votersCollection := db.Collection("voters")
+	censusCollection := db.Collection("census")
+	usersCollection := db.Collection("users")
+	// fetch all documents from the elections collection
+	electionsCollection := db.Collection("elections")
+	electionsCursor, err := electionsCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer electionsCursor.Close(ctx)
+	// iterate over all documents
+	for electionsCursor.Next(ctx) {
+		var election struct {
+			ID          string    `bson:"_id"`
+			UserID      uint64    `bson:"userId"`
+			CreatedTime time.Time `bson:"createdTime"`
+		}
+		if err = electionsCursor.Decode(&election); err != nil {
+			return err
+		}
+		activities := []any{bson.M{
+			"_id":             primitive.NewObjectID(),
+			"type":            "election",
+			"userID":          election.UserID,
+			"electionID":      election.ID,
+			"electionOwnerID": election.UserID,
+			"weight":          1,
+			"createdAt":       election.CreatedTime,
+		}}
+		// get the voters of the election, if any
+		var voters struct {
+			Voters []uint64 `bson:"voters"`
+		}
+		err := votersCollection.FindOne(ctx, bson.M{"_id": election.ID}).Decode(&voters)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		// get the participants of the census of the election, if any
+		var census struct {
+			Participants map[string]string `bson:"participants"`
+		}
+		err = censusCollection.FindOne(ctx, bson.M{"electionId": election.ID}).Decode(&census)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		for _, voter := range voters.Voters {
+			participation, err := voterParticipation(ctx, usersCollection, census.Participants, voter)
+			if err != nil {
+				return err
+			}
+			activities = append(activities, bson.M{
+				"_id":             primitive.NewObjectID(),
+				"type":            "vote",
+				"userID":          voter,
+				"electionID":      election.ID,
+				"electionOwnerID": election.UserID,
+				"weight":          participation,
+				"createdAt":       election.CreatedTime,
+			})
+		}
+		if _, err := activitiesCollection.InsertMany(ctx, activities); err != nil {
+			return err
+		}
+	}
+	return electionsCursor.Err()
+}
+
+// voterParticipation returns the participation of the voter provided in the
+// census participants provided, which are encoded as "weight:participation" by
+// username. It falls back to 1, like the participation of the new votes, if
+// the voter or their participation are unknown.
+func voterParticipation(ctx context.Context, users *mongo.Collection, participants map[string]string,
+	voter uint64,
+) (uint64, error) {
+	if len(participants) == 0 {
+		return 1, nil
+	}
+	var user struct {
+		Username string `bson:"username"`
+	}
+	if err := users.FindOne(ctx, bson.M{"_id": voter}).Decode(&user); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return 1, nil
+		}
+		return 0, err
+	}
+	weightAndParticipation := strings.Split(participants[user.Username], ":")
+	if len(weightAndParticipation) != 2 {
+		return 1, nil
+	}
+	participation, err := strconv.ParseUint(weightAndParticipation[1], 10, 32)
+	if err != nil {
+		return 1, nil
+	}
+	return participation, nil
+}
+
+func downActivitiesBackfill(ctx context.Context, db *mongo.Database) error {
+	return db.Collection("activities").Drop(ctx)
+}
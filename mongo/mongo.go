@@ -23,7 +23,8 @@ import (
 )
 
 const (
-	authenticationExpirationNoActivitySeconds = 15 * 24 * 60 * 60 // 15 days
+	authenticationExpirationNoActivitySeconds = 15 * 24 * 60 * 60  // 15 days
+	reputationHistoryRetentionSeconds         = 365 * 24 * 60 * 60 // 1 year
 )
 
 // MongoStorage uses an external MongoDB service for stoting the user data and election details.
@@ -44,6 +45,11 @@ type MongoStorage struct {
 	avatars            *mongo.Collection
 	delegations        *mongo.Collection
 	reputations        *mongo.Collection
+	reputationHistory  *mongo.Collection
+	activities         *mongo.Collection
+	featureThresholds  *mongo.Collection
+	eventCursors       *mongo.Collection
+	frameAnalytics     *mongo.Collection
 }
 
 type Options struct {
@@ -118,6 +124,11 @@ func New(url, database string) (*MongoStorage, error) {
 	ms.avatars = client.Database(database).Collection("avatars")
 	ms.delegations = client.Database(database).Collection("delegations")
 	ms.reputations = client.Database(database).Collection("reputations")
+	ms.reputationHistory = client.Database(database).Collection("reputationHistory")
+	ms.activities = client.Database(database).Collection("activities")
+	ms.featureThresholds = client.Database(database).Collection("featureThresholds")
+	ms.eventCursors = client.Database(database).Collection("eventCursors")
+	ms.frameAnalytics = client.Database(database).Collection("frameAnalytics")
 
 	// If reset flag is enabled, Reset drops the database documents and recreates indexes
 	// else, just createIndexes
@@ -358,6 +369,66 @@ func (ms *MongoStorage) createIndexes() error {
 		return fmt.Errorf("failed to create index on community ids for reputations: %w", err)
 	}
 
+	// Create an index for the 'userID' and 'createdAt' fields on reputation
+	// history to support the time series queries by user
+	reputationHistoryUserIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "userID", Value: 1},
+			{Key: "createdAt", Value: -1},
+		},
+	}
+	if _, err := ms.reputationHistory.Indexes().CreateOne(ctx, reputationHistoryUserIndex); err != nil {
+		return fmt.Errorf("failed to create index on user ids for reputation history: %w", err)
+	}
+
+	// Create an index for the 'communityID' and 'createdAt' fields on
+	// reputation history to support the time series queries by community
+	reputationHistoryCommunityIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "communityID", Value: 1},
+			{Key: "createdAt", Value: -1},
+		},
+	}
+	if _, err := ms.reputationHistory.Indexes().CreateOne(ctx, reputationHistoryCommunityIndex); err != nil {
+		return fmt.Errorf("failed to create index on community ids for reputation history: %w", err)
+	}
+
+	// Create the TTL index for the 'createdAt' field in the reputation history
+	// collection. With this index, the old snapshots will be automatically
+	// deleted after the retention period.
+	reputationHistoryTTLIndex := mongo.IndexModel{
+		Keys:    bson.M{"createdAt": 1},
+		Options: options.Index().SetExpireAfterSeconds(reputationHistoryRetentionSeconds),
+	}
+	if _, err := ms.reputationHistory.Indexes().CreateOne(ctx, reputationHistoryTTLIndex); err != nil {
+		return fmt.Errorf("failed to create ttl index on reputation history: %w", err)
+	}
+
+	// Create an index for the 'userID' and 'createdAt' fields on activities
+	// to support the queries of the activity done by a user
+	activitiesUserIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "userID", Value: 1},
+			{Key: "createdAt", Value: -1},
+		},
+	}
+	if _, err := ms.activities.Indexes().CreateOne(ctx, activitiesUserIndex); err != nil {
+		return fmt.Errorf("failed to create index on user ids for activities: %w", err)
+	}
+
+	// Create an index for the 'electionOwnerID' and 'createdAt' fields on
+	// activities to support the queries of the votes on the elections of a
+	// user
+	activitiesElectionOwnerIndex := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "electionOwnerID", Value: 1},
+			{Key: "createdAt", Value: -1},
+		},
+	}
+	if _, err := ms.activities.Indexes().CreateOne(ctx, activitiesElectionOwnerIndex); err != nil {
+		return fmt.Errorf("failed to create index on election owner ids for activities: %w", err)
+	}
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.vocdoni.io/dvote/log"
@@ -153,6 +154,83 @@ func (ms *MongoStorage) SetDetailedReputationForCommunity(communityID string, re
 	return ms.updateReputation(reputation)
 }
 
+// AddReputationSnapshots method stores a snapshot of every reputation
+// provided, all of them with the same creation time. It is used to keep track
+// of the evolution of the reputation of users and communities.
+func (ms *MongoStorage) AddReputationSnapshots(reputations []*Reputation, createdAt time.Time) error {
+	if len(reputations) == 0 {
+		return nil
+	}
+	ms.keysLock.Lock()
+	defer ms.keysLock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	snapshots := make([]any, 0, len(reputations))
+	for _, rep := range reputations {
+		snapshots = append(snapshots, &ReputationSnapshot{
+			ID:         primitive.NewObjectID(),
+			Reputation: *rep,
+			CreatedAt:  createdAt,
+		})
+	}
+	_, err := ms.reputationHistory.InsertMany(ctx, snapshots)
+	return err
+}
+
+// UserReputationSnapshots method returns the reputation snapshots of a user
+// taken after the given time, sorted from the oldest to the newest. If limit
+// is greater than zero, only the newest 'limit' snapshots are returned.
+func (ms *MongoStorage) UserReputationSnapshots(userID uint64, since time.Time, limit int64) ([]*ReputationSnapshot, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+	return ms.reputationSnapshots(bson.M{
+		"userID":      userID,
+		"communityID": "",
+		"createdAt":   bson.M{"$gte": since},
+	}, limit)
+}
+
+// CommunityReputationSnapshots method returns the reputation snapshots of a
+// community taken after the given time, sorted from the oldest to the newest.
+// If limit is greater than zero, only the newest 'limit' snapshots are
+// returned.
+func (ms *MongoStorage) CommunityReputationSnapshots(communityID string, since time.Time, limit int64) ([]*ReputationSnapshot, error) {
+	ms.keysLock.RLock()
+	defer ms.keysLock.RUnlock()
+	return ms.reputationSnapshots(bson.M{
+		"communityID": communityID,
+		"createdAt":   bson.M{"$gte": since},
+	}, limit)
+}
+
+func (ms *MongoStorage) reputationSnapshots(filter bson.M, limit int64) ([]*ReputationSnapshot, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// get the newest snapshots first to apply the limit
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(limit)
+	}
+	cur, err := ms.reputationHistory.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	snapshots := []*ReputationSnapshot{}
+	if err := cur.All(ctx, &snapshots); err != nil {
+		return nil, err
+	}
+	// reverse the snapshots to return them from the oldest to the newest
+	for i, j := 0, len(snapshots)-1; i < j; i, j = i+1, j-1 {
+		snapshots[i], snapshots[j] = snapshots[j], snapshots[i]
+	}
+	return snapshots, nil
+}
+
 func (ms *MongoStorage) userReputation(userID uint64) (*Reputation, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -68,24 +68,64 @@ type Reputation struct {
 	CastVotesCount        uint64 `json:"castVotesCount" bson:"castVotesCount"`
 	ParticipationsCount   uint64 `json:"participationsCount" bson:"participationsCount"`
 	CommunitiesCount      uint64 `json:"communitiesCount" bson:"communitiesCount"`
-	// boosters
-	HasVotecasterNFTPass           bool `json:"hasVotecasterNFTPass" bson:"hasVotecasterNFTPass"`
-	HasVotecasterLaunchNFT         bool `json:"hasVotecasterLaunchNFT" bson:"hasVotecasterLaunchNFT"`
-	IsVotecasterAlphafrensFollower bool `json:"isVotecasterAlphafrensFollower" bson:"isVotecasterAlphafrensFollower"`
-	IsVotecasterFarcasterFollower  bool `json:"isVotecasterFarcasterFollower" bson:"isVotecasterFarcasterFollower"`
-	IsVocdoniFarcasterFollower     bool `json:"isVocdoniFarcasterFollower" bson:"isVocdoniFarcasterFollower"`
-	VotecasterAnnouncementRecasted bool `json:"votecasterAnnouncementRecasted" bson:"votecasterAnnouncementRecasted"`
-	HasKIWI                        bool `json:"hasKIWI" bson:"hasKIWI"`
-	HasDegenDAONFT                 bool `json:"hasDegenDAONFT" bson:"hasDegenDAONFT"`
-	HasHaberdasheryNFT             bool `json:"hasHaberdasheryNFT" bson:"hasHaberdasheryNFT"`
-	Has10kDegenAtLeast             bool `json:"has10kDegenAtLeast" bson:"has10kDegenAtLeast"`
-	HasTokyoDAONFT                 bool `json:"hasTokyoDAONFT" bson:"hasTokyoDAONFT"`
-	HasProxy                       bool `json:"hasProxy" bson:"hasProxy"`
-	Has5ProxyAtLeast               bool `json:"has5ProxyAtLeast" bson:"has5ProxyAtLeast"`
-	HasProxyStudioNFT              bool `json:"hasProxyStudioNFT" bson:"hasProxyStudioNFT"`
-	HasNameDegen                   bool `json:"hasNameDegen" bson:"hasNameDegen"`
-	HasFarcasterOGNFT              bool `json:"hasFarcasterOGNFT" bson:"hasFarcasterOGNFT"`
-	HasMoxiePass                   bool `json:"hasMoxiePass" bson:"hasMoxiePass"`
+	// boosters, indexed by the booster ID
+	Boosters map[string]bool `json:"boosters" bson:"boosters"`
+}
+
+// ReputationSnapshot represents the state of the reputation of a user or a
+// community at a given time. It includes the same attributes as the
+// Reputation struct and the time when the snapshot was taken.
+type ReputationSnapshot struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	Reputation `bson:",inline"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+const (
+	// ActivityTypeVote is the type of the activity registered when a user
+	// casts a vote.
+	ActivityTypeVote = "vote"
+	// ActivityTypeElection is the type of the activity registered when a user
+	// creates an election.
+	ActivityTypeElection = "election"
+)
+
+// Activity represents a timestamped action of a user that counts for their
+// reputation. It includes the type of the action, the user that did it, the
+// election involved and its owner, and the weight of the action (the
+// participation of a vote, which counts for the owner of the election).
+type Activity struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id"`
+	Type            string             `json:"type" bson:"type"`
+	UserID          uint64             `json:"userID" bson:"userID"`
+	ElectionID      string             `json:"electionID" bson:"electionID"`
+	ElectionOwnerID uint64             `json:"electionOwnerID" bson:"electionOwnerID"`
+	Weight          uint64             `json:"weight" bson:"weight"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// FeatureThresholds represents the reputation thresholds of the features set
+// for a community, indexed by the string representation of the features. If
+// the community ID is empty, they are the global thresholds.
+type FeatureThresholds struct {
+	CommunityID string            `json:"communityID" bson:"_id"`
+	Thresholds  map[string]uint32 `json:"thresholds" bson:"thresholds"`
+}
+
+// EventCursor represents the last event ID processed by a consumer of an
+// event subscription, identified by its name, to resume it after a restart.
+type EventCursor struct {
+	Name      string    `json:"name" bson:"_id"`
+	EventID   uint64    `json:"eventId" bson:"eventId"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// FrameAnalytics represents the aggregated frame events of an election, by
+// frame and event type.
+type FrameAnalytics struct {
+	ElectionID string                       `json:"electionId" bson:"_id"`
+	Events     map[string]map[string]uint64 `json:"events" bson:"events"`
+	UpdatedAt  time.Time                    `json:"updatedAt" bson:"updatedAt"`
 }
 
 // ElectionCommunity represents the community used to create an election.
@@ -94,6 +134,20 @@ type ElectionCommunity struct {
 	Name string `json:"name" bson:"name"`
 }
 
+// ElectionCast represents the bot cast that published an election created
+// from the bot. It includes if the final results of the election must be
+// posted into the thread of the cast, if they have been already posted and
+// the failed attempts to post them, which are not retried once they fail too
+// many times.
+type ElectionCast struct {
+	FID             uint64 `json:"fid" bson:"fid"`
+	Hash            string `json:"hash" bson:"hash"`
+	PostResults     bool   `json:"postResults" bson:"postResults"`
+	ResultsPosted   bool   `json:"resultsPosted" bson:"resultsPosted"`
+	ResultsFailed   bool   `json:"resultsFailed,omitempty" bson:"resultsFailed,omitempty"`
+	ResultsAttempts int    `json:"resultsAttempts,omitempty" bson:"resultsAttempts,omitempty"`
+}
+
 // Election represents an election and its details owned by a user.
 type Election struct {
 	ElectionID            string             `json:"electionId" bson:"_id"`
@@ -108,6 +162,8 @@ type Election struct {
 	Question              string             `json:"question" bson:"question"`
 	Community             *ElectionCommunity `json:"community" bson:"community"`
 	CastedWeight          string             `json:"castedWeight" bson:"castedWeight"`
+	Cast                  *ElectionCast      `json:"cast,omitempty" bson:"cast,omitempty"`
+	Protocols             []string           `json:"protocols,omitempty" bson:"protocols,omitempty"`
 }
 
 // Census stores the census of an election ready to be used for voting on farcaster.
@@ -14,6 +14,10 @@ import (
 	"go.vocdoni.io/dvote/types"
 )
 
+// IncreaseVoteCount counts the vote of the user provided in the election
+// provided, with the weight and the participation provided. The users that
+// already voted in the election are not counted again, so the votes that
+// overwrite a previous vote do not increase the counters.
 func (ms *MongoStorage) IncreaseVoteCount(userFID uint64, electionID types.HexBytes, weight *big.Int, participation uint32) error {
 	ms.keysLock.Lock()
 	defer ms.keysLock.Unlock()
@@ -24,6 +28,17 @@ func (ms *MongoStorage) IncreaseVoteCount(userFID uint64, electionID types.HexBy
 		"participation", participation,
 	)
 
+	// skip the users that already voted, which are changing their vote, the
+	// election is unknown if it has no voters yet
+	voters, err := ms.votersOfElection(electionID)
+	if err != nil && !errors.Is(err, ErrElectionUnknown) {
+		return fmt.Errorf("failed to get the voters of the election: %w", err)
+	}
+	if err == nil && ms.isUserVoter(voters, userFID) {
+		log.Debugw("vote overwritten, not counted", "userID", userFID, "electionID", electionID.String())
+		return nil
+	}
+
 	user, err := ms.userData(userFID)
 	if err != nil {
 		return err
@@ -62,6 +77,12 @@ func (ms *MongoStorage) IncreaseVoteCount(userFID uint64, electionID types.HexBy
 	if err := ms.updateElection(election); err != nil {
 		return err
 	}
+	// register the vote as a timestamped activity of the user, the
+	// participation counts for the owner of the election
+	if err := ms.addActivity(ActivityTypeVote, userFID, election, uint64(participation)); err != nil {
+		log.Warnw("failed to register vote activity", "userID", userFID,
+			"electionID", electionID.String(), "error", err)
+	}
 
 	return ms.addVoterToElection(election, userFID)
 }
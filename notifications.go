@@ -6,9 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
+	"github.com/vocdoni/vote-frame/frametemplates"
 	"github.com/vocdoni/vote-frame/imageframe"
 	"github.com/vocdoni/vote-frame/mongo"
 	"go.vocdoni.io/dvote/httprouter"
@@ -20,10 +20,10 @@ import (
 
 func (v *vocdoniHandler) notificationsHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
 	png := imageframe.NotificationsImage()
-	response := strings.ReplaceAll(frame(frameNotifications), "{image}", imageLink(png))
-
-	ctx.SetResponseContentType("text/html; charset=utf-8")
-	return ctx.Send([]byte(response), http.StatusOK)
+	return v.sendFrame(ctx, nil, frametemplates.Notifications{
+		Page: frametemplates.Page{Image: imageLink(png)},
+		Kind: frametemplates.NotificationsSettings,
+	})
 }
 
 func (v *vocdoniHandler) notificationsResponseHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
@@ -43,9 +43,10 @@ func (v *vocdoniHandler) notificationsResponseHandler(msg *apirest.APIdata, ctx
 	if actionMessage.ButtonIndex == 3 {
 		// User has clicked the "filter by user" button
 		png := imageframe.NotificationsManageImage()
-		response := strings.ReplaceAll(frame(frameNotificationsManager), "{image}", imageLink(png))
-		ctx.SetResponseContentType("text/html; charset=utf-8")
-		return ctx.Send([]byte(response), http.StatusOK)
+		return v.sendFrame(ctx, nil, frametemplates.Notifications{
+			Page: frametemplates.Page{Image: imageLink(png)},
+			Kind: frametemplates.NotificationsManager,
+		})
 	}
 
 	allowNotifications := actionMessage.ButtonIndex == 1
@@ -60,10 +61,10 @@ func (v *vocdoniHandler) notificationsResponseHandler(msg *apirest.APIdata, ctx
 	} else {
 		png = imageframe.NotificationsDeniedImage()
 	}
-	response := strings.ReplaceAll(frame(frameNotificationsResponse), "{image}", imageLink(png))
-
-	ctx.SetResponseContentType("text/html; charset=utf-8")
-	return ctx.Send([]byte(response), http.StatusOK)
+	return v.sendFrame(ctx, nil, frametemplates.Notifications{
+		Page: frametemplates.Page{Image: imageLink(png)},
+		Kind: frametemplates.NotificationsResponse,
+	})
 }
 
 func (v *vocdoniHandler) notificationsFilterByUserHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
@@ -83,9 +84,15 @@ func (v *vocdoniHandler) notificationsFilterByUserHandler(msg *apirest.APIdata,
 	setErrorResponse := func(err error) []byte {
 		log.Warnw("failed to filter by user", "error", err)
 		png := imageframe.NotificationsErrorImage()
-		response := strings.ReplaceAll(frame(frameNotificationsResponse), "{image}", imageLink(png))
+		response, err := v.renderFrame(nil, frametemplates.Notifications{
+			Page: frametemplates.Page{Image: imageLink(png)},
+			Kind: frametemplates.NotificationsResponse,
+		})
+		if err != nil {
+			log.Warnw("failed to render frame", "error", err)
+		}
 		ctx.SetResponseContentType("text/html; charset=utf-8")
-		return []byte(response)
+		return response
 	}
 
 	if len(actionMessage.InputText) == 0 {
@@ -117,10 +124,10 @@ func (v *vocdoniHandler) notificationsFilterByUserHandler(msg *apirest.APIdata,
 	} else {
 		png = imageframe.NotificationsDeniedImage()
 	}
-	response := strings.ReplaceAll(frame(frameNotificationsResponse), "{image}", imageLink(png))
-
-	ctx.SetResponseContentType("text/html; charset=utf-8")
-	return ctx.Send([]byte(response), http.StatusOK)
+	return v.sendFrame(ctx, nil, frametemplates.Notifications{
+		Page: frametemplates.Page{Image: imageLink(png)},
+		Kind: frametemplates.NotificationsResponse,
+	})
 }
 
 // notificationsForceSendHandler enqueue the notifications for the given election and its users.
@@ -252,7 +252,7 @@ func (nm *NotificationManager) handleNotifications(notifications []mongo.Notific
 				mentions = []uint64{n.UserID, botdata.FID}
 			}
 			// send the notification and remove it from the database
-			if err := nm.api.Reply(nm.ctx, notificationThread, msg, mentions, n.FrameUrl); err != nil {
+			if _, err := nm.api.Reply(nm.ctx, notificationThread, msg, mentions, n.FrameUrl); err != nil {
 				errCh <- fmt.Errorf("error sending notification: %s", err)
 				return
 			}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vocdoni/vote-frame/farcasterapi"
+	"github.com/vocdoni/vote-frame/frametemplates"
+	"github.com/vocdoni/vote-frame/openframes"
+	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
+)
+
+// ErrOpenFrameVote is returned when a vote is received from an Open Frames
+// client. The polls use the Farcaster frame census of the vochain, which only
+// accepts votes proven by a frame message signed by a Farcaster app key of the
+// voter, so the votes must be cast from a Farcaster client. The Open Frames
+// support is limited to showing the polls and the eligibility of the voters.
+var ErrOpenFrameVote = fmt.Errorf("this poll can only be voted from a Farcaster client")
+
+// isOpenFrame returns if the frame signature packet provided comes from an
+// Open Frames client other than Farcaster.
+func isOpenFrame(body []byte) bool {
+	protocol, err := openframes.Detect(body)
+	return err == nil && protocol != openframes.ProtocolFarcaster
+}
+
+// electionProtocols returns the Open Frames client protocols accepted by the
+// election provided, which are the default ones if it does not declare them.
+func (v *vocdoniHandler) electionProtocols(electionID types.HexBytes) []string {
+	election, err := v.db.Election(electionID)
+	if err != nil || len(election.Protocols) == 0 {
+		return openframes.DefaultProtocols
+	}
+	return election.Protocols
+}
+
+// acceptedProtocols returns the Open Frames client protocols announced by the
+// frames of the election provided, or nil if it only accepts the Farcaster
+// clients, which do not need the Open Frames tags.
+func (v *vocdoniHandler) acceptedProtocols(electionID types.HexBytes) []frametemplates.Protocol {
+	protocols := v.electionProtocols(electionID)
+	if len(protocols) == 1 && protocols[0] == openframes.ProtocolFarcaster {
+		return nil
+	}
+	accepts := []frametemplates.Protocol{}
+	for _, protocol := range protocols {
+		accepts = append(accepts, frametemplates.Protocol{
+			Name:    protocol,
+			Version: openframes.Version(protocol),
+		})
+	}
+	return accepts
+}
+
+// openFrameVoteData verifies the Open Frames packet provided, checks that its
+// client protocol is accepted by the election provided and that it was signed
+// for the election, and maps the signer to their census key: the Farcaster
+// user that verified the signing address and one of their app keys included
+// in the census of the election. It returns the vote data of the voter and an
+// error if they are not eligible to vote, like extractVoteDataAndCheckIfEligible.
+func (v *vocdoniHandler) openFrameVoteData(body []byte, electionID types.HexBytes, root []byte,
+	maxOverwrites uint32, requireState bool,
+) (*voteData, error) {
+	identity, err := v.openFrames.Verify(body, v.electionProtocols(electionID))
+	if err != nil {
+		return nil, err
+	}
+	// the freshness of the message is its signed deadline, checked by the
+	// verifier, the rest of the checks are the same as the Farcaster frame
+	// messages ones
+	if err := checkFrameTarget(identity.URL, identity.State, electionID, requireState); err != nil {
+		return nil, err
+	}
+	fid, signers, err := v.userByVerifiedAddress(identity.Address.Hex())
+	if err != nil {
+		log.Debugw("open frame signer not found",
+			"electionID", electionID.String(),
+			"protocol", identity.Protocol,
+			"address", identity.Address.Hex(),
+			"error", err,
+		)
+		return &voteData{}, ErrNotInCensus
+	}
+
+	log.Infow("received open frame vote request",
+		"electionID", electionID,
+		"protocol", identity.Protocol,
+		"address", identity.Address.Hex(),
+		"fid", fid,
+		"button", identity.ButtonIndex,
+	)
+
+	// look for the app key of the user included in the census
+	data := &voteData{FID: fid}
+	for _, signer := range signers {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(signer, "0x"))
+		if err != nil {
+			continue
+		}
+		data, err = voterEligibility(pubKey, fid, electionID, root, maxOverwrites, v.cli)
+		if !errors.Is(err, ErrNotInCensus) {
+			return data, err
+		}
+	}
+	return data, ErrNotInCensus
+}
+
+// userByVerifiedAddress returns the FID and the app keys of the Farcaster user
+// that has verified the address provided. The user is looked up in the
+// database and then in the Farcaster API.
+func (v *vocdoniHandler) userByVerifiedAddress(address string) (uint64, []string, error) {
+	if user, err := v.db.UserByAddress(address); err == nil && len(user.Signers) > 0 {
+		return user.UserID, user.Signers, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	users, err := v.fcapi.UserDataByVerificationAddress(ctx, []string{address})
+	if err != nil && !errors.Is(err, farcasterapi.ErrPartialData) {
+		return 0, nil, err
+	}
+	if len(users) == 0 {
+		return 0, nil, fmt.Errorf("no user with the verified address %s", address)
+	}
+	return users[0].FID, users[0].Signers, nil
+}
@@ -0,0 +1,143 @@
+package openframes
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// lensChainID is the ID of the chain of the EIP-712 domain of the Lens frame
+// messages (Polygon).
+const lensChainID = 137
+
+// lensUntrustedData struct mirrors the signed fields of the untrusted data of
+// the Lens frame signature packets. The packets also include an unsigned
+// timestamp, which is ignored since it cannot be trusted.
+type lensUntrustedData struct {
+	SpecVersion    string `json:"specVersion"`
+	URL            string `json:"url"`
+	ButtonIndex    uint32 `json:"buttonIndex"`
+	ProfileID      string `json:"profileId"`
+	PubID          string `json:"pubId"`
+	InputText      string `json:"inputText"`
+	State          string `json:"state"`
+	ActionResponse string `json:"actionResponse"`
+	Deadline       int64  `json:"deadline"`
+}
+
+// LensVerifier struct verifies the Lens frame messages, whose signature is
+// the EIP-712 signature of their untrusted data. It recovers the signing
+// address, but it does not check that the address is the owner of the Lens
+// profile of the message or one of its delegated executors, so the profile ID
+// is not returned as part of the identity of the signer.
+type LensVerifier struct{}
+
+// NewLensVerifier creates a new Lens frame messages verifier.
+func NewLensVerifier() *LensVerifier {
+	return &LensVerifier{}
+}
+
+// Verify decodes the Lens untrusted data of the packet provided, checks that
+// the signed deadline has not passed and recovers the address that signed
+// it.
+func (lv *LensVerifier) Verify(packet *Packet) (*Identity, error) {
+	data := &lensUntrustedData{}
+	if err := json.Unmarshal(packet.UntrustedData, data); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPacket, err)
+	}
+	if data.SpecVersion != LensVersion {
+		return nil, fmt.Errorf("%w: unsupported spec version %q", ErrInvalidPacket, data.SpecVersion)
+	}
+	if data.Deadline < time.Now().Unix() {
+		return nil, fmt.Errorf("%w: expired signature", ErrInvalidSignature)
+	}
+	signature, err := hexutil.Decode(packet.TrustedData.MessageBytes)
+	if err != nil || len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidSignature)
+	}
+	address, err := recoverLensSigner(data, signature)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSignature, err)
+	}
+	return &Identity{
+		Address:     address,
+		ButtonIndex: data.ButtonIndex,
+		InputText:   data.InputText,
+		URL:         data.URL,
+		State:       []byte(data.State),
+		Deadline:    time.Unix(data.Deadline, 0),
+	}, nil
+}
+
+// lensTypedDataHash returns the EIP-712 hash of the Lens untrusted data
+// provided.
+func lensTypedDataHash(data *lensUntrustedData) ([]byte, error) {
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"FrameData": {
+				{Name: "specVersion", Type: "string"},
+				{Name: "url", Type: "string"},
+				{Name: "buttonIndex", Type: "uint256"},
+				{Name: "profileId", Type: "string"},
+				{Name: "pubId", Type: "string"},
+				{Name: "inputText", Type: "string"},
+				{Name: "state", Type: "string"},
+				{Name: "actionResponse", Type: "string"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "FrameData",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Lens Frames",
+			Version:           LensVersion,
+			ChainId:           math.NewHexOrDecimal256(lensChainID),
+			VerifyingContract: common.Address{}.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"specVersion":    data.SpecVersion,
+			"url":            data.URL,
+			"buttonIndex":    new(big.Int).SetUint64(uint64(data.ButtonIndex)),
+			"profileId":      data.ProfileID,
+			"pubId":          data.PubID,
+			"inputText":      data.InputText,
+			"state":          data.State,
+			"actionResponse": data.ActionResponse,
+			"deadline":       big.NewInt(data.Deadline),
+		},
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	return hash, err
+}
+
+// recoverLensSigner returns the address that signed the Lens untrusted data
+// provided with the signature provided.
+func recoverLensSigner(data *lensUntrustedData, signature []byte) (common.Address, error) {
+	hash, err := lensTypedDataHash(data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	// the wallets set the recovery ID to 27 or 28
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
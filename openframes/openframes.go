@@ -0,0 +1,227 @@
+// openframes package adapts the frame messages of the Open Frames clients, the
+// frame-capable clients other than Farcaster, to the frame server. It detects
+// the client protocol of the frame signature packets received, verifies the
+// client-specific signature of the non Farcaster messages and returns the
+// identity of their signer, which is an Ethereum address that can be mapped to
+// a census key. The Farcaster messages are still validated by the
+// framevalidator package.
+package openframes
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+const (
+	// ProtocolFarcaster is the client protocol of the Farcaster clients. The
+	// packets without client protocol are Farcaster packets.
+	ProtocolFarcaster = "farcaster"
+	// FarcasterVersion is the Farcaster protocol version announced
+	FarcasterVersion = "vNext"
+	// ProtocolLens is the client protocol of the Lens clients
+	ProtocolLens = "lens"
+	// LensVersion is the Lens protocol version supported
+	LensVersion = "1.0.0"
+	// OpenFramesVersion is the version of the Open Frames specification
+	// announced by the frames
+	OpenFramesVersion = "vNext"
+	// MaxMessageLifetime is the maximum time until the signed deadline of the
+	// frame messages accepted, so the messages verified can be remembered
+	// until they expire to reject their replays
+	MaxMessageLifetime = time.Hour
+	// seenMessagesSize is the maximum number of messages verified remembered
+	seenMessagesSize = 100000
+)
+
+var (
+	// ErrInvalidPacket is returned when the frame signature packet is
+	// malformed.
+	ErrInvalidPacket = fmt.Errorf("invalid open frame packet")
+	// ErrInvalidSignature is returned when the client-specific signature of
+	// the frame message cannot be verified.
+	ErrInvalidSignature = fmt.Errorf("invalid open frame signature")
+	// ErrProtocolNotSupported is returned when the client protocol of the
+	// packet is unknown or has no verifier.
+	ErrProtocolNotSupported = fmt.Errorf("client protocol not supported")
+	// ErrProtocolNotAccepted is returned when the client protocol of the
+	// packet is not accepted by the poll.
+	ErrProtocolNotAccepted = fmt.Errorf("client protocol not accepted")
+	// ErrReplayedMessage is returned when the frame message has already been
+	// verified.
+	ErrReplayedMessage = fmt.Errorf("replayed open frame message")
+)
+
+// versions contains the version supported of every known client protocol.
+var versions = map[string]string{
+	ProtocolFarcaster: FarcasterVersion,
+	ProtocolLens:      LensVersion,
+}
+
+// DefaultProtocols are the client protocols accepted by the polls that do not
+// declare them.
+var DefaultProtocols = []string{ProtocolFarcaster}
+
+// Packet struct mirrors the JSON structure of the Open Frames signature
+// packets. The untrusted data is client-specific, so it is decoded by the
+// verifier of every protocol.
+type Packet struct {
+	ClientProtocol string          `json:"clientProtocol"`
+	UntrustedData  json.RawMessage `json:"untrustedData"`
+	TrustedData    struct {
+		MessageBytes string `json:"messageBytes"`
+	} `json:"trustedData"`
+}
+
+// Identity struct contains the data of a frame message verified. The address
+// is the Ethereum address that signed the message, which is the only identity
+// of the signer, since the client-specific profile claimed in the message
+// cannot be checked against it. The deadline is the signed time until which
+// the message is valid.
+type Identity struct {
+	Protocol    string
+	Address     common.Address
+	ButtonIndex uint32
+	InputText   string
+	URL         string
+	State       []byte
+	Deadline    time.Time
+}
+
+// Verifier interface defines the verifiers of the client-specific signatures
+// of the frame messages.
+type Verifier interface {
+	Verify(packet *Packet) (*Identity, error)
+}
+
+// ParseClientProtocol splits the client protocol provided, with the format
+// <protocol>@<version>, into the protocol and the version. An empty client
+// protocol is the Farcaster one.
+func ParseClientProtocol(clientProtocol string) (string, string, error) {
+	if clientProtocol == "" {
+		return ProtocolFarcaster, FarcasterVersion, nil
+	}
+	protocol, version, ok := strings.Cut(clientProtocol, "@")
+	if !ok || protocol == "" || version == "" {
+		return "", "", fmt.Errorf("%w: malformed client protocol %q", ErrInvalidPacket, clientProtocol)
+	}
+	return protocol, version, nil
+}
+
+// Detect returns the client protocol of the frame signature packet provided.
+func Detect(body []byte) (string, error) {
+	packet := &Packet{}
+	if err := json.Unmarshal(body, packet); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidPacket, err)
+	}
+	protocol, _, err := ParseClientProtocol(packet.ClientProtocol)
+	return protocol, err
+}
+
+// ValidateProtocols checks that the client protocols provided are known.
+func ValidateProtocols(protocols []string) error {
+	for _, protocol := range protocols {
+		if _, ok := versions[protocol]; !ok {
+			return fmt.Errorf("%w: %s", ErrProtocolNotSupported, protocol)
+		}
+	}
+	return nil
+}
+
+// Accepts returns if the client protocol provided is one of the accepted ones.
+// If no protocols are accepted, the default ones are.
+func Accepts(accepted []string, protocol string) bool {
+	if len(accepted) == 0 {
+		accepted = DefaultProtocols
+	}
+	for _, p := range accepted {
+		if p == protocol {
+			return true
+		}
+	}
+	return false
+}
+
+// Version returns the version supported of the client protocol provided, or
+// an empty string if it is unknown.
+func Version(protocol string) string {
+	return versions[protocol]
+}
+
+// Adapter struct verifies the frame messages of the client protocols with a
+// registered verifier. It remembers the messages verified until they expire
+// to reject their replays.
+type Adapter struct {
+	verifiers map[string]Verifier
+	seenMtx   sync.Mutex
+	seen      *expirable.LRU[string, struct{}]
+}
+
+// New creates a new adapter with the verifiers of the non Farcaster client
+// protocols supported.
+func New() *Adapter {
+	return &Adapter{
+		verifiers: map[string]Verifier{
+			ProtocolLens: NewLensVerifier(),
+		},
+		seen: expirable.NewLRU[string, struct{}](seenMessagesSize, nil, MaxMessageLifetime),
+	}
+}
+
+// Register sets the verifier of the client protocol provided.
+func (a *Adapter) Register(protocol string, verifier Verifier) {
+	a.verifiers[protocol] = verifier
+}
+
+// Verify decodes the frame signature packet provided, checks that its client
+// protocol is accepted and verifies its signature with the verifier of the
+// protocol. The messages whose deadline is further than MaxMessageLifetime
+// and the messages already verified are rejected. It returns the identity of
+// the signer or an error that wraps ErrInvalidPacket, ErrInvalidSignature,
+// ErrProtocolNotSupported, ErrProtocolNotAccepted or ErrReplayedMessage.
+func (a *Adapter) Verify(body []byte, accepted []string) (*Identity, error) {
+	packet := &Packet{}
+	if err := json.Unmarshal(body, packet); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPacket, err)
+	}
+	protocol, version, err := ParseClientProtocol(packet.ClientProtocol)
+	if err != nil {
+		return nil, err
+	}
+	if !Accepts(accepted, protocol) {
+		return nil, fmt.Errorf("%w: %s", ErrProtocolNotAccepted, protocol)
+	}
+	verifier, ok := a.verifiers[protocol]
+	if !ok || version != versions[protocol] {
+		return nil, fmt.Errorf("%w: %s@%s", ErrProtocolNotSupported, protocol, version)
+	}
+	identity, err := verifier.Verify(packet)
+	if err != nil {
+		return nil, err
+	}
+	if identity.Deadline.After(time.Now().Add(MaxMessageLifetime)) {
+		return nil, fmt.Errorf("%w: deadline too far", ErrInvalidSignature)
+	}
+	if !a.markSeen(protocol + ":" + packet.TrustedData.MessageBytes) {
+		return nil, ErrReplayedMessage
+	}
+	identity.Protocol = protocol
+	return identity, nil
+}
+
+// markSeen remembers the message provided and returns true, or returns false
+// if it was already seen.
+func (a *Adapter) markSeen(message string) bool {
+	a.seenMtx.Lock()
+	defer a.seenMtx.Unlock()
+	if _, ok := a.seen.Get(message); ok {
+		return false
+	}
+	a.seen.Add(message, struct{}{})
+	return true
+}
@@ -0,0 +1,130 @@
+package openframes
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	qt "github.com/frankban/quicktest"
+)
+
+const testURL = "https://farcaster.vote/poll/abcd"
+
+// lensPacket returns a Lens frame signature packet with the untrusted data
+// provided signed by the key provided.
+func lensPacket(c *qt.C, key *ecdsa.PrivateKey, data *lensUntrustedData) []byte {
+	hash, err := lensTypedDataHash(data)
+	c.Assert(err, qt.IsNil)
+	signature, err := crypto.Sign(hash, key)
+	c.Assert(err, qt.IsNil)
+	signature[crypto.RecoveryIDOffset] += 27
+	untrustedData, err := json.Marshal(data)
+	c.Assert(err, qt.IsNil)
+	packet := &Packet{
+		ClientProtocol: ProtocolLens + "@" + LensVersion,
+		UntrustedData:  untrustedData,
+	}
+	packet.TrustedData.MessageBytes = hexutil.Encode(signature)
+	body, err := json.Marshal(packet)
+	c.Assert(err, qt.IsNil)
+	return body
+}
+
+func testLensData() *lensUntrustedData {
+	return &lensUntrustedData{
+		SpecVersion: LensVersion,
+		URL:         testURL,
+		ButtonIndex: 2,
+		ProfileID:   "0x01",
+		PubID:       "0x01-0x01",
+		Deadline:    time.Now().Add(10 * time.Minute).Unix(),
+	}
+}
+
+func TestDetect(t *testing.T) {
+	c := qt.New(t)
+
+	protocol, err := Detect([]byte(`{"untrustedData":{"fid":1},"trustedData":{"messageBytes":"0a"}}`))
+	c.Assert(err, qt.IsNil)
+	c.Assert(protocol, qt.Equals, ProtocolFarcaster)
+	protocol, err = Detect([]byte(`{"clientProtocol":"lens@1.0.0"}`))
+	c.Assert(err, qt.IsNil)
+	c.Assert(protocol, qt.Equals, ProtocolLens)
+	_, err = Detect([]byte(`{"clientProtocol":"lens"}`))
+	c.Assert(err, qt.ErrorIs, ErrInvalidPacket)
+	_, err = Detect([]byte(`not json`))
+	c.Assert(err, qt.ErrorIs, ErrInvalidPacket)
+}
+
+func TestProtocols(t *testing.T) {
+	c := qt.New(t)
+
+	c.Assert(ValidateProtocols([]string{ProtocolFarcaster, ProtocolLens}), qt.IsNil)
+	c.Assert(ValidateProtocols([]string{"xmtp"}), qt.ErrorIs, ErrProtocolNotSupported)
+	// the polls without protocols only accept the Farcaster clients
+	c.Assert(Accepts(nil, ProtocolFarcaster), qt.IsTrue)
+	c.Assert(Accepts(nil, ProtocolLens), qt.IsFalse)
+	c.Assert(Accepts([]string{ProtocolLens}, ProtocolLens), qt.IsTrue)
+	c.Assert(Version(ProtocolLens), qt.Equals, LensVersion)
+	c.Assert(Version("xmtp"), qt.Equals, "")
+}
+
+func TestLensVerify(t *testing.T) {
+	c := qt.New(t)
+
+	key, err := crypto.GenerateKey()
+	c.Assert(err, qt.IsNil)
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	adapter := New()
+	accepted := []string{ProtocolFarcaster, ProtocolLens}
+
+	identity, err := adapter.Verify(lensPacket(c, key, testLensData()), accepted)
+	c.Assert(err, qt.IsNil)
+	c.Assert(identity.Protocol, qt.Equals, ProtocolLens)
+	c.Assert(identity.Address, qt.Equals, address)
+	c.Assert(identity.ButtonIndex, qt.Equals, uint32(2))
+	c.Assert(identity.URL, qt.Equals, testURL)
+	c.Assert(identity.Deadline.After(time.Now()), qt.IsTrue)
+
+	// the messages already verified are rejected
+	_, err = adapter.Verify(lensPacket(c, key, testLensData()), accepted)
+	c.Assert(err, qt.ErrorIs, ErrReplayedMessage)
+
+	// the polls that do not accept Lens reject the packet
+	_, err = adapter.Verify(lensPacket(c, key, testLensData()), nil)
+	c.Assert(err, qt.ErrorIs, ErrProtocolNotAccepted)
+
+	// the tampered data recovers another address
+	body := lensPacket(c, key, testLensData())
+	packet := &Packet{}
+	c.Assert(json.Unmarshal(body, packet), qt.IsNil)
+	tampered := testLensData()
+	tampered.ButtonIndex = 3
+	packet.UntrustedData, err = json.Marshal(tampered)
+	c.Assert(err, qt.IsNil)
+	identity, err = NewLensVerifier().Verify(packet)
+	c.Assert(err, qt.IsNil)
+	c.Assert(identity.Address, qt.Not(qt.Equals), address)
+
+	// the expired and malformed signatures are rejected
+	expired := testLensData()
+	expired.Deadline = time.Now().Add(-time.Minute).Unix()
+	_, err = adapter.Verify(lensPacket(c, key, expired), accepted)
+	c.Assert(err, qt.ErrorIs, ErrInvalidSignature)
+	longLived := testLensData()
+	longLived.Deadline = time.Now().Add(2 * MaxMessageLifetime).Unix()
+	_, err = adapter.Verify(lensPacket(c, key, longLived), accepted)
+	c.Assert(err, qt.ErrorIs, ErrInvalidSignature)
+	packet.TrustedData.MessageBytes = "0x0a"
+	_, err = NewLensVerifier().Verify(packet)
+	c.Assert(err, qt.ErrorIs, ErrInvalidSignature)
+
+	// the unsupported versions and protocols are rejected
+	_, err = adapter.Verify([]byte(`{"clientProtocol":"lens@2.0.0"}`), accepted)
+	c.Assert(err, qt.ErrorIs, ErrProtocolNotSupported)
+	_, err = adapter.Verify([]byte(`{"clientProtocol":"xmtp@2024-02-09"}`), []string{"xmtp"})
+	c.Assert(err, qt.ErrorIs, ErrProtocolNotSupported)
+}
@@ -0,0 +1,152 @@
+// quota package defines a per user rate limiter for the expensive actions of
+// the service, such as creating polls or censuses. Every user can do a limited
+// number of every action in a sliding time window, and the limit is scaled by
+// the reputation tier of the user.
+package quota
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Action represents an action of a user limited by a quota.
+type Action string
+
+const (
+	// PollCreation is the action of creating a poll, from the API or from the
+	// bot.
+	PollCreation Action = "pollCreation"
+	// CensusCreation is the action of creating a census that requires to
+	// query external services, such as CSV or channel censuses.
+	CensusCreation Action = "censusCreation"
+)
+
+// Limit struct defines the max number of times that an action can be done in
+// a time window by a user of the lowest reputation tier.
+type Limit struct {
+	Max    int
+	Window time.Duration
+}
+
+// Tier struct defines a reputation tier, every user with a reputation equal
+// or greater than MinReputation gets the base limits multiplied by the
+// Multiplier of the tier.
+type Tier struct {
+	MinReputation uint32
+	Multiplier    int
+}
+
+// DefaultLimits contains the default base limits of every action.
+var DefaultLimits = map[Action]Limit{
+	PollCreation:   {Max: 5, Window: time.Hour},
+	CensusCreation: {Max: 3, Window: time.Hour},
+}
+
+// DefaultTiers contains the default reputation tiers.
+var DefaultTiers = []Tier{
+	{MinReputation: 0, Multiplier: 1},
+	{MinReputation: 10, Multiplier: 2},
+	{MinReputation: 30, Multiplier: 4},
+	{MinReputation: 60, Multiplier: 8},
+}
+
+// ExceededError is returned when a user has exceeded the quota of an action.
+// It includes the time to wait until the action can be done again.
+type ExceededError struct {
+	Action     Action
+	RetryAfter time.Duration
+}
+
+// Error returns the error message of the ExceededError.
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("quota of %s exceeded, retry after %s", e.Action, e.RetryAfter)
+}
+
+// Limiter struct keeps track of the actions done by every user and checks
+// them against the quotas. It is safe for concurrent use.
+type Limiter struct {
+	mtx    sync.Mutex
+	limits map[Action]Limit
+	tiers  []Tier
+	events map[Action]map[uint64][]time.Time
+	now    func() time.Time
+}
+
+// NewLimiter creates a new Limiter with the base limits and the reputation
+// tiers provided. If no tiers are provided, the base limits are used for
+// every user.
+func NewLimiter(limits map[Action]Limit, tiers []Tier) *Limiter {
+	sortedTiers := append([]Tier{}, tiers...)
+	sort.Slice(sortedTiers, func(i, j int) bool {
+		return sortedTiers[i].MinReputation < sortedTiers[j].MinReputation
+	})
+	return &Limiter{
+		limits: limits,
+		tiers:  sortedTiers,
+		events: make(map[Action]map[uint64][]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Allow checks if the user with the FID and the reputation provided can do the
+// action provided. If they can, the action is registered and nil is returned.
+// If they cannot, an ExceededError is returned with the time to wait until the
+// oldest action in the window expires. The actions without limits are always
+// allowed.
+func (l *Limiter) Allow(fid uint64, action Action, reputation uint32) error {
+	limit, ok := l.limits[action]
+	if !ok || limit.Max <= 0 || limit.Window <= 0 {
+		return nil
+	}
+	max := limit.Max * l.multiplier(reputation)
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	now := l.now()
+	if _, ok := l.events[action]; !ok {
+		l.events[action] = make(map[uint64][]time.Time)
+	}
+	// discard the events out of the window
+	events := l.events[action][fid]
+	for len(events) > 0 && now.Sub(events[0]) >= limit.Window {
+		events = events[1:]
+	}
+	if len(events) >= max {
+		l.events[action][fid] = events
+		return &ExceededError{
+			Action:     action,
+			RetryAfter: limit.Window - now.Sub(events[len(events)-max]),
+		}
+	}
+	l.events[action][fid] = append(events, now)
+	return nil
+}
+
+// Max returns the max number of times that the action provided can be done
+// in its time window by a user with the reputation provided. If the action
+// has no limits, it returns 0.
+func (l *Limiter) Max(action Action, reputation uint32) int {
+	limit, ok := l.limits[action]
+	if !ok {
+		return 0
+	}
+	return limit.Max * l.multiplier(reputation)
+}
+
+// multiplier returns the multiplier of the highest tier reached with the
+// reputation provided. If no tier is reached, it returns 1.
+func (l *Limiter) multiplier(reputation uint32) int {
+	multiplier := 1
+	for _, tier := range l.tiers {
+		if reputation < tier.MinReputation {
+			break
+		}
+		multiplier = tier.Multiplier
+	}
+	if multiplier < 1 {
+		return 1
+	}
+	return multiplier
+}
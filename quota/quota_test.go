@@ -0,0 +1,58 @@
+package quota
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	c := qt.New(t)
+	now := time.Unix(1700000000, 0)
+	l := NewLimiter(map[Action]Limit{
+		PollCreation: {Max: 2, Window: time.Hour},
+	}, DefaultTiers)
+	l.now = func() time.Time { return now }
+
+	c.Assert(l.Allow(1, PollCreation, 0), qt.IsNil)
+	now = now.Add(10 * time.Minute)
+	c.Assert(l.Allow(1, PollCreation, 0), qt.IsNil)
+	// the third poll in the window exceeds the quota
+	now = now.Add(10 * time.Minute)
+	err := l.Allow(1, PollCreation, 0)
+	var errExceeded *ExceededError
+	c.Assert(errors.As(err, &errExceeded), qt.IsTrue)
+	c.Assert(errExceeded.Action, qt.Equals, PollCreation)
+	c.Assert(errExceeded.RetryAfter, qt.Equals, 40*time.Minute)
+	// other users and actions without limits are not affected
+	c.Assert(l.Allow(2, PollCreation, 0), qt.IsNil)
+	c.Assert(l.Allow(1, CensusCreation, 0), qt.IsNil)
+	// once the oldest poll leaves the window, a new one is allowed
+	now = now.Add(40 * time.Minute)
+	c.Assert(l.Allow(1, PollCreation, 0), qt.IsNil)
+	c.Assert(l.Allow(1, PollCreation, 0), qt.IsNotNil)
+}
+
+func TestLimiterTiers(t *testing.T) {
+	c := qt.New(t)
+	l := NewLimiter(map[Action]Limit{
+		CensusCreation: {Max: 1, Window: time.Hour},
+	}, []Tier{
+		{MinReputation: 50, Multiplier: 3},
+		{MinReputation: 0, Multiplier: 1},
+	})
+	c.Assert(l.Max(CensusCreation, 0), qt.Equals, 1)
+	c.Assert(l.Max(CensusCreation, 49), qt.Equals, 1)
+	c.Assert(l.Max(CensusCreation, 50), qt.Equals, 3)
+	c.Assert(l.Max(PollCreation, 50), qt.Equals, 0)
+
+	for i := 0; i < 3; i++ {
+		c.Assert(l.Allow(1, CensusCreation, 60), qt.IsNil)
+	}
+	c.Assert(l.Allow(1, CensusCreation, 60), qt.IsNotNil)
+	// a user with lower reputation is limited earlier
+	c.Assert(l.Allow(2, CensusCreation, 10), qt.IsNil)
+	c.Assert(l.Allow(2, CensusCreation, 10), qt.IsNotNil)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+
+	"github.com/vocdoni/vote-frame/quota"
+	"go.vocdoni.io/dvote/httprouter"
+	"go.vocdoni.io/dvote/log"
+)
+
+// allowAction checks if the user with the FID provided can do the action
+// provided based on its quota, which is scaled by the user reputation. If the
+// action is allowed, it is registered. If the quota is exceeded it returns a
+// *quota.ExceededError. If the handler has no quotas, every action is allowed.
+func (v *vocdoniHandler) allowAction(userFID uint64, action quota.Action) error {
+	if v.quotas == nil || userFID == 0 {
+		return nil
+	}
+	var reputation uint32
+	if accessProfile, err := v.db.UserAccessProfile(userFID); err != nil {
+		log.Warnw("failed to get user access profile", "fid", userFID, "error", err)
+	} else if accessProfile != nil {
+		reputation = accessProfile.Reputation
+	}
+	return v.quotas.Allow(userFID, action, reputation)
+}
+
+// checkQuota checks the quota of the action provided for the user with the
+// FID provided, and if it is exceeded, it sends a 429 response with the
+// Retry-After header (in seconds) and returns true. Otherwise, it returns
+// false and the request can be handled.
+func (v *vocdoniHandler) checkQuota(ctx *httprouter.HTTPContext, userFID uint64, action quota.Action) (bool, error) {
+	err := v.allowAction(userFID, action)
+	if err == nil {
+		return false, nil
+	}
+	var errExceeded *quota.ExceededError
+	if !errors.As(err, &errExceeded) {
+		return false, err
+	}
+	retryAfter := int(math.Ceil(errExceeded.RetryAfter.Seconds()))
+	ctx.SetHeader("Retry-After", fmt.Sprint(retryAfter))
+	return true, ctx.Send([]byte(fmt.Sprintf("too many requests, retry after %d seconds", retryAfter)),
+		http.StatusTooManyRequests)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/vocdoni/vote-frame/helpers"
+	"github.com/vocdoni/vote-frame/imageframe"
+	"go.vocdoni.io/dvote/api"
+	"go.vocdoni.io/dvote/apiclient"
+	"go.vocdoni.io/dvote/httprouter"
+	"go.vocdoni.io/dvote/httprouter/apirest"
+	"go.vocdoni.io/dvote/types"
+)
+
+// nullifierSize is the size of the vote nullifiers, which are keccak256 hashes.
+const nullifierSize = 32
+
+var (
+	// ErrInvalidElectionID is returned when the election ID of a vote receipt
+	// is not a valid election ID.
+	ErrInvalidElectionID = fmt.Errorf("invalid electionID")
+	// ErrInvalidNullifier is returned when the nullifier of a vote receipt is
+	// not a valid nullifier.
+	ErrInvalidNullifier = fmt.Errorf("invalid nullifier")
+	// ErrVoteNotIncluded is returned when the vote of a receipt is not
+	// included in the vochain.
+	ErrVoteNotIncluded = fmt.Errorf("vote not found")
+)
+
+// voteReceiptHandler returns the receipt of the vote of the election and the
+// nullifier requested.
+func (v *vocdoniHandler) voteReceiptHandler(_ *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	receipt, err := v.voteReceipt(ctx.URLParam("electionID"), ctx.URLParam("nullifier"))
+	if err != nil {
+		if status := voteReceiptErrorStatus(err); status != http.StatusInternalServerError {
+			return ctx.Send([]byte(err.Error()), status)
+		}
+		return err
+	}
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal receipt: %w", err)
+	}
+	ctx.SetResponseContentType("application/json")
+	return ctx.Send(data, http.StatusOK)
+}
+
+// voteReceipt returns the receipt of the vote of the hex encoded election ID
+// and nullifier provided. It confirms that the vote is included in the
+// vochain, with the same request used to check if a voter already voted, and
+// returns the block and the transaction that include it, its weight and the
+// link to a shareable image of the receipt, which is created when it is
+// requested.
+func (v *vocdoniHandler) voteReceipt(electionIDHex, nullifierHex string) (*VoteReceipt, error) {
+	electionID, err := hex.DecodeString(electionIDHex)
+	if err != nil || len(electionID) != types.ProcessIDsize {
+		return nil, ErrInvalidElectionID
+	}
+	nullifier, err := hex.DecodeString(nullifierHex)
+	if err != nil || len(nullifier) != nullifierSize {
+		return nil, ErrInvalidNullifier
+	}
+	vote, err := v.includedVote(electionID, nullifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify vote: %w", err)
+	}
+	if vote == nil {
+		return nil, ErrVoteNotIncluded
+	}
+	return &VoteReceipt{
+		ElectionID:  fmt.Sprintf("%x", electionID),
+		Nullifier:   fmt.Sprintf("%x", nullifier),
+		BlockHeight: vote.BlockHeight,
+		TxHash:      vote.TxHash.String(),
+		Weight:      vote.VoteWeight,
+		Date:        vote.Date,
+		Image:       imageLink(imageframe.ReceiptImageID(electionID, nullifier)),
+		ExplorerURL: fmt.Sprintf("%s/verify/#/%x", explorerURL, nullifier),
+	}, nil
+}
+
+// voteReceiptErrorStatus returns the HTTP status of the vote receipt error
+// provided.
+func voteReceiptErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrInvalidElectionID), errors.Is(err, ErrInvalidNullifier):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrVoteNotIncluded):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// receiptImage returns the image with the receipt of the vote of the election
+// and the nullifier provided. If it is not cached, it is created again from
+// the vote included in the vochain, so the links to the receipt images keep
+// working after they are evicted from the cache or the server restarts.
+func (v *vocdoniHandler) receiptImage(electionID, nullifier types.HexBytes) ([]byte, error) {
+	return imageframe.ReceiptImage(electionID, nullifier, func() ([]string, error) {
+		vote, err := v.includedVote(electionID, nullifier)
+		if err != nil {
+			return nil, fmt.Errorf("failed to verify vote: %w", err)
+		}
+		if vote == nil {
+			return nil, ErrVoteNotIncluded
+		}
+		// render the receipt image with the question of the election
+		question := ""
+		if election, err := v.election(electionID); err == nil {
+			metadata := helpers.UnpackMetadata(election.Metadata)
+			if len(metadata.Questions) > 0 {
+				question = metadata.Questions[0].Title["default"]
+			}
+		}
+		return []string{
+			question,
+			fmt.Sprintf("Included in block %d", vote.BlockHeight),
+			fmt.Sprintf("Transaction %x...", shortHash(vote.TxHash)),
+			fmt.Sprintf("Weight %s", vote.VoteWeight),
+			fmt.Sprintf("Nullifier %x...", shortHash(nullifier)),
+		}, nil
+	})
+}
+
+// includedVote returns the vote of the election and the nullifier provided if
+// it is included in the vochain, or nil if it is not.
+func (v *vocdoniHandler) includedVote(electionID, nullifier types.HexBytes) (*api.Vote, error) {
+	included, err := voteIncluded(v.cli, electionID, nullifier)
+	if err != nil || !included {
+		return nil, err
+	}
+	return voteByNullifier(v.cli, nullifier)
+}
+
+// voteByNullifier returns the vote of the nullifier provided from the vochain.
+func voteByNullifier(cli *apiclient.HTTPclient, nullifier types.HexBytes) (*api.Vote, error) {
+	resp, code, err := cli.Request("GET", nil, "votes", nullifier.String())
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp)
+	}
+	vote := &api.Vote{}
+	if err := json.Unmarshal(resp, vote); err != nil {
+		return nil, fmt.Errorf("failed to decode vote: %w", err)
+	}
+	return vote, nil
+}
+
+// voteIncluded returns if the vote of the election and the nullifier provided
+// is included in the vochain.
+func voteIncluded(cli *apiclient.HTTPclient, electionID, nullifier types.HexBytes) (bool, error) {
+	_, code, err := cli.Request("GET", nil, "votes", "verify", electionID.String(), nullifier.String())
+	if err != nil {
+		return false, err
+	}
+	return code == http.StatusOK, nil
+}
+
+// shortHash returns the first bytes of the hash provided, to be displayed in
+// the images.
+func shortHash(hash []byte) []byte {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/imageframe"
+	"go.vocdoni.io/dvote/api"
+	"go.vocdoni.io/dvote/apiclient"
+	"go.vocdoni.io/dvote/types"
+)
+
+// testReceiptAPI returns a vochain API server, served under /v2 like the
+// public ones, that only includes the vote of the nullifier provided.
+func testReceiptAPI(c *qt.C, electionID, nullifier types.HexBytes) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf("/v2/votes/verify/%x/%x", electionID, nullifier):
+			w.WriteHeader(http.StatusOK)
+		case fmt.Sprintf("/v2/votes/%x", nullifier):
+			data, err := json.Marshal(&api.Vote{
+				TxHash:      types.HexBytes{0x0a, 0x0b},
+				VoteWeight:  "5",
+				BlockHeight: 100,
+			})
+			c.Assert(err, qt.IsNil)
+			_, _ = w.Write(data)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	c.Cleanup(srv.Close)
+	return srv
+}
+
+func TestVoteReceipt(t *testing.T) {
+	c := qt.New(t)
+
+	electionID := types.HexBytes(strings.Repeat("\x01", types.ProcessIDsize))
+	nullifier := types.HexBytes(strings.Repeat("\x02", nullifierSize))
+	cli, err := apiclient.New(testReceiptAPI(c, electionID, nullifier).URL + "/v2")
+	c.Assert(err, qt.IsNil)
+	v := &vocdoniHandler{cli: cli}
+
+	// the election IDs and the nullifiers are validated
+	for _, tc := range []struct {
+		electionID, nullifier string
+		err                   error
+	}{
+		{"zz", nullifier.String(), ErrInvalidElectionID},
+		{"0102", nullifier.String(), ErrInvalidElectionID},
+		{electionID.String(), "zz", ErrInvalidNullifier},
+		{electionID.String(), "0102", ErrInvalidNullifier},
+	} {
+		_, err := v.voteReceipt(tc.electionID, tc.nullifier)
+		c.Assert(err, qt.ErrorIs, tc.err)
+		c.Assert(voteReceiptErrorStatus(err), qt.Equals, http.StatusBadRequest)
+	}
+
+	// the votes not included are not found
+	other := types.HexBytes(strings.Repeat("\x03", nullifierSize))
+	_, err = v.voteReceipt(electionID.String(), other.String())
+	c.Assert(err, qt.ErrorIs, ErrVoteNotIncluded)
+	c.Assert(voteReceiptErrorStatus(err), qt.Equals, http.StatusNotFound)
+	c.Assert(voteReceiptErrorStatus(fmt.Errorf("api down")), qt.Equals, http.StatusInternalServerError)
+
+	// the receipt of the included votes links to their receipt image
+	receipt, err := v.voteReceipt(electionID.String(), nullifier.String())
+	c.Assert(err, qt.IsNil)
+	c.Assert(receipt.BlockHeight, qt.Equals, uint32(100))
+	c.Assert(receipt.TxHash, qt.Equals, "0a0b")
+	c.Assert(receipt.Weight, qt.Equals, "5")
+	imageID := imageframe.ReceiptImageID(electionID, nullifier)
+	c.Assert(receipt.Image, qt.Equals, imageLink(imageID))
+	parsedElectionID, parsedNullifier, ok := imageframe.ParseReceiptImageID(imageID)
+	c.Assert(ok, qt.IsTrue)
+	c.Assert(parsedElectionID, qt.DeepEquals, electionID)
+	c.Assert(parsedNullifier, qt.DeepEquals, nullifier)
+}
@@ -4,14 +4,17 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/vocdoni/vote-frame/farcasterapi/warpcast"
+	"github.com/vocdoni/vote-frame/features"
 	"github.com/vocdoni/vote-frame/mongo"
 	"go.vocdoni.io/dvote/httprouter"
 	"go.vocdoni.io/dvote/httprouter/apirest"
 	"go.vocdoni.io/dvote/log"
+	"go.vocdoni.io/dvote/types"
 	"go.vocdoni.io/dvote/util"
 )
 
@@ -32,21 +35,6 @@ func (v *vocdoniHandler) sendRemindersHandler(msg *apirest.APIdata, ctx *httprou
 	if err != nil {
 		return ctx.Send([]byte(err.Error()), apirest.HTTPstatusNotFound)
 	}
-	// get access profile to use the warpcast api key of the current user
-	accessProfile, err := v.db.UserAccessProfile(auth.UserID)
-	if err != nil {
-		return ctx.Send([]byte(err.Error()), http.StatusInternalServerError)
-	}
-	// check if the user has a configured warpcast api key
-	if accessProfile == nil || accessProfile.WarpcastAPIKey == "" {
-		return ctx.Send([]byte("no warpcast api key configured"), http.StatusBadRequest)
-	}
-	// init warpcast client to send the reminders with the user warpcast api key
-	warpcastClient := warpcast.NewWarpcastAPI()
-	if err := warpcastClient.SetFarcasterUser(auth.UserID, accessProfile.WarpcastAPIKey); err != nil {
-		log.Warnw("failed to initialize warpcast client", "error", err)
-		return ctx.Send([]byte("failed to initialize warpcast client: "+err.Error()), http.StatusInternalServerError)
-	}
 	// get the election id from the url params
 	electionID, err := hex.DecodeString(ctx.URLParam("electionID"))
 	if err != nil {
@@ -60,12 +48,18 @@ func (v *vocdoniHandler) sendRemindersHandler(msg *apirest.APIdata, ctx *httprou
 		}
 		return fmt.Errorf("failed to get election: %w", err)
 	}
-	// check if the election is a community election and if the user is an admin
-	if election.Community == nil || election.Community.ID == "" {
-		return fmt.Errorf("election is not a community election")
-	}
-	if !v.db.IsCommunityAdmin(auth.UserID, election.Community.ID) && auth.UserID != v.adminFID {
-		return ctx.Send([]byte("user is not an admin of the community"), http.StatusForbidden)
+	// check if the user can send reminders for the election
+	accessProfile, err := v.remindersAllowed(auth.UserID, election)
+	if err != nil {
+		switch {
+		case errors.Is(err, errNotCommunityAdmin), errors.Is(err, errRemindersReputation):
+			return ctx.Send([]byte(err.Error()), http.StatusForbidden)
+		case errors.Is(err, errNoWarpcastAPIKey):
+			return ctx.Send([]byte(err.Error()), http.StatusBadRequest)
+		case errors.Is(err, errNotCommunityElection):
+			return err
+		}
+		return ctx.Send([]byte(err.Error()), http.StatusInternalServerError)
 	}
 	// decode the reminders request from the body, there are two types of
 	// reminders, one for ranked list of n users by weight and another for
@@ -109,20 +103,94 @@ func (v *vocdoniHandler) sendRemindersHandler(msg *apirest.APIdata, ctx *httprou
 	if err != nil {
 		return fmt.Errorf("failed to get voters of election: %w", err)
 	}
-	maxDMs := v.MaxDirectMessages(auth.UserID, maxDirectMessages)
+	if err := v.remindersLimitAllowed(auth.UserID, remindableUsers, alreadySent); err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusBadRequest)
+	}
+	// send the reminders to the users in background
+	taskID, err := v.queueReminders(auth.UserID, accessProfile.WarpcastAPIKey, electionID,
+		usersToRemind, remindableUsers, req.Content)
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), http.StatusInternalServerError)
+	}
+	res, err := json.Marshal(&ReminderResponse{
+		QueueID: taskID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminders response: %w", err)
+	}
+	return ctx.Send(res, http.StatusOK)
+}
+
+var (
+	errNotCommunityElection = fmt.Errorf("election is not a community election")
+	errNotCommunityAdmin    = fmt.Errorf("user is not an admin of the community")
+	errRemindersReputation  = fmt.Errorf("user does not have enough reputation to send reminders")
+	errNoWarpcastAPIKey     = fmt.Errorf("no warpcast api key configured")
+)
+
+// remindersAllowed checks if the user with the FID provided can send
+// reminders for the election provided. The election must be a community
+// election, the user must be an admin of the community with enough reputation
+// (or the service admin) and must have a warpcast api key configured to send
+// the reminders. It returns the access profile of the user to use their
+// warpcast api key.
+func (v *vocdoniHandler) remindersAllowed(userFID uint64, election *mongo.Election) (*mongo.UserAccessProfile, error) {
+	// get access profile to use the warpcast api key of the current user
+	accessProfile, err := v.db.UserAccessProfile(userFID)
+	if err != nil {
+		return nil, err
+	}
+	// check if the user has a configured warpcast api key
+	if accessProfile == nil || accessProfile.WarpcastAPIKey == "" {
+		return nil, errNoWarpcastAPIKey
+	}
+	// check if the election is a community election and if the user is an admin
+	if election.Community == nil || election.Community.ID == "" {
+		return nil, errNotCommunityElection
+	}
+	if !v.db.IsCommunityAdmin(userFID, election.Community.ID) && userFID != v.adminFID {
+		return nil, errNotCommunityAdmin
+	}
+	// check if the user has enough reputation to send reminders
+	if userFID != v.adminFID &&
+		!features.IsAllowedInCommunity(features.SEND_REMINDERS, election.Community.ID, accessProfile.Reputation) {
+		return nil, errRemindersReputation
+	}
+	return accessProfile, nil
+}
+
+// remindersLimitAllowed checks if the user with the FID provided can send
+// reminders to the remindable users provided, having already sent the number
+// of reminders provided, based on the maximum number of direct messages
+// allowed by their reputation.
+func (v *vocdoniHandler) remindersLimitAllowed(userFID uint64, remindableUsers map[uint64]string, alreadySent uint64) error {
+	maxDMs := v.MaxDirectMessages(userFID, maxDirectMessages)
 	if uint64(len(remindableUsers)) > maxDMs {
-		msg := fmt.Sprintf("too many users to remind, by your reputation you only can sent %d reminds", maxDMs)
-		return ctx.Send([]byte(msg), http.StatusBadRequest)
+		return fmt.Errorf("too many users to remind, by your reputation you only can sent %d reminds", maxDMs)
 	}
-	if uint64(alreadySent) >= maxDMs {
-		msg := fmt.Sprintf("you have already sent the maximum number of reminders (%d)", maxDMs)
-		return ctx.Send([]byte(msg), http.StatusBadRequest)
+	if alreadySent >= maxDMs {
+		return fmt.Errorf("you have already sent the maximum number of reminders (%d)", maxDMs)
+	}
+	return nil
+}
+
+// queueReminders sends in background the reminder with the content provided
+// to the users provided that are remindable, from the user with the FID and
+// the warpcast api key provided. The status of the task is stored in the
+// background queue and the ID of the task is returned.
+func (v *vocdoniHandler) queueReminders(fromFID uint64, warpcastAPIKey string, electionID types.HexBytes,
+	usersToRemind, remindableUsers map[uint64]string, content string,
+) (string, error) {
+	// init warpcast client to send the reminders with the user warpcast api key
+	warpcastClient := warpcast.NewWarpcastAPI()
+	if err := warpcastClient.SetFarcasterUser(fromFID, warpcastAPIKey); err != nil {
+		log.Warnw("failed to initialize warpcast client", "error", err)
+		return "", fmt.Errorf("failed to initialize warpcast client: %w", err)
 	}
-	// send the reminders to the users in background
 	taskID := util.RandomHex(16)
 	v.backgroundQueue.Store(taskID, RemindersStatus{
 		Total:      len(usersToRemind),
-		ElectionID: election.ElectionID,
+		ElectionID: electionID.String(),
 	})
 	go func() {
 		ctx, cancel := context.WithCancel(context.Background())
@@ -140,10 +208,10 @@ func (v *vocdoniHandler) sendRemindersHandler(msg *apirest.APIdata, ctx *httprou
 			}
 			// send the reminder to the user
 			log.Debugw("sending direct message reminder",
-				"content", string(req.Content),
+				"content", content,
 				"to", fid,
-				"from", auth.UserID)
-			if err := warpcastClient.DirectMessage(ctx, req.Content, fid); err != nil {
+				"from", fromFID)
+			if err := warpcastClient.DirectMessage(ctx, content, fid); err != nil {
 				log.Warnw("failed to send direct notification", "error", err, "fid", fid, "username", username)
 				currentStatus.Fails[username] = err.Error()
 				v.backgroundQueue.Store(taskID, currentStatus)
@@ -162,13 +230,7 @@ func (v *vocdoniHandler) sendRemindersHandler(msg *apirest.APIdata, ctx *httprou
 		currentStatus.Completed = true
 		v.backgroundQueue.Store(taskID, currentStatus)
 	}()
-	res, err := json.Marshal(&ReminderResponse{
-		QueueID: taskID,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to marshal reminders response: %w", err)
-	}
-	return ctx.Send(res, http.StatusOK)
+	return taskID, nil
 }
 
 func (v *vocdoniHandler) remindersQueueHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
@@ -0,0 +1,149 @@
+package reputation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// BoosterTypeERC20 is the type for a booster that is granted to the
+	// holders of an ERC20 token.
+	BoosterTypeERC20 = "erc20"
+	// BoosterTypeNFT is the type for a booster that is granted to the holders
+	// of an NFT.
+	BoosterTypeNFT = "nft"
+	// BoosterTypeFarcasterFollower is the type for a booster that is granted
+	// to the followers of a Farcaster profile.
+	BoosterTypeFarcasterFollower = "farcasterFollower"
+	// BoosterTypeAlfafrensFollower is the type for a booster that is granted
+	// to the subscribers of an Alfafrens channel.
+	BoosterTypeAlfafrensFollower = "alfafrensFollower"
+	// BoosterTypeRecaster is the type for a booster that is granted to the
+	// users that have recasted a Farcaster cast.
+	BoosterTypeRecaster = "recaster"
+)
+
+// Booster struct defines a reputation booster. It includes the booster
+// identifier, which is also the key of the booster in the user reputation, a
+// human readable name, the type of the booster and the points that the user
+// gets if they have it. Depending on the type, it also includes the token
+// address and chain ID, the minimum balance required, the Farcaster profile to
+// follow or the cast to recast.
+type Booster struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Points     uint64 `json:"points"`
+	Address    string `json:"address,omitempty"`
+	ChainID    uint64 `json:"chainID,omitempty"`
+	MinBalance uint64 `json:"minBalance,omitempty"`
+	// CheckBalance indicates that the holders of the token must not be cached
+	// because they are too many, instead, the balance of every user address is
+	// checked every time.
+	CheckBalance bool   `json:"checkBalance,omitempty"`
+	FID          uint64 `json:"fid,omitempty"`
+	CastHash     string `json:"castHash,omitempty"`
+}
+
+// IsToken returns true if the booster is granted to the holders of a token.
+func (b *Booster) IsToken() bool {
+	return b.Type == BoosterTypeERC20 || b.Type == BoosterTypeNFT
+}
+
+// TokenAddress returns the address of the token of the booster.
+func (b *Booster) TokenAddress() common.Address {
+	return common.HexToAddress(b.Address)
+}
+
+// HasEnoughBalance returns true if the balance provided is enough to get the
+// booster. If no minimum balance is defined, any balance is enough.
+func (b *Booster) HasEnoughBalance(balance *big.Int) bool {
+	if balance == nil {
+		return false
+	}
+	return balance.Cmp(new(big.Int).SetUint64(b.MinBalance)) >= 0
+}
+
+// validate checks that the booster has all the required fields based on its
+// type.
+func (b *Booster) validate() error {
+	if b.ID == "" {
+		return fmt.Errorf("booster id is required")
+	}
+	switch b.Type {
+	case BoosterTypeERC20, BoosterTypeNFT:
+		if !common.IsHexAddress(b.Address) || b.ChainID == 0 {
+			return fmt.Errorf("booster %s requires a valid token address and chain id", b.ID)
+		}
+	case BoosterTypeAlfafrensFollower:
+		if !common.IsHexAddress(b.Address) {
+			return fmt.Errorf("booster %s requires a valid channel address", b.ID)
+		}
+	case BoosterTypeFarcasterFollower:
+		if b.FID == 0 {
+			return fmt.Errorf("booster %s requires a fid", b.ID)
+		}
+	case BoosterTypeRecaster:
+		if b.FID == 0 || b.CastHash == "" {
+			return fmt.Errorf("booster %s requires a fid and a cast hash", b.ID)
+		}
+	default:
+		return fmt.Errorf("booster %s has an unknown type '%s'", b.ID, b.Type)
+	}
+	return nil
+}
+
+// BoostersRegistry struct contains the list of boosters supported by the
+// reputation system. The order of the boosters is the order in which they
+// appear in the configuration file.
+type BoostersRegistry struct {
+	Boosters []*Booster `json:"boosters"`
+}
+
+// LoadBoostersRegistry loads the boosters registry from the JSON file at the
+// given path. It returns an error if the file cannot be read, the JSON cannot
+// be parsed or some booster is not valid.
+func LoadBoostersRegistry(path string) (*BoostersRegistry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	registry := &BoostersRegistry{}
+	if err := json.Unmarshal(data, registry); err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{}
+	for _, booster := range registry.Boosters {
+		if err := booster.validate(); err != nil {
+			return nil, err
+		}
+		if ids[booster.ID] {
+			return nil, fmt.Errorf("duplicated booster id '%s'", booster.ID)
+		}
+		ids[booster.ID] = true
+	}
+	return registry, nil
+}
+
+// Booster returns the booster with the given id or nil if it does not exist.
+func (r *BoostersRegistry) Booster(id string) *Booster {
+	for _, booster := range r.Boosters {
+		if booster.ID == id {
+			return booster
+		}
+	}
+	return nil
+}
+
+// Info returns the puntuaction values for each booster of the registry.
+func (r *BoostersRegistry) Info() ReputationInfo {
+	info := ReputationInfo{}
+	for _, booster := range r.Boosters {
+		info[booster.ID] = booster.Points
+	}
+	return info
+}
@@ -0,0 +1,59 @@
+package reputation
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestLoadBoostersRegistry(t *testing.T) {
+	c := qt.New(t)
+	// the default config file must be valid
+	registry, err := LoadBoostersRegistry("../boosters_config.json")
+	c.Assert(err, qt.IsNil)
+	c.Assert(len(registry.Boosters) > 0, qt.IsTrue)
+	c.Assert(registry.Booster("hasKIWI"), qt.IsNotNil)
+	c.Assert(registry.Booster("unknown"), qt.IsNil)
+	// duplicated ids are not allowed
+	path := filepath.Join(t.TempDir(), "boosters.json")
+	c.Assert(os.WriteFile(path, []byte(`{"boosters": [
+		{"id": "a", "type": "farcasterFollower", "fid": 1, "points": 1},
+		{"id": "a", "type": "farcasterFollower", "fid": 2, "points": 1}
+	]}`), 0o600), qt.IsNil)
+	_, err = LoadBoostersRegistry(path)
+	c.Assert(err, qt.IsNotNil)
+	// token boosters require a valid address
+	c.Assert(os.WriteFile(path, []byte(`{"boosters": [
+		{"id": "a", "type": "erc20", "address": "0x1", "chainID": 1, "points": 1}
+	]}`), 0o600), qt.IsNil)
+	_, err = LoadBoostersRegistry(path)
+	c.Assert(err, qt.IsNotNil)
+}
+
+func TestBoostersReputation(t *testing.T) {
+	c := qt.New(t)
+	registry := &BoostersRegistry{Boosters: []*Booster{
+		{ID: "a", Points: 10},
+		{ID: "b", Points: 20},
+		{ID: "c", Points: 90},
+	}}
+	c.Assert(boostersReputation(registry, Boosters{}), qt.Equals, uint64(0))
+	c.Assert(boostersReputation(registry, Boosters{"a": true, "b": false}), qt.Equals, uint64(10))
+	c.Assert(boostersReputation(registry, Boosters{"a": true, "b": true}), qt.Equals, uint64(30))
+	// unknown boosters are ignored
+	c.Assert(boostersReputation(registry, Boosters{"a": true, "d": true}), qt.Equals, uint64(10))
+	// the reputation is capped
+	c.Assert(boostersReputation(registry, Boosters{"a": true, "c": true}), qt.Equals, uint64(maxReputation))
+}
+
+func TestBoosterHasEnoughBalance(t *testing.T) {
+	c := qt.New(t)
+	b := &Booster{MinBalance: 5}
+	c.Assert(b.HasEnoughBalance(nil), qt.IsFalse)
+	c.Assert(b.HasEnoughBalance(big.NewInt(4)), qt.IsFalse)
+	c.Assert(b.HasEnoughBalance(big.NewInt(5)), qt.IsTrue)
+	c.Assert((&Booster{}).HasEnoughBalance(big.NewInt(1)), qt.IsTrue)
+}
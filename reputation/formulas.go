@@ -2,14 +2,15 @@ package reputation
 
 import (
 	"math"
+	"time"
 
 	"github.com/vocdoni/vote-frame/mongo"
 )
 
 // totalReputation calculates the reputation of a user based on their activity
 // and boosters and returns the mean value of both.
-func totalReputation(ar *ActivityReputationCounts, b *Boosters) uint64 {
-	return (activityReputation(ar) + boostersReputation(b)) / 2
+func totalReputation(ar *ActivityReputationCounts, registry *BoostersRegistry, b Boosters) uint64 {
+	return (activityReputation(ar) + boostersReputation(registry, b)) / 2
 }
 
 // activityReputation calculates the reputation of a user based on their
@@ -61,100 +62,59 @@ func ponderateActivityReputation(ar *ActivityReputationCounts) *ActivityReputati
 	return p
 }
 
+// decayFactor returns the weight of an activity done 'age' time ago following
+// an exponential decay with the given half-life, so the weight of the recent
+// activity is 1 and it halves every half-life. If the half-life is not
+// positive, the activity does not decay.
+func decayFactor(age, halfLife time.Duration) float64 {
+	if halfLife <= 0 || age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// decayedActivityCounts calculates the time-weighted activity counts of a user
+// based on their timestamped activities. Every activity is weighted by its
+// decay factor at the time provided, so the recent elections created, votes
+// cast and participations on the user elections count more than the old ones.
+// The followers and communities counts are not included because they are not
+// based on timestamped activities.
+func decayedActivityCounts(userID uint64, activities []*mongo.Activity, halfLife time.Duration, now time.Time) *ActivityReputationCounts {
+	var elections, votes, participations float64
+	for _, activity := range activities {
+		factor := decayFactor(now.Sub(activity.CreatedAt), halfLife)
+		switch activity.Type {
+		case mongo.ActivityTypeElection:
+			if activity.UserID == userID {
+				elections += factor
+			}
+		case mongo.ActivityTypeVote:
+			if activity.UserID == userID {
+				votes += factor
+			}
+			if activity.ElectionOwnerID == userID {
+				participations += factor * float64(activity.Weight)
+			}
+		}
+	}
+	return &ActivityReputationCounts{
+		ElectionsCreatedCount: uint64(math.Round(elections)),
+		CastVotesCount:        uint64(math.Round(votes)),
+		ParticipationsCount:   uint64(math.Round(participations)),
+	}
+}
+
 // boostersReputation calculates the reputation of a user based on their boosters,
-// it returns a value between 0 and 100. The reputation is calculated based on
-// the following boosters:
-//   - VotecasterNFTPass: 'votecasterNFTPassPuntuaction' points
-//   - VotecasterLaunchNFT: 'votecasterLaunchNFTPuntuaction' points
-//   - VotecasterAlphafrensFollower: 'votecasterAlphafrensFollowerPuntuaction' points
-//   - VotecasterFarcasterFollower: 'votecasterFarcasterFollowerPuntuaction' points
-//   - VocdoniFarcasterFollower: 'vocdoniFarcasterFollowerPuntuaction' points
-//   - VotecasterAnnouncementRecasted: 'votecasterAnnouncementRecastedPuntuaction' points
-//   - KIWI: 'kiwiPuntuaction' points
-//   - DegenDAO NFT: 'degenDAONFTPuntuaction' points
-//   - Haberdashery NFT: 'haberdasheryFTPuntuaction' points
-//   - >=10k Degen: 'degenAtLeast10kPuntuaction' points
-//   - TokyoDAO NFT: 'tokyoDAONFTPuntuaction' points
-//   - ProxyStudio NFT: 'proxyStudioNFTPuntuaction' points
-//   - >=5 Proxy: 'proxyAtLeast5Puntuaction' points
-//   - NameDegen: 'nameDegenPuntuaction' points
-//   - FarcasterOG NFT: 'farcasterOGNFTPuntuaction' points
-//
-// If the reputation exceeds 100, it is capped at 100.
-func boostersReputation(rep *Boosters) uint64 {
+// it returns a value between 0 and 100. The reputation is the sum of the
+// points of every booster of the registry that the user has. Boosters that are
+// not in the registry are ignored. If the reputation exceeds 100, it is capped
+// at 100.
+func boostersReputation(registry *BoostersRegistry, rep Boosters) uint64 {
 	reputation := uint64(0)
-	// add votecaster nft pass puntuaction if user has it
-	if rep.HasVotecasterNFTPass {
-		reputation += votecasterNFTPassPuntuaction
-	}
-	// add votecaster launch nft puntuaction if user has it
-	if rep.HasVotecasterLaunchNFT {
-		reputation += votecasterLaunchNFTPuntuaction
-	}
-	// add votecaster alphafrens follower puntuaction if user is subscribed
-	// to votecaster alphafrens
-	if rep.IsVotecasterAlphafrensFollower {
-		reputation += votecasterAlphafrensFollowerPuntuaction
-	}
-	// add votecaster farcaster follower puntuaction if user follows votecaster
-	// farcaster profile
-	if rep.IsVotecasterFarcasterFollower {
-		reputation += votecasterFarcasterFollowerPuntuaction
-	}
-	// add vocdoni farcaster follower puntuaction if user follows vocdoni
-	// farcaster profile
-	if rep.IsVocdoniFarcasterFollower {
-		reputation += vocdoniFarcasterFollowerPuntuaction
-	}
-	// add votecaster announcement recasted puntuaction if user recasted the
-	// votecaster launch announcement cast
-	if rep.VotecasterAnnouncementRecasted {
-		reputation += votecasterAnnouncementRecastedPuntuaction
-	}
-	// add kiwi puntuaction if user has kiwi
-	// (oeth:0x66747bdC903d17C586fA09eE5D6b54CC85bBEA45)
-	if rep.HasKIWI {
-		reputation += kiwiPuntuaction
-	}
-	// add degen dao nft puntuaction if user has degen dao nft
-	// (base:0x980Fbdd1cF05080781Dca0AEf7026B0406743389)
-	if rep.HasDegenDAONFT {
-		reputation += degenDAONFTPuntuaction
-	}
-	// add haberdashery nft puntuaction if user has haberdashery nft
-	// (base:0x85E7DF5708902bE39891d59aBEf8E21EDE91E8BF)
-	if rep.HasHaberdasheryNFT {
-		reputation += haberdasheryNFTPuntuaction
-	}
-	// add degen at least 10k puntuaction if user has at least 10k degen
-	// (base:0x4ed4E862860beD51a9570b96d89aF5E1B0Efefed)
-	if rep.Has10kDegenAtLeast {
-		reputation += degenAtLeast10kPuntuaction
-	}
-	// add tokyo dao nft puntuaction if user has tokyo dao nft
-	// (base:0x432073397Aead241cf2411e21D8fA949183E7151)
-	if rep.HasTokyoDAONFT {
-		reputation += tokyoDAONFTPuntuaction
-	}
-	// add 5 proxy at least puntuaction if user has at least 5 proxies
-	// (degen:0xA051A2Cb19C00eCDffaE94D0Ff98c17758041D16)
-	if rep.Has5ProxyAtLeast {
-		reputation += proxyAtLeast5Puntuaction
-	}
-	// add name degen puntuaction if user has name degen
-	// (degen:0x4087fb91A1fBdef05761C02714335D232a2Bf3a1)
-	if rep.HasNameDegen {
-		reputation += nameDegenPuntuaction
-	}
-	// add farcaster og nft puntuaction if user has farcaster og nft
-	// (base:0xe03ef4b9db1a47464de84fb476f9baf493b3e886)
-	if rep.HasFarcasterOGNFT {
-		reputation += farcasterOGNFTPuntuaction
-	}
-	// add moxie pass puntuaction if user has moxie pass
-	// (base:0x235CAD50d8a510Bc9081279996f01877827142D8)
-	if rep.HasMoxiePass {
-		reputation += moxiePassPuntuaction
+	for _, booster := range registry.Boosters {
+		if rep[booster.ID] {
+			reputation += booster.Points
+		}
 	}
 	// ensure the reputation does not exceed 100
 	if reputation > maxReputation {
@@ -0,0 +1,59 @@
+package reputation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/vocdoni/vote-frame/mongo"
+)
+
+func TestDecayFactor(t *testing.T) {
+	c := qt.New(t)
+	halfLife := 30 * 24 * time.Hour
+	c.Assert(decayFactor(0, halfLife), qt.Equals, float64(1))
+	c.Assert(decayFactor(halfLife, halfLife), qt.Equals, 0.5)
+	c.Assert(decayFactor(2*halfLife, halfLife), qt.Equals, 0.25)
+	// future activity and disabled decay do not decay
+	c.Assert(decayFactor(-time.Hour, halfLife), qt.Equals, float64(1))
+	c.Assert(decayFactor(10*halfLife, 0), qt.Equals, float64(1))
+	c.Assert(math.Abs(decayFactor(halfLife/2, halfLife)-math.Sqrt(0.5)) < 1e-9, qt.IsTrue)
+}
+
+func TestDecayedActivityCounts(t *testing.T) {
+	c := qt.New(t)
+	halfLife := 30 * 24 * time.Hour
+	now := time.Now()
+	activity := func(aType string, user, owner, weight uint64, age time.Duration) *mongo.Activity {
+		return &mongo.Activity{
+			Type:            aType,
+			UserID:          user,
+			ElectionOwnerID: owner,
+			Weight:          weight,
+			CreatedAt:       now.Add(-age),
+		}
+	}
+	activities := []*mongo.Activity{
+		// recent activity counts entirely
+		activity(mongo.ActivityTypeElection, 1, 1, 1, 0),
+		activity(mongo.ActivityTypeVote, 1, 2, 1, 0),
+		activity(mongo.ActivityTypeVote, 1, 2, 1, 0),
+		// old activity counts less
+		activity(mongo.ActivityTypeElection, 1, 1, 1, halfLife),
+		activity(mongo.ActivityTypeVote, 1, 2, 1, halfLife),
+		activity(mongo.ActivityTypeVote, 1, 2, 1, 2*halfLife),
+		// votes on the user elections count as participations
+		activity(mongo.ActivityTypeVote, 3, 1, 4, 0),
+		activity(mongo.ActivityTypeVote, 4, 1, 4, halfLife),
+	}
+	counts := decayedActivityCounts(1, activities, halfLife, now)
+	c.Assert(counts.ElectionsCreatedCount, qt.Equals, uint64(2)) // 1.5
+	c.Assert(counts.CastVotesCount, qt.Equals, uint64(3))        // 2.75
+	c.Assert(counts.ParticipationsCount, qt.Equals, uint64(6))   // 4 + 2
+	// old activity vanishes
+	counts = decayedActivityCounts(1, []*mongo.Activity{
+		activity(mongo.ActivityTypeVote, 1, 2, 1, 365*24*time.Hour),
+	}, halfLife, now)
+	c.Assert(counts.CastVotesCount, qt.Equals, uint64(0))
+}
@@ -0,0 +1,90 @@
+package reputation
+
+import (
+	"fmt"
+	"time"
+
+	dbmongo "github.com/vocdoni/vote-frame/mongo"
+	"go.vocdoni.io/dvote/log"
+)
+
+// UserReputationHistory method returns the reputation history of a user since
+// the given time. If limit is greater than zero, only the newest 'limit'
+// snapshots are included. It returns an error if the snapshots cannot be
+// fetched from the database.
+func (u *Updater) UserReputationHistory(userID uint64, since time.Time, limit int64) (*ReputationHistory, error) {
+	snapshots, err := u.db.UserReputationSnapshots(userID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching user reputation snapshots: %w", err)
+	}
+	return u.reputationHistory(snapshots), nil
+}
+
+// CommunityReputationHistory method returns the reputation history of a
+// community since the given time. If limit is greater than zero, only the
+// newest 'limit' snapshots are included. It returns an error if the snapshots
+// cannot be fetched from the database.
+func (u *Updater) CommunityReputationHistory(communityID string, since time.Time, limit int64) (*ReputationHistory, error) {
+	snapshots, err := u.db.CommunityReputationSnapshots(communityID, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching community reputation snapshots: %w", err)
+	}
+	return u.reputationHistory(snapshots), nil
+}
+
+// snapshotReputations method stores a snapshot of the current reputation of
+// every user and community in the database.
+func (u *Updater) snapshotReputations() error {
+	log.Info("taking reputations snapshot")
+	reputations, err := u.db.Reputations()
+	if err != nil {
+		return fmt.Errorf("error fetching reputations: %w", err)
+	}
+	if err := u.db.AddReputationSnapshots(reputations, time.Now()); err != nil {
+		return fmt.Errorf("error storing reputations snapshot: %w", err)
+	}
+	log.Infow("reputations snapshot stored", "total", len(reputations))
+	return nil
+}
+
+// reputationHistory method converts the database snapshots provided into a
+// ReputationHistory, calculating the breakdown of every snapshot and the delta
+// between the last two snapshots.
+func (u *Updater) reputationHistory(dbSnapshots []*dbmongo.ReputationSnapshot) *ReputationHistory {
+	history := &ReputationHistory{Snapshots: []*ReputationSnapshot{}}
+	for _, s := range dbSnapshots {
+		snapshot := &ReputationSnapshot{
+			Timestamp:       s.CreatedAt,
+			TotalReputation: s.TotalReputation,
+			TotalPoints:     s.TotalPoints,
+		}
+		if s.CommunityID != "" {
+			snapshot.Participation = s.Participation
+			snapshot.CensusSize = s.CensusSize
+		} else {
+			counts := &ActivityReputationCounts{
+				FollowersCount:        s.FollowersCount,
+				ElectionsCreatedCount: s.ElectionsCreatedCount,
+				CastVotesCount:        s.CastVotesCount,
+				ParticipationsCount:   s.ParticipationsCount,
+				CommunitiesCount:      s.CommunitiesCount,
+			}
+			snapshot.ActivityPoints = ponderateActivityReputation(counts)
+			snapshot.ActivityReputation = activityReputation(counts)
+			snapshot.Boosters = s.Boosters
+			snapshot.BoostersReputation = boostersReputation(u.boosters, s.Boosters)
+		}
+		history.Snapshots = append(history.Snapshots, snapshot)
+	}
+	if n := len(history.Snapshots); n > 1 {
+		last, prev := history.Snapshots[n-1], history.Snapshots[n-2]
+		history.Delta = &ReputationDelta{
+			Since:              prev.Timestamp,
+			TotalReputation:    int64(last.TotalReputation) - int64(prev.TotalReputation),
+			TotalPoints:        int64(last.TotalPoints) - int64(prev.TotalPoints),
+			ActivityReputation: int64(last.ActivityReputation) - int64(prev.ActivityReputation),
+			BoostersReputation: int64(last.BoostersReputation) - int64(prev.BoostersReputation),
+		}
+	}
+	return history
+}
@@ -0,0 +1,184 @@
+package reputation
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// ImprovementTypeBooster is the type of the improvements that consist of
+	// getting a missing booster.
+	ImprovementTypeBooster = "booster"
+	// ImprovementTypeActivity is the type of the improvements that consist of
+	// reaching the next threshold of an activity indicator.
+	ImprovementTypeActivity = "activity"
+)
+
+// ReputationImprovement struct contains an action that a user can do to
+// improve their reputation. It includes the type of the improvement, the
+// identifier of the booster or the activity indicator, a human readable
+// description of the action, the amount of activity required (only for
+// activity improvements), the points that the user would get in the booster
+// or activity component and the resulting gain in the total reputation.
+type ReputationImprovement struct {
+	Type           string `json:"type"`
+	ID             string `json:"id"`
+	Description    string `json:"description"`
+	Required       uint64 `json:"required,omitempty"`
+	PointsGain     uint64 `json:"pointsGain"`
+	ReputationGain uint64 `json:"reputationGain"`
+}
+
+// ReputationImprovements method calculates the list of actions that the user
+// provided can do to improve their reputation, based on their current
+// reputation stored in the database. The list is ranked by the gain in the
+// total reputation.
+func (u *Updater) ReputationImprovements(userID uint64) ([]*ReputationImprovement, error) {
+	rep, err := u.db.DetailedUserReputation(userID)
+	if err != nil {
+		return nil, err
+	}
+	counts := &ActivityReputationCounts{
+		FollowersCount:        rep.FollowersCount,
+		ElectionsCreatedCount: rep.ElectionsCreatedCount,
+		CastVotesCount:        rep.CastVotesCount,
+		ParticipationsCount:   rep.ParticipationsCount,
+		CommunitiesCount:      rep.CommunitiesCount,
+	}
+	return reputationImprovements(counts, u.boosters, rep.Boosters), nil
+}
+
+// reputationImprovements function calculates the marginal gain of every
+// missing booster of the registry and of reaching the next threshold of every
+// activity indicator that is not already at its max value. The improvements
+// without gain are discarded and the rest are sorted by the gain in the total
+// reputation, then by the gain in the component points and then by the
+// required activity.
+func reputationImprovements(counts *ActivityReputationCounts, registry *BoostersRegistry, boosters Boosters) []*ReputationImprovement {
+	currentActivity := activityReputation(counts)
+	currentBoosters := boostersReputation(registry, boosters)
+	current := totalReputation(counts, registry, boosters)
+	improvements := []*ReputationImprovement{}
+	// missing boosters
+	for _, booster := range registry.Boosters {
+		if boosters[booster.ID] {
+			continue
+		}
+		withBooster := Boosters{booster.ID: true}
+		for id, has := range boosters {
+			withBooster[id] = has
+		}
+		newBoosters := boostersReputation(registry, withBooster)
+		improvements = append(improvements, &ReputationImprovement{
+			Type:           ImprovementTypeBooster,
+			ID:             booster.ID,
+			Description:    boosterDescription(booster),
+			PointsGain:     newBoosters - currentBoosters,
+			ReputationGain: totalReputation(counts, registry, withBooster) - current,
+		})
+	}
+	// next activity thresholds
+	points := ponderateActivityReputation(counts)
+	thresholds := []struct {
+		id          string
+		description string
+		count       uint64
+		next        uint64
+		maxed       bool
+		apply       func(c *ActivityReputationCounts, required uint64)
+	}{
+		{
+			id:          "followers",
+			description: "Get %d more followers",
+			count:       counts.FollowersCount,
+			next:        (points.FollowersPoints + 1) * followersDividerPonderation,
+			maxed:       points.FollowersPoints >= maxFollowersReputation,
+			apply:       func(c *ActivityReputationCounts, r uint64) { c.FollowersCount += r },
+		},
+		{
+			id:          "createdElections",
+			description: "Create %d more polls",
+			count:       counts.ElectionsCreatedCount,
+			next:        (points.ElectionsCreatedPoints + 1) * electionsDividerPonderation,
+			maxed:       points.ElectionsCreatedPoints >= maxElectionsReputation,
+			apply:       func(c *ActivityReputationCounts, r uint64) { c.ElectionsCreatedCount += r },
+		},
+		{
+			id:          "castVotes",
+			description: "Cast %d more votes",
+			count:       counts.CastVotesCount,
+			next:        (points.CastVotesPoints + 1) * votesDividerPonderation,
+			maxed:       points.CastVotesPoints >= maxVotesReputation,
+			apply:       func(c *ActivityReputationCounts, r uint64) { c.CastVotesCount += r },
+		},
+		{
+			id:          "participations",
+			description: "Get %d more votes on the polls you created",
+			count:       counts.ParticipationsCount,
+			next:        (points.ParticipationsPoints + 1) * castedDividerPonderation,
+			maxed:       points.ParticipationsPoints >= maxCastedReputation,
+			apply:       func(c *ActivityReputationCounts, r uint64) { c.ParticipationsCount += r },
+		},
+		{
+			id:          "communities",
+			description: "Become admin of %d more communities",
+			count:       counts.CommunitiesCount,
+			next:        counts.CommunitiesCount + 1,
+			maxed:       points.CommunitiesPoints >= maxCommunityReputation,
+			apply:       func(c *ActivityReputationCounts, r uint64) { c.CommunitiesCount += r },
+		},
+	}
+	for _, t := range thresholds {
+		if t.maxed {
+			continue
+		}
+		required := t.next - t.count
+		newCounts := *counts
+		t.apply(&newCounts, required)
+		improvements = append(improvements, &ReputationImprovement{
+			Type:           ImprovementTypeActivity,
+			ID:             t.id,
+			Description:    fmt.Sprintf(t.description, required),
+			Required:       required,
+			PointsGain:     activityReputation(&newCounts) - currentActivity,
+			ReputationGain: totalReputation(&newCounts, registry, boosters) - current,
+		})
+	}
+	// discard the improvements without gain and rank the rest
+	ranked := []*ReputationImprovement{}
+	for _, improvement := range improvements {
+		if improvement.PointsGain > 0 {
+			ranked = append(ranked, improvement)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].ReputationGain != ranked[j].ReputationGain {
+			return ranked[i].ReputationGain > ranked[j].ReputationGain
+		}
+		if ranked[i].PointsGain != ranked[j].PointsGain {
+			return ranked[i].PointsGain > ranked[j].PointsGain
+		}
+		return ranked[i].Required < ranked[j].Required
+	})
+	return ranked
+}
+
+// boosterDescription returns a human readable description of the action
+// required to get the booster provided.
+func boosterDescription(b *Booster) string {
+	switch b.Type {
+	case BoosterTypeNFT:
+		return fmt.Sprintf("Hold the %s (%s on chain %d)", b.Name, b.Address, b.ChainID)
+	case BoosterTypeERC20:
+		return fmt.Sprintf("Hold at least %d tokens of %s (%s on chain %d)",
+			b.MinBalance, b.Name, b.Address, b.ChainID)
+	case BoosterTypeFarcasterFollower:
+		return fmt.Sprintf("Follow the Farcaster profile with FID %d (%s)", b.FID, b.Name)
+	case BoosterTypeAlfafrensFollower:
+		return fmt.Sprintf("Subscribe to the Alfafrens channel %s (%s)", b.Address, b.Name)
+	case BoosterTypeRecaster:
+		return fmt.Sprintf("Recast the cast %s (%s)", b.CastHash, b.Name)
+	default:
+		return b.Name
+	}
+}
@@ -0,0 +1,44 @@
+package reputation
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestReputationImprovements(t *testing.T) {
+	c := qt.New(t)
+	registry := &BoostersRegistry{Boosters: []*Booster{
+		{ID: "a", Type: BoosterTypeFarcasterFollower, FID: 1, Points: 10},
+		{ID: "b", Type: BoosterTypeFarcasterFollower, FID: 2, Points: 40},
+		{ID: "c", Type: BoosterTypeFarcasterFollower, FID: 3, Points: 20},
+	}}
+	counts := &ActivityReputationCounts{
+		FollowersCount:        1500,
+		ElectionsCreatedCount: 100,
+		CastVotesCount:        3,
+	}
+	improvements := reputationImprovements(counts, registry, Boosters{"c": true})
+	// the owned booster and the maxed activity are not included
+	ids := map[string]*ReputationImprovement{}
+	for _, i := range improvements {
+		ids[i.ID] = i
+	}
+	c.Assert(ids["c"], qt.IsNil)
+	c.Assert(ids["createdElections"], qt.IsNil)
+	// the improvements are ranked by the reputation gain
+	c.Assert(improvements[0].ID, qt.Equals, "b")
+	c.Assert(improvements[0].PointsGain, qt.Equals, uint64(40))
+	c.Assert(improvements[0].ReputationGain, qt.Equals, uint64(20))
+	c.Assert(improvements[1].ID, qt.Equals, "a")
+	c.Assert(improvements[1].ReputationGain, qt.Equals, uint64(5))
+	// the next activity threshold requires the remaining activity
+	c.Assert(ids["followers"].Type, qt.Equals, ImprovementTypeActivity)
+	c.Assert(ids["followers"].Required, qt.Equals, uint64(500))
+	c.Assert(ids["followers"].PointsGain, qt.Equals, uint64(1))
+	c.Assert(ids["castVotes"].Required, qt.Equals, uint64(1))
+	c.Assert(ids["communities"].PointsGain, qt.Equals, uint64(communitiesMultiplierPonderation))
+	for i := 1; i < len(improvements); i++ {
+		c.Assert(improvements[i-1].ReputationGain >= improvements[i].ReputationGain, qt.IsTrue)
+	}
+}
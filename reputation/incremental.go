@@ -0,0 +1,235 @@
+package reputation
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	dbmongo "github.com/vocdoni/vote-frame/mongo"
+	"go.vocdoni.io/dvote/log"
+)
+
+// boostersRefreshInterval is the minimum time between two refreshes of the
+// boosters data (followers and holders) during the incremental updates.
+const boostersRefreshInterval = time.Hour
+
+// incrementalUpdate method recomputes the reputation of the users and
+// communities that changed since the previous update. It includes the users
+// that created elections or voted, the owners of the elections that received
+// votes and the communities of those elections. If the boosters data is
+// outdated, it is refreshed and the users that gained or lost some booster are
+// also included. It only returns an error if the database is closed, the rest
+// of errors are logged.
+func (u *Updater) incrementalUpdate() error {
+	startTime := time.Now()
+	startPass()
+	since := time.Unix(0, activityCursor.Load())
+	users := map[uint64]bool{}
+	// refresh the boosters data and include the users that changed
+	if time.Since(u.lastBoostersUpdate) >= boostersRefreshInterval {
+		fids, err := u.fetchFollowersAndRecasters()
+		if err != nil {
+			log.Warnw("error fetching internal followers", "error", err)
+		}
+		for _, fid := range fids {
+			users[fid] = true
+		}
+		addrs, err := u.fetchHolders()
+		if err != nil {
+			log.Warnw("error fetching holders", "error", err)
+		}
+		if len(addrs) > 0 {
+			strAddrs := make([]string, 0, len(addrs))
+			for _, addr := range addrs {
+				strAddrs = append(strAddrs, addr.Hex())
+			}
+			holders, err := u.db.UserByAddressBulk(strAddrs)
+			if err != nil {
+				if dbmongo.IsDBClosed(err) {
+					return err
+				}
+				log.Warnw("error fetching users of changed holders", "error", err)
+			}
+			for _, user := range holders {
+				users[user.UserID] = true
+			}
+		}
+		u.lastBoostersUpdate = startTime
+	}
+	// include the users and communities with new activity, if they cannot be
+	// fetched, the activity cursor is not moved to retry them in the next
+	// update
+	moveCursor := true
+	activeUsers, err := u.db.UsersWithActivitySince(since)
+	if err != nil {
+		if dbmongo.IsDBClosed(err) {
+			return err
+		}
+		log.Warnw("error fetching users with new activity", "error", err)
+		moveCursor = false
+	}
+	for _, userID := range activeUsers {
+		users[userID] = true
+	}
+	communities, err := u.db.CommunitiesWithActivitySince(since)
+	if err != nil {
+		if dbmongo.IsDBClosed(err) {
+			return err
+		}
+		log.Warnw("error fetching communities with new activity", "error", err)
+		moveCursor = false
+	}
+	passItems.Store(int64(len(communities) + len(users)))
+	// update the communities first, because the points of the users depend on
+	// them, and include their creators
+	for _, communityID := range communities {
+		creator, err := u.updateCommunityParticipation(communityID)
+		passProcessedItems.Add(1)
+		if err != nil {
+			if dbmongo.IsDBClosed(err) {
+				return err
+			}
+			log.Errorf("error updating community %s: %v", communityID, err)
+			continue
+		}
+		communitiesUpdatedCounter.Inc()
+		if creator != 0 && !users[creator] {
+			users[creator] = true
+			passItems.Add(1)
+		}
+	}
+	userIDs := make([]uint64, 0, len(users))
+	for userID := range users {
+		userIDs = append(userIDs, userID)
+	}
+	updated := u.updateUsersByID(userIDs)
+	// update last update time and the activity cursor
+	if moveCursor {
+		activityCursor.Store(startTime.UnixNano())
+	}
+	u.lastIncrementalUpdate = time.Now()
+	incrementalUpdatesCounter.Inc()
+	log.Infow("reputations incrementally updated",
+		"communities", len(communities),
+		"users", len(userIDs),
+		"updated", updated,
+		"took", time.Since(startTime).String())
+	return nil
+}
+
+// updateCommunityParticipation method updates the participation mean and the
+// total reputation of the community provided. The census size is not updated
+// because it requires to query external sources, it is updated in the full
+// updates. It returns the FID of the creator of the community.
+func (u *Updater) updateCommunityParticipation(communityID string) (uint64, error) {
+	community, err := u.db.Community(communityID)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching community: %w", err)
+	}
+	if community == nil {
+		return 0, fmt.Errorf("community not found")
+	}
+	participation, err := u.db.CommunityParticipationMean(communityID)
+	if err != nil {
+		return 0, fmt.Errorf("error fetching community participation mean: %w", err)
+	}
+	if err := u.db.SetDetailedReputationForCommunity(communityID, &dbmongo.Reputation{
+		Participation: participation,
+	}); err != nil {
+		return 0, fmt.Errorf("error updating community reputation: %w", err)
+	}
+	if err := u.updateTotalReputation(&dbmongo.Reputation{CommunityID: communityID}); err != nil {
+		return 0, err
+	}
+	return community.Creator, nil
+}
+
+// updateUsersByID method updates the reputation and the total points of the
+// users provided concurrently. It returns the number of users updated.
+func (u *Updater) updateUsersByID(userIDs []uint64) int64 {
+	concurrentUpdates := make(chan struct{}, u.maxConcurrent)
+	innerWaiter := sync.WaitGroup{}
+	updates := atomic.Int64{}
+	for _, userID := range userIDs {
+		// get a slot in the concurrent updates channel
+		concurrentUpdates <- struct{}{}
+		innerWaiter.Add(1)
+		go func(userID uint64) {
+			// release the slot when the update is done
+			defer func() {
+				<-concurrentUpdates
+				innerWaiter.Done()
+				passProcessedItems.Add(1)
+			}()
+			if err := u.updateUserByID(userID); err != nil {
+				if !dbmongo.IsDBClosed(err) {
+					log.Errorf("error updating user %d: %v", userID, err)
+				}
+				return
+			}
+			updates.Add(1)
+			usersUpdatedCounter.Inc()
+		}(userID)
+	}
+	innerWaiter.Wait()
+	close(concurrentUpdates)
+	return updates.Load()
+}
+
+// updateUserByID method updates the reputation and the total points of the
+// user provided. The unknown users are ignored.
+func (u *Updater) updateUserByID(userID uint64) error {
+	user, err := u.db.User(userID)
+	if err != nil {
+		if errors.Is(err, dbmongo.ErrUserUnknown) {
+			return nil
+		}
+		return fmt.Errorf("error fetching user: %w", err)
+	}
+	if err := u.updateUserContants(user); err != nil {
+		return err
+	}
+	rep, err := u.db.DetailedUserReputation(userID)
+	if err != nil {
+		return fmt.Errorf("error fetching user reputation: %w", err)
+	}
+	return u.updateTotalReputation(rep)
+}
+
+// diffFollowers function returns the FIDs included in only one of the
+// followers sets provided.
+func diffFollowers(oldFollowers, newFollowers map[uint64]bool) []uint64 {
+	diff := []uint64{}
+	for fid := range newFollowers {
+		if !oldFollowers[fid] {
+			diff = append(diff, fid)
+		}
+	}
+	for fid := range oldFollowers {
+		if !newFollowers[fid] {
+			diff = append(diff, fid)
+		}
+	}
+	return diff
+}
+
+// diffHolders function returns the addresses whose balances get the booster
+// provided in only one of the holders sets provided.
+func diffHolders(booster *Booster, oldHolders, newHolders map[common.Address]*big.Int) []common.Address {
+	diff := []common.Address{}
+	for addr, balance := range newHolders {
+		if booster.HasEnoughBalance(balance) != booster.HasEnoughBalance(oldHolders[addr]) {
+			diff = append(diff, addr)
+		}
+	}
+	for addr, balance := range oldHolders {
+		if _, ok := newHolders[addr]; !ok && booster.HasEnoughBalance(balance) {
+			diff = append(diff, addr)
+		}
+	}
+	return diff
+}
@@ -0,0 +1,52 @@
+package reputation
+
+import (
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	qt "github.com/frankban/quicktest"
+)
+
+func TestDiffFollowers(t *testing.T) {
+	c := qt.New(t)
+	c.Assert(diffFollowers(nil, nil), qt.HasLen, 0)
+	diff := diffFollowers(
+		map[uint64]bool{1: true, 2: true},
+		map[uint64]bool{2: true, 3: true},
+	)
+	sort.Slice(diff, func(i, j int) bool { return diff[i] < diff[j] })
+	c.Assert(diff, qt.DeepEquals, []uint64{1, 3})
+	// the first fetch includes every follower
+	c.Assert(diffFollowers(nil, map[uint64]bool{1: true}), qt.DeepEquals, []uint64{1})
+}
+
+func TestDiffHolders(t *testing.T) {
+	c := qt.New(t)
+	booster := &Booster{MinBalance: 10}
+	a := common.HexToAddress("0x0a")
+	b := common.HexToAddress("0x0b")
+	d := common.HexToAddress("0x0d")
+	e := common.HexToAddress("0x0e")
+	oldHolders := map[common.Address]*big.Int{
+		a: big.NewInt(20), // keeps the booster
+		b: big.NewInt(20), // loses the booster by balance
+		d: big.NewInt(20), // loses the booster by leaving the holders
+		e: big.NewInt(1),  // leaves the holders without the booster
+	}
+	f := common.HexToAddress("0x0f")
+	g := common.HexToAddress("0x10")
+	newHolders := map[common.Address]*big.Int{
+		a: big.NewInt(30),
+		b: big.NewInt(5),
+		f: big.NewInt(10), // gets the booster
+		g: big.NewInt(1),  // joins the holders without the booster
+	}
+	diff := diffHolders(booster, oldHolders, newHolders)
+	got := map[common.Address]bool{}
+	for _, addr := range diff {
+		got[addr] = true
+	}
+	c.Assert(got, qt.DeepEquals, map[common.Address]bool{b: true, d: true, f: true})
+}
@@ -0,0 +1,66 @@
+package reputation
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+)
+
+var (
+	// fullUpdatesCounter counts the full rebuilds of the reputations
+	fullUpdatesCounter = metrics.NewCounter("reputation_updater_full_updates_total")
+	// incrementalUpdatesCounter counts the incremental updates of the
+	// reputations
+	incrementalUpdatesCounter = metrics.NewCounter("reputation_updater_incremental_updates_total")
+	// usersUpdatedCounter counts the reputations of users updated
+	usersUpdatedCounter = metrics.NewCounter("reputation_updater_users_updated_total")
+	// communitiesUpdatedCounter counts the reputations of communities updated
+	communitiesUpdatedCounter = metrics.NewCounter("reputation_updater_communities_updated_total")
+
+	// passItems and passProcessedItems contain the number of items (users
+	// and communities) to update in the current update and the number of
+	// them already processed
+	passItems          atomic.Int64
+	passProcessedItems atomic.Int64
+	// activityCursor contains the time (in unix nanoseconds) until which the
+	// activity of the users has been processed
+	activityCursor atomic.Int64
+	// lastFullUpdate contains the time (in unix seconds) of the last full
+	// rebuild of the reputations
+	lastFullUpdate atomic.Int64
+)
+
+func init() {
+	metrics.NewGauge("reputation_updater_pass_items", func() float64 {
+		return float64(passItems.Load())
+	})
+	metrics.NewGauge("reputation_updater_pass_processed_items", func() float64 {
+		return float64(passProcessedItems.Load())
+	})
+	// progress of the current update, between 0 and 1
+	metrics.NewGauge("reputation_updater_progress", func() float64 {
+		total := passItems.Load()
+		if total == 0 {
+			return 1
+		}
+		return float64(passProcessedItems.Load()) / float64(total)
+	})
+	// lag between the current time and the last activity processed
+	metrics.NewGauge("reputation_updater_lag_seconds", func() float64 {
+		cursor := activityCursor.Load()
+		if cursor == 0 {
+			return 0
+		}
+		return time.Since(time.Unix(0, cursor)).Seconds()
+	})
+	metrics.NewGauge("reputation_updater_last_full_update_timestamp", func() float64 {
+		return float64(lastFullUpdate.Load())
+	})
+}
+
+// startPass resets the progress metrics to start a new update.
+func startPass() {
+	passItems.Store(0)
+	passProcessedItems.Store(0)
+}
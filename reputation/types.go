@@ -1,6 +1,10 @@
 package reputation
 
-import "github.com/vocdoni/vote-frame/mongo"
+import (
+	"time"
+
+	"github.com/vocdoni/vote-frame/mongo"
+)
 
 // ActivityReputationCounts struct contains the user activity counts for each
 // activity indicator.
@@ -22,26 +26,10 @@ type ActivityReputationPoints struct {
 	CommunitiesPoints      uint64 `json:"communitiesPoints"`
 }
 
-// Boosters struct contains the user boosters information, which are boolean
-// values that indicate if the user has a specific booster.
-type Boosters struct {
-	HasVotecasterNFTPass           bool `json:"hasVotecasterNFTPass"`
-	HasVotecasterLaunchNFT         bool `json:"hasVotecasterLaunchNFT"`
-	IsVotecasterAlphafrensFollower bool `json:"isVotecasterAlphafrensFollower"`
-	IsVotecasterFarcasterFollower  bool `json:"isVotecasterFarcasterFollower"`
-	IsVocdoniFarcasterFollower     bool `json:"isVocdoniFarcasterFollower"`
-	VotecasterAnnouncementRecasted bool `json:"votecasterAnnouncementRecasted"`
-	HasKIWI                        bool `json:"hasKIWI"`
-	HasDegenDAONFT                 bool `json:"hasDegenDAONFT"`
-	HasHaberdasheryNFT             bool `json:"hasHaberdasheryNFT"`
-	Has10kDegenAtLeast             bool `json:"has10kDegenAtLeast"`
-	HasTokyoDAONFT                 bool `json:"hasTokyoDAONFT"`
-	Has5ProxyAtLeast               bool `json:"has5ProxyAtLeast"`
-	HasProxyStudioNFT              bool `json:"hasProxyStudioNFT"`
-	HasNameDegen                   bool `json:"hasNameDegen"`
-	HasFarcasterOGNFT              bool `json:"hasFarcasterOGNFT"`
-	HasMoxiePass                   bool `json:"hasMoxiePass"`
-}
+// Boosters type contains the user boosters information, which is a map of
+// boolean values indexed by the booster ID that indicate if the user has a
+// specific booster.
+type Boosters map[string]bool
 
 // ReputationInfo type is a map that contains the reputation information for
 // each activity or booster indicator.
@@ -52,7 +40,7 @@ type ReputationInfo map[string]uint64
 // the user total reputation, the user total points, the user boosters, the
 // user boosters info.
 type Reputation struct {
-	*Boosters       `json:"boosters"`
+	Boosters        Boosters                  `json:"boosters"`
 	BoostersInfo    ReputationInfo            `json:"boostersInfo"`
 	ActivityPoints  *ActivityReputationPoints `json:"activityPoints"`
 	ActivityCounts  *ActivityReputationCounts `json:"activityCounts"`
@@ -62,8 +50,9 @@ type Reputation struct {
 }
 
 // ReputationToAPIResponse function converts a mongo.Reputation struct to a
-// Reputation struct.
-func ReputationToAPIResponse(rep *mongo.Reputation) *Reputation {
+// Reputation struct, including the boosters information of the registry
+// provided.
+func ReputationToAPIResponse(rep *mongo.Reputation, registry *BoostersRegistry) *Reputation {
 	if rep == nil {
 		return nil
 	}
@@ -74,29 +63,51 @@ func ReputationToAPIResponse(rep *mongo.Reputation) *Reputation {
 		ParticipationsCount:   rep.ParticipationsCount,
 		CommunitiesCount:      rep.CommunitiesCount,
 	}
+	boosters := Boosters{}
+	for _, booster := range registry.Boosters {
+		boosters[booster.ID] = rep.Boosters[booster.ID]
+	}
 	return &Reputation{
-		ActivityCounts: activityPoints,
-		ActivityPoints: ponderateActivityReputation(activityPoints),
-		ActivityInfo:   ActivityPuntuationInfo,
-		Boosters: &Boosters{
-			HasVotecasterNFTPass:           rep.HasVotecasterNFTPass,
-			HasVotecasterLaunchNFT:         rep.HasVotecasterLaunchNFT,
-			IsVotecasterAlphafrensFollower: rep.IsVotecasterAlphafrensFollower,
-			IsVotecasterFarcasterFollower:  rep.IsVotecasterFarcasterFollower,
-			IsVocdoniFarcasterFollower:     rep.IsVocdoniFarcasterFollower,
-			VotecasterAnnouncementRecasted: rep.VotecasterAnnouncementRecasted,
-			HasKIWI:                        rep.HasKIWI,
-			HasDegenDAONFT:                 rep.HasDegenDAONFT,
-			HasHaberdasheryNFT:             rep.HasHaberdasheryNFT,
-			Has10kDegenAtLeast:             rep.Has10kDegenAtLeast,
-			HasTokyoDAONFT:                 rep.HasTokyoDAONFT,
-			Has5ProxyAtLeast:               rep.Has5ProxyAtLeast,
-			HasProxyStudioNFT:              rep.HasProxyStudioNFT,
-			HasNameDegen:                   rep.HasNameDegen,
-			HasFarcasterOGNFT:              rep.HasFarcasterOGNFT,
-		},
-		BoostersInfo:    BoostersPuntuationInfo,
+		ActivityCounts:  activityPoints,
+		ActivityPoints:  ponderateActivityReputation(activityPoints),
+		ActivityInfo:    ActivityPuntuationInfo,
+		Boosters:        boosters,
+		BoostersInfo:    registry.Info(),
 		TotalReputation: rep.TotalReputation,
 		TotalPoints:     rep.TotalPoints,
 	}
 }
+
+// ReputationSnapshot struct contains the reputation of a user or a community
+// at a given time. For users, it includes the breakdown of the reputation in
+// activity and boosters reputation, the activity points and the boosters. For
+// communities, it includes the participation and the census size.
+type ReputationSnapshot struct {
+	Timestamp          time.Time                 `json:"timestamp"`
+	TotalReputation    uint64                    `json:"totalReputation"`
+	TotalPoints        uint64                    `json:"totalPoints"`
+	ActivityReputation uint64                    `json:"activityReputation,omitempty"`
+	BoostersReputation uint64                    `json:"boostersReputation,omitempty"`
+	ActivityPoints     *ActivityReputationPoints `json:"activityPoints,omitempty"`
+	Boosters           Boosters                  `json:"boosters,omitempty"`
+	Participation      float64                   `json:"participation,omitempty"`
+	CensusSize         uint64                    `json:"censusSize,omitempty"`
+}
+
+// ReputationDelta struct contains the difference between the last reputation
+// snapshot and the previous one.
+type ReputationDelta struct {
+	Since              time.Time `json:"since"`
+	TotalReputation    int64     `json:"totalReputation"`
+	TotalPoints        int64     `json:"totalPoints"`
+	ActivityReputation int64     `json:"activityReputation"`
+	BoostersReputation int64     `json:"boostersReputation"`
+}
+
+// ReputationHistory struct contains the time series of reputation snapshots
+// of a user or a community, sorted from the oldest to the newest, and the
+// delta since the previous snapshot.
+type ReputationHistory struct {
+	Snapshots []*ReputationSnapshot `json:"snapshots"`
+	Delta     *ReputationDelta      `json:"delta,omitempty"`
+}
@@ -34,33 +34,31 @@ type Updater struct {
 	lastUpdate    time.Time
 	maxConcurrent int
 
-	alfafrensFollowers  map[uint64]bool
-	vocdoniFollowers    map[uint64]bool
-	votecasterFollowers map[uint64]bool
-	recasters           map[uint64]bool
-	followersMtx        sync.Mutex
-	cachedFollowers     atomic.Bool
+	boosters *BoostersRegistry
 
-	votecasterNFTPassHolders   map[common.Address]*big.Int
-	votecasterLaunchNFTHolders map[common.Address]*big.Int
-	kiwiHolders                map[common.Address]*big.Int
-	degenDAONFTHolders         map[common.Address]*big.Int
-	haberdasheryNFTHolders     map[common.Address]*big.Int
-	tokyoDAONFTHolders         map[common.Address]*big.Int
-	proxyHolders               map[common.Address]*big.Int
-	proxyStudioNFTHolders      map[common.Address]*big.Int
-	nameDegenHolders           map[common.Address]*big.Int
-	farcasterOGNFTHolders      map[common.Address]*big.Int
-	moxiePassHolders           map[common.Address]*big.Int
-	holdersMtx                 sync.Mutex
-	cachedHolders              atomic.Bool
+	followers       map[string]map[uint64]bool
+	followersMtx    sync.Mutex
+	cachedFollowers atomic.Bool
+
+	holders       map[string]map[common.Address]*big.Int
+	holdersMtx    sync.Mutex
+	cachedHolders atomic.Bool
+
+	activityHalfLife time.Duration
+
+	lastIncrementalUpdate time.Time
+	lastBoostersUpdate    time.Time
 }
 
 // NewUpdater creates a new Updater instance with the given parameters,
 // including the parent context, the database, the Airstack client, the Census3
-// client, and the maximum number of concurrent updates.
+// client, the boosters registry, the half-life of the user activity decay and
+// the maximum number of concurrent updates. If the half-life is zero, the
+// user activity does not decay.
 func NewUpdater(ctx context.Context, db *dbmongo.MongoStorage, fapi farcasterapi.API,
-	c3 *apiclient.HTTPclient, maxConcurrent int) (*Updater, error) {
+	c3 *apiclient.HTTPclient, boosters *BoostersRegistry, activityHalfLife time.Duration,
+	maxConcurrent int,
+) (*Updater, error) {
 	if db == nil {
 		return nil, errors.New("database is required")
 	}
@@ -70,37 +68,35 @@ func NewUpdater(ctx context.Context, db *dbmongo.MongoStorage, fapi farcasterapi
 	if c3 == nil {
 		return nil, errors.New("census3 client is required")
 	}
+	if boosters == nil {
+		return nil, errors.New("boosters registry is required")
+	}
+	if activityHalfLife < 0 {
+		return nil, errors.New("activity half-life must not be negative")
+	}
 	internalCtx, cancel := context.WithCancel(ctx)
 	return &Updater{
-		ctx:                        internalCtx,
-		cancel:                     cancel,
-		db:                         db,
-		fapi:                       fapi,
-		census3:                    c3,
-		lastUpdate:                 time.Time{},
-		maxConcurrent:              maxConcurrent,
-		alfafrensFollowers:         make(map[uint64]bool),
-		vocdoniFollowers:           make(map[uint64]bool),
-		votecasterFollowers:        make(map[uint64]bool),
-		recasters:                  make(map[uint64]bool),
-		votecasterNFTPassHolders:   make(map[common.Address]*big.Int),
-		votecasterLaunchNFTHolders: make(map[common.Address]*big.Int),
-		kiwiHolders:                make(map[common.Address]*big.Int),
-		degenDAONFTHolders:         make(map[common.Address]*big.Int),
-		haberdasheryNFTHolders:     make(map[common.Address]*big.Int),
-		tokyoDAONFTHolders:         make(map[common.Address]*big.Int),
-		proxyHolders:               make(map[common.Address]*big.Int),
-		proxyStudioNFTHolders:      make(map[common.Address]*big.Int),
-		nameDegenHolders:           make(map[common.Address]*big.Int),
-		farcasterOGNFTHolders:      make(map[common.Address]*big.Int),
-		moxiePassHolders:           make(map[common.Address]*big.Int),
+		ctx:           internalCtx,
+		cancel:        cancel,
+		db:            db,
+		fapi:          fapi,
+		census3:       c3,
+		lastUpdate:    time.Time{},
+		maxConcurrent: maxConcurrent,
+		boosters:      boosters,
+		followers:     make(map[string]map[uint64]bool),
+		holders:       make(map[string]map[common.Address]*big.Int),
+
+		activityHalfLife: activityHalfLife,
 	}, nil
 }
 
-// Start method starts the updater with the given cooldown time between updates.
-// It will run until the context is canceled, calling the updateUsers method
-// periodically and updating the last update time accordingly.
-func (u *Updater) Start(coolDown time.Duration) error {
+// Start method starts the updater with the given cooldown times between full
+// and incremental updates. It will run until the context is canceled. Every
+// full update rebuilds the reputation of every user and community, while
+// every incremental update only recomputes the reputation of the users and
+// communities whose activity or boosters changed since the previous update.
+func (u *Updater) Start(coolDown, incrementalCoolDown time.Duration) error {
 	u.waiter.Add(1)
 	go func() {
 		defer u.waiter.Done()
@@ -110,48 +106,80 @@ func (u *Updater) Start(coolDown time.Duration) error {
 			case <-u.ctx.Done():
 				return
 			default:
-				// check if is time to update users
-				if time.Since(u.lastUpdate) < coolDown {
-					time.Sleep(time.Second * 30)
-					continue
-				}
-				// fetch internal followers
-				if err := u.fetchFollowersAndRecasters(); err != nil {
-					log.Warnw("error fetching internal followers", "error", err)
-				}
-				// fetch holders
-				if err := u.fetchHolders(); err != nil {
-					log.Warnw("error fetching holders", "error", err)
-				}
-				// update communities contants (participation mean and census size)
-				if err := u.updateCommunitiesContants(); err != nil {
-					if mongo.IsDBClosed(err) {
+				switch {
+				case time.Since(u.lastUpdate) >= coolDown:
+					if err := u.fullUpdate(); err != nil {
 						return
 					}
-					log.Warnw("error updating communities constants", "error", err)
-				}
-				// update users constants (activity reputation and boosters)
-				if err := u.updateUsersConstants(); err != nil {
-					if mongo.IsDBClosed(err) {
-						return
-					}
-					log.Warnw("error updating users constants", "error", err)
-				}
-				// update total reputations of both, communities and users
-				if err := u.updateTotalReputations(); err != nil {
-					if mongo.IsDBClosed(err) {
+				case time.Since(u.lastIncrementalUpdate) >= incrementalCoolDown:
+					if err := u.incrementalUpdate(); err != nil {
 						return
 					}
-					log.Warnw("error updating total reputations", "error", err)
+				default:
+					time.Sleep(time.Second * 30)
 				}
-				// update last update time
-				u.lastUpdate = time.Now()
 			}
 		}
 	}()
 	return nil
 }
 
+// fullUpdate method rebuilds the reputation of every user and community. It
+// refetches the boosters data, updates the constants of every community and
+// user, updates the total reputations and stores a snapshot of them. It only
+// returns an error if the database is closed, the rest of errors are logged.
+func (u *Updater) fullUpdate() error {
+	startTime := time.Now()
+	startPass()
+	// fetch internal followers
+	if _, err := u.fetchFollowersAndRecasters(); err != nil {
+		log.Warnw("error fetching internal followers", "error", err)
+	}
+	// fetch holders
+	if _, err := u.fetchHolders(); err != nil {
+		log.Warnw("error fetching holders", "error", err)
+	}
+	u.lastBoostersUpdate = startTime
+	// update communities contants (participation mean and census size)
+	if err := u.updateCommunitiesContants(); err != nil {
+		if mongo.IsDBClosed(err) {
+			return err
+		}
+		log.Warnw("error updating communities constants", "error", err)
+	}
+	// update users constants (activity reputation and boosters)
+	if err := u.updateUsersConstants(); err != nil {
+		if mongo.IsDBClosed(err) {
+			return err
+		}
+		log.Warnw("error updating users constants", "error", err)
+	}
+	// update total reputations of both, communities and users
+	if err := u.updateTotalReputations(); err != nil {
+		if mongo.IsDBClosed(err) {
+			return err
+		}
+		log.Warnw("error updating total reputations", "error", err)
+	}
+	// store a snapshot of the updated reputations to keep track of their
+	// evolution
+	if err := u.snapshotReputations(); err != nil {
+		if mongo.IsDBClosed(err) {
+			return err
+		}
+		log.Warnw("error taking reputations snapshot", "error", err)
+	}
+	// update last update times, the activity done since the start of the
+	// full update will be processed by the next incremental update
+	u.lastUpdate = time.Now()
+	u.lastIncrementalUpdate = u.lastUpdate
+	activityCursor.Store(startTime.UnixNano())
+	lastFullUpdate.Store(u.lastUpdate.Unix())
+	fullUpdatesCounter.Inc()
+	log.Infow("reputations fully updated", "took", time.Since(startTime).String())
+	return nil
+}
+
 // Stop method stops the updater by canceling the context and waiting for the
 // updater to finish.
 func (u *Updater) Stop() {
@@ -172,13 +200,13 @@ func (u *Updater) UserReputation(userID uint64, commit bool) (*Reputation, error
 	}
 	// fetch internal followers
 	if !u.cachedFollowers.Load() {
-		if err := u.fetchFollowersAndRecasters(); err != nil {
+		if _, err := u.fetchFollowersAndRecasters(); err != nil {
 			log.Warnw("error fetching internal followers", "error", err)
 		}
 	}
 	// fetch holders
 	if !u.cachedHolders.Load() {
-		if err := u.fetchHolders(); err != nil {
+		if _, err := u.fetchHolders(); err != nil {
 			log.Warnw("error fetching holders", "error", err)
 		}
 	}
@@ -202,7 +230,12 @@ func (u *Updater) UserReputation(userID uint64, commit bool) (*Reputation, error
 			log.Warnw("error updating user reputation", "error", err)
 		}
 	}
-	return ReputationToAPIResponse(rep), nil
+	return ReputationToAPIResponse(rep, u.boosters), nil
+}
+
+// Boosters method returns the boosters registry used by the updater.
+func (u *Updater) Boosters() *BoostersRegistry {
+	return u.boosters
 }
 
 // userPoints method calculates the points of a user based on the user
@@ -285,141 +318,98 @@ func (u *Updater) communityPoints(communityID string) (uint64, error) {
 		userRep.TotalReputation), nil
 }
 
-// fetchFollowersAndRecasters method updates the internal followers of the
-// Vocdoni and Votecaster profiles in Farcaster and warpcast users that have
-// recasted the Votecaster Launch cast announcement. It fetches the followers
-// and recasters data from the Farcaster API and updates the internal followers
-// maps accordingly. It returns an error if the followers data cannot be fetched.
-func (u *Updater) fetchFollowersAndRecasters() error {
+// fetchFollowersAndRecasters method updates the internal followers cache of
+// every booster of the registry that depends on the Farcaster social graph:
+// the followers of Farcaster profiles, the subscribers of Alfafrens channels
+// and the users that have recasted a cast. It fetches the data from the
+// Farcaster API and the Alfafrens API and replaces the internal followers maps
+// accordingly. It returns the FIDs of the users that have gained or lost some
+// booster, and an error if some followers data cannot be fetched.
+func (u *Updater) fetchFollowersAndRecasters() ([]uint64, error) {
 	log.Info("fetching followers and recasters")
 	internalCtx, cancel := context.WithTimeout(u.ctx, time.Second*30)
 	defer cancel()
 	u.followersMtx.Lock()
 	defer u.followersMtx.Unlock()
-	// update alfafrens followers
-	alfafrensFollowers, err := alfafrens.ChannelFids(VotecasterAlphafrensChannelAddress.Bytes())
-	if err == nil {
-		for _, fid := range alfafrensFollowers {
-			u.alfafrensFollowers[fid] = true
+	changed := map[uint64]bool{}
+	var errs []error
+	for _, booster := range u.boosters.Boosters {
+		var fids []uint64
+		var err error
+		switch booster.Type {
+		case BoosterTypeAlfafrensFollower:
+			fids, err = alfafrens.ChannelFids(common.HexToAddress(booster.Address).Bytes())
+		case BoosterTypeFarcasterFollower:
+			fids, err = u.fapi.UserFollowers(internalCtx, booster.FID)
+		case BoosterTypeRecaster:
+			fids, err = u.fapi.RecastsFIDs(internalCtx, &farcasterapi.APIMessage{
+				Author: booster.FID,
+				Hash:   booster.CastHash,
+			})
+		default:
+			continue
 		}
-	}
-	// update vocdoni followers
-	vocdoniFollowers, err1 := u.fapi.UserFollowers(internalCtx, VocdoniFarcasterFID)
-	if err1 == nil {
-		for _, fid := range vocdoniFollowers {
-			u.vocdoniFollowers[fid] = true
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error getting %s followers: %w", booster.ID, err))
+			continue
 		}
-	}
-	// update votecaster followers
-	votecasterFollowers, err2 := u.fapi.UserFollowers(internalCtx, VotecasterFarcasterFID)
-	if err2 == nil {
-		for _, fid := range votecasterFollowers {
-			u.votecasterFollowers[fid] = true
+		followers := make(map[uint64]bool, len(fids))
+		for _, fid := range fids {
+			followers[fid] = true
 		}
-	}
-	// update recasters
-	recasters, err3 := u.fapi.RecastsFIDs(internalCtx, &farcasterapi.APIMessage{
-		Author: VocdoniFarcasterFID,
-		Hash:   VotecasterAnnouncementCastHash,
-	})
-	if err3 == nil {
-		for _, fid := range recasters {
-			u.recasters[fid] = true
+		for _, fid := range diffFollowers(u.followers[booster.ID], followers) {
+			changed[fid] = true
 		}
+		u.followers[booster.ID] = followers
+		log.Debugw("booster followers", "booster", booster.ID, "followers", len(followers))
 	}
 	u.cachedFollowers.Store(true)
-	if err != nil || err1 != nil || err2 != nil || err3 != nil {
-		return fmt.Errorf("error updating internal followers: %w, %w, %w, %w", err, err1, err2, err3)
+	changedFIDs := make([]uint64, 0, len(changed))
+	for fid := range changed {
+		changedFIDs = append(changedFIDs, fid)
 	}
-	return nil
+	if len(errs) > 0 {
+		return changedFIDs, fmt.Errorf("error updating internal followers: %v", errs)
+	}
+	return changedFIDs, nil
 }
 
 // fetchHolders method updates the internal holders lists to cache the holders
-// of the Votecaster NFT pass, the Votecaster Launch NFT, the KIWI token, the
-// DegenDAO NFT, the Haberdashery NFT, the TokyoDAO NFT, the Proxy, the
-// ProxyStudio NFT, and the NameDegen NFT. It fetches the holders data from the
-// Airstack API and the Census3 API. It returns an error if the holders data
-// cannot be fetched.
-func (u *Updater) fetchHolders() error {
+// of every token booster of the registry. It fetches the holders data from the
+// Census3 API. The holders of the boosters that require to check the balance
+// of every user are not cached because they are too many. It returns the
+// addresses that have gained or lost some booster, and an error if the holders
+// data cannot be fetched.
+func (u *Updater) fetchHolders() ([]common.Address, error) {
 	log.Info("fetching holders of reputation erc20's and nft's")
 	u.holdersMtx.Lock()
 	defer u.holdersMtx.Unlock()
-	var err error
+	changed := map[common.Address]bool{}
 	var errs []error
-	// update Votecaster NFT pass holders
-	u.votecasterNFTPassHolders, err = u.tokenHolders(VotecasterNFTPassAddress, VotecasterNFTPassChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting votecaster nft pass holders: %w", err))
-	}
-	log.Debugw("votecaster nft pass holders", "holders", len(u.votecasterNFTPassHolders))
-	// update Votecaster Launch NFT holders
-	u.votecasterLaunchNFTHolders, err = u.tokenHolders(VotecasterLaunchNFTAddress, VotecasterLaunchNFTChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting votecaster launch nft holders: %w", err))
-	}
-	log.Debugw("votecaster launch nft holders", "holders", len(u.votecasterLaunchNFTHolders))
-	// update KIWI holders
-	u.kiwiHolders, err = u.tokenHolders(KIWIAddress, KIWIChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting kiwi holders: %w", err))
-	}
-	log.Debugw("kiwi holders", "holders", len(u.kiwiHolders))
-	// update DegenDAO NFT holders
-	u.degenDAONFTHolders, err = u.tokenHolders(DegenDAONFTAddress, DegenDAONFTChainChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting degen dao nft holders: %w", err))
-	}
-	log.Debugw("degen dao nft holders", "holders", len(u.degenDAONFTHolders))
-	// Note: Degen holders are not cached because they are too many, instead
-	// every time we need to check if a user is a Degen holder we will check
-	// the balance of the user
-
-	// update Haberdashery NFT holders
-	u.haberdasheryNFTHolders, err = u.tokenHolders(HaberdasheryNFTAddress, HaberdasheryNFTChainChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting haberdashery nft holders: %w", err))
-	}
-	log.Debugw("haberdashery nft holders", "holders", len(u.haberdasheryNFTHolders))
-	// update TokyoDAO NFT holders
-	u.tokyoDAONFTHolders, err = u.tokenHolders(TokyoDAONFTAddress, TokyoDAONFTChainChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting TokyoDAO NFT holders: %w", err))
-	}
-	log.Debugw("tokyo dao nft holders", "holders", len(u.tokyoDAONFTHolders))
-	// update Proxy
-	u.proxyHolders, err = u.tokenHolders(ProxyAddress, ProxyChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting proxy holders: %w", err))
-	}
-	log.Debugw("proxy holders", "holders", len(u.proxyHolders))
-	// update ProxyStudio NFT holders
-	u.proxyStudioNFTHolders, err = u.tokenHolders(ProxyStudioNFTAddress, ProxyStudioNFTChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting proxy studio nft holders: %w", err))
-	}
-	log.Debugw("proxy studio nft holders", "holders", len(u.proxyStudioNFTHolders))
-	// update NameDegen NFT holders
-	u.nameDegenHolders, err = u.tokenHolders(NameDegenAddress, NameDegenChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting name degen nft holders: %w", err))
-	}
-	log.Debugw("name degen nft holders", "holders", len(u.nameDegenHolders))
-	// update Farcaster OG NFT holders
-	u.farcasterOGNFTHolders, err = u.tokenHolders(FarcasterOGNFTAddress, FarcasterOGNFTChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting farcaster og nft holders: %w", err))
-	}
-	log.Debugw("farcaster og nft holders", "holders", len(u.farcasterOGNFTHolders))
-	// update Moxie Pass NFT holders
-	u.moxiePassHolders, err = u.tokenHolders(MoxiePassAddress, MoxiePassChainChainID)
-	if err != nil {
-		errs = append(errs, fmt.Errorf("error getting moxie pass nft holders: %w", err))
+	for _, booster := range u.boosters.Boosters {
+		if !booster.IsToken() || booster.CheckBalance {
+			continue
+		}
+		holders, err := u.tokenHolders(booster.TokenAddress(), booster.ChainID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error getting %s holders: %w", booster.ID, err))
+			continue
+		}
+		for _, addr := range diffHolders(booster, u.holders[booster.ID], holders) {
+			changed[addr] = true
+		}
+		u.holders[booster.ID] = holders
+		log.Debugw("booster holders", "booster", booster.ID, "holders", len(holders))
 	}
 	u.cachedHolders.Store(true)
+	changedAddrs := make([]common.Address, 0, len(changed))
+	for addr := range changed {
+		changedAddrs = append(changedAddrs, addr)
+	}
 	if len(errs) > 0 {
-		return fmt.Errorf("error updating holders: %v", errs)
+		return changedAddrs, fmt.Errorf("error updating holders: %v", errs)
 	}
-	return nil
+	return changedAddrs, nil
 }
 
 // updateCommunitiesContants method updates the participation mean and the
@@ -439,6 +429,7 @@ func (u *Updater) updateCommunitiesContants() error {
 	if err != nil {
 		return fmt.Errorf("error listing communities: %w", err)
 	}
+	passItems.Add(int64(len(communities)))
 	// counters for total and updated communities
 	updates := atomic.Int64{}
 	// listen for communities and update them concurrently
@@ -455,6 +446,7 @@ func (u *Updater) updateCommunitiesContants() error {
 				defer func() {
 					<-concurrentUpdates
 					innerWaiter.Done()
+					passProcessedItems.Add(1)
 				}()
 				participation, censusSize, err := u.communityConstants(community)
 				if err != nil {
@@ -470,6 +462,7 @@ func (u *Updater) updateCommunitiesContants() error {
 					}
 					return
 				}
+				communitiesUpdatedCounter.Inc()
 			}(&community)
 		}
 	}()
@@ -503,6 +496,7 @@ func (u *Updater) updateUsersConstants() error {
 			return
 		}
 		total.Store(int64(len(users)))
+		passItems.Add(int64(len(users)))
 		for _, user := range users {
 			// get a slot in the concurrent updates channel
 			concurrentUpdates <- struct{}{}
@@ -512,6 +506,7 @@ func (u *Updater) updateUsersConstants() error {
 				defer func() {
 					<-concurrentUpdates
 					innerWaiter.Done()
+					passProcessedItems.Add(1)
 				}()
 				// update user reputation
 				if err := u.updateUserContants(user); err != nil {
@@ -521,6 +516,7 @@ func (u *Updater) updateUsersConstants() error {
 					log.Errorf("error updating user %d: %v", user.UserID, err)
 				} else {
 					updates.Add(1)
+					usersUpdatedCounter.Inc()
 				}
 			}(user)
 		}
@@ -556,6 +552,7 @@ func (u *Updater) updateTotalReputations() error {
 			return
 		}
 		total.Store(int64(len(reputations)))
+		passItems.Add(int64(len(reputations)))
 		for _, reputation := range reputations {
 			// get a slot in the concurrent updates channel
 			concurrentUpdates <- struct{}{}
@@ -565,6 +562,7 @@ func (u *Updater) updateTotalReputations() error {
 				defer func() {
 					<-concurrentUpdates
 					innerWaiter.Done()
+					passProcessedItems.Add(1)
 				}()
 				// update total reputation
 				if err := u.updateTotalReputation(rep); err != nil {
@@ -717,24 +715,9 @@ func (u *Updater) userReputation(user *dbmongo.User) (*mongo.Reputation, error)
 	}
 	// get boosters data and update reputation
 	boostersRep := u.userBoosters(user)
-	rep.HasVotecasterNFTPass = boostersRep.HasVotecasterNFTPass
-	rep.HasVotecasterLaunchNFT = boostersRep.HasVotecasterLaunchNFT
-	rep.IsVotecasterAlphafrensFollower = boostersRep.IsVotecasterAlphafrensFollower
-	rep.IsVotecasterFarcasterFollower = boostersRep.IsVotecasterFarcasterFollower
-	rep.IsVocdoniFarcasterFollower = boostersRep.IsVocdoniFarcasterFollower
-	rep.VotecasterAnnouncementRecasted = boostersRep.VotecasterAnnouncementRecasted
-	rep.HasKIWI = boostersRep.HasKIWI
-	rep.HasDegenDAONFT = boostersRep.HasDegenDAONFT
-	rep.HasHaberdasheryNFT = boostersRep.HasHaberdasheryNFT
-	rep.Has10kDegenAtLeast = boostersRep.Has10kDegenAtLeast
-	rep.HasTokyoDAONFT = boostersRep.HasTokyoDAONFT
-	rep.HasProxyStudioNFT = boostersRep.HasProxyStudioNFT
-	rep.Has5ProxyAtLeast = boostersRep.Has5ProxyAtLeast
-	rep.HasNameDegen = boostersRep.HasNameDegen
-	rep.HasFarcasterOGNFT = boostersRep.HasFarcasterOGNFT
-	rep.HasMoxiePass = boostersRep.HasMoxiePass
+	rep.Boosters = boostersRep
 	// calculate total reputation
-	rep.TotalReputation = totalReputation(activityRep, boostersRep)
+	rep.TotalReputation = totalReputation(activityRep, u.boosters, boostersRep)
 	return rep, nil
 }
 
@@ -743,7 +726,10 @@ func (u *Updater) userReputation(user *dbmongo.User) (*mongo.Reputation, error)
 // The activity data includes the number of followers, the number of elections
 // created, the number of casted votes, the number of votes casted on elections
 // created by the user, and the number of communities where the user is an
-// admin. It returns an error if the activity data cannot be fetched.
+// admin. If the activity half-life is set, the elections created, the casted
+// votes and the votes casted on elections created by the user are
+// time-weighted, so the old activity counts less than the recent one. It
+// returns an error if the activity data cannot be fetched.
 func (u *Updater) userActivityReputation(user *dbmongo.User) (*ActivityReputationCounts, error) {
 	// Fetch the total votes cast on elections created by the user
 	totalVotes, err := u.db.TotalVotesForUserElections(user.UserID)
@@ -755,108 +741,67 @@ func (u *Updater) userActivityReputation(user *dbmongo.User) (*ActivityReputatio
 	if err != nil {
 		return &ActivityReputationCounts{}, fmt.Errorf("error fetching communities count for user: %w", err)
 	}
-	return &ActivityReputationCounts{
+	counts := &ActivityReputationCounts{
 		FollowersCount:        user.Followers,
 		ElectionsCreatedCount: user.ElectionCount,
 		CastVotesCount:        user.CastedVotes,
 		ParticipationsCount:   totalVotes,
 		CommunitiesCount:      communitiesCount,
-	}, nil
+	}
+	if u.activityHalfLife == 0 {
+		return counts, nil
+	}
+	// if the activity decays, replace the lifetime counts of the timestamped
+	// activities by the decayed ones, which never exceed the lifetime counts
+	now := time.Now()
+	activities, err := u.db.UserActivities(user.UserID, now.Add(-u.activityHalfLife*activityDecayHorizon))
+	if err != nil {
+		return &ActivityReputationCounts{}, fmt.Errorf("error fetching user activities: %w", err)
+	}
+	decayed := decayedActivityCounts(user.UserID, activities, u.activityHalfLife, now)
+	counts.ElectionsCreatedCount = min(decayed.ElectionsCreatedCount, counts.ElectionsCreatedCount)
+	counts.CastVotesCount = min(decayed.CastVotesCount, counts.CastVotesCount)
+	counts.ParticipationsCount = min(decayed.ParticipationsCount, counts.ParticipationsCount)
+	return counts, nil
 }
 
-// userBoosters method fetches the boosters data of a given user from the
-// Airstack and the Census3 API. It returns the boosters data as a Boosters
-// struct. The boosters data includes whether the user has the Votecaster NFT
-// pass, the Votecaster Launch NFT, the user is subscribed to Votecaster
-// Alphafrens channel, the user follows Votecaster and Vocdoni profiles on
-// Farcaster, the user has recasted the Votecaster Launch cast announcement,
-// the user has KIWI, the user has the DegenDAO NFT, the user has at least 10k
-// Degen, the user has Haberdashery NFT, the user has the TokyoDAO NFT, the user
-// has a Proxy, the user has at least 5 Proxies, the user has the ProxyStudio
-// NFT, and the user has the NameDegen NFT. It returns an error if the boosters
-// data cannot be fetched.
-func (u *Updater) userBoosters(user *dbmongo.User) *Boosters {
-	// create new boosters struct and slice for errors
-	boosters := &Boosters{}
-	// check if user is votecaster alphafrens follower, is vocdoni or votecaster
-	// farcaster follower, and if the user has recasted the votecaster launch
-	// cast announcement
+// userBoosters method checks which boosters of the registry the given user
+// has. It uses the cached followers and holders and, for the boosters that
+// require it, the Census3 API to check the balance of every user address. It
+// returns the boosters data as a Boosters map which includes every booster of
+// the registry.
+func (u *Updater) userBoosters(user *dbmongo.User) Boosters {
+	boosters := Boosters{}
+	// check the boosters that depend on the farcaster social graph
 	u.followersMtx.Lock()
 	defer u.followersMtx.Unlock()
-	boosters.IsVotecasterAlphafrensFollower = u.alfafrensFollowers[user.UserID]
-	boosters.IsVocdoniFarcasterFollower = u.vocdoniFollowers[user.UserID]
-	boosters.IsVotecasterFarcasterFollower = u.votecasterFollowers[user.UserID]
-	boosters.VotecasterAnnouncementRecasted = u.recasters[user.UserID]
-	// for every user address check every booster only if it is not already set
+	// for every user address check every token booster only if it is not
+	// already set
 	u.holdersMtx.Lock()
 	defer u.holdersMtx.Unlock()
-	for _, strAddr := range user.Addresses {
-		addr := common.HexToAddress(strAddr)
-		// check if user has votecaster nft pass
-		if !boosters.HasVotecasterNFTPass {
-			_, ok := u.votecasterNFTPassHolders[addr]
-			boosters.HasVotecasterNFTPass = ok
-		}
-		// check if user has votecaster launch nft
-		if !boosters.HasVotecasterLaunchNFT {
-			_, ok := u.votecasterLaunchNFTHolders[addr]
-			boosters.HasVotecasterLaunchNFT = ok
-		}
-		// check if user has KIWI
-		if !boosters.HasKIWI {
-			_, ok := u.kiwiHolders[addr]
-			boosters.HasKIWI = ok
-		}
-		// check if user has DegenDAO NFT
-		if !boosters.HasDegenDAONFT {
-			_, ok := u.degenDAONFTHolders[addr]
-			boosters.HasDegenDAONFT = ok
-		}
-		// check if user has Haberdashery NFT
-		if !boosters.HasHaberdasheryNFT {
-			_, ok := u.haberdasheryNFTHolders[addr]
-			boosters.HasHaberdasheryNFT = ok
-		}
-		// check if user has 10k Degen
-		if !boosters.Has10kDegenAtLeast {
-			balance, err := u.census3.TokenHolder(DegenAddress.Hex(), DegenChainID, "", addr.Hex())
-			if err != nil {
-				log.Warnw("error checking if user has 10k degen", "error", err, "user", user.UserID)
-			}
-			if balance != nil {
-				boosters.Has10kDegenAtLeast = balance.Cmp(big.NewInt(10000)) >= 0
-			}
-		}
-		// check if user has TokyoDAO NFT
-		if !boosters.HasTokyoDAONFT {
-			_, ok := u.tokyoDAONFTHolders[addr]
-			boosters.HasTokyoDAONFT = ok
+	for _, booster := range u.boosters.Boosters {
+		if !booster.IsToken() {
+			boosters[booster.ID] = u.followers[booster.ID][user.UserID]
+			continue
 		}
-		// check if user has Proxy and at least 5 Proxies
-		if !boosters.Has5ProxyAtLeast {
-			if balance, ok := u.proxyHolders[addr]; ok {
-				boosters.Has5ProxyAtLeast = balance.Cmp(big.NewInt(5)) >= 0
+		boosters[booster.ID] = false
+		for _, strAddr := range user.Addresses {
+			addr := common.HexToAddress(strAddr)
+			var balance *big.Int
+			if booster.CheckBalance {
+				var err error
+				balance, err = u.census3.TokenHolder(booster.Address, booster.ChainID, "", addr.Hex())
+				if err != nil {
+					log.Warnw("error checking booster balance", "error", err,
+						"booster", booster.ID, "user", user.UserID)
+				}
+			} else {
+				balance = u.holders[booster.ID][addr]
+			}
+			if booster.HasEnoughBalance(balance) {
+				boosters[booster.ID] = true
+				break
 			}
-		}
-		// check if user has ProxyStudio NFT
-		if !boosters.HasProxyStudioNFT {
-			_, ok := u.proxyStudioNFTHolders[addr]
-			boosters.HasProxyStudioNFT = ok
-		}
-		// check if user has NameDegen
-		if !boosters.HasNameDegen {
-			_, ok := u.nameDegenHolders[addr]
-			boosters.HasNameDegen = ok
-		}
-		// check if user has Farcaster OG NFT
-		if !boosters.HasFarcasterOGNFT {
-			_, ok := u.farcasterOGNFTHolders[addr]
-			boosters.HasFarcasterOGNFT = ok
-		}
-		// check if user has Moxie Pass
-		if !boosters.HasMoxiePass {
-			_, ok := u.moxiePassHolders[addr]
-			boosters.HasMoxiePass = ok
 		}
 	}
 	return boosters
@@ -5,13 +5,12 @@ import (
 	"fmt"
 	"math"
 	"math/big"
-	"net/http"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/vocdoni/vote-frame/airstack"
 	"github.com/vocdoni/vote-frame/communityhub"
+	"github.com/vocdoni/vote-frame/frameanalytics"
+	"github.com/vocdoni/vote-frame/frametemplates"
 	"github.com/vocdoni/vote-frame/helpers"
 	"github.com/vocdoni/vote-frame/imageframe"
 	"github.com/vocdoni/vote-frame/mongo"
@@ -32,12 +31,13 @@ func (v *vocdoniHandler) checkIfElectionFinishedAndHandle(electionID types.HexBy
 	if pngResults == nil {
 		return false
 	}
-	response := strings.ReplaceAll(frame(frameFinalResults), "{image}", imageLink(imageframe.AddImageToCache(pngResults)))
-	response = strings.ReplaceAll(response, "{processID}", electionID.String())
-	response = strings.ReplaceAll(response, "{title}", "Final results")
-
-	ctx.SetResponseContentType("text/html; charset=utf-8")
-	if err := ctx.Send([]byte(response), http.StatusOK); err != nil {
+	if err := v.sendFrame(ctx, electionID, frametemplates.FinalResults{
+		Page: frametemplates.Page{
+			Image: imageLink(imageframe.AddImageToCache(pngResults)),
+			Title: "Final results",
+		},
+		ProcessID: electionID.String(),
+	}); err != nil {
 		log.Warnw("failed to send response", "error", err)
 		return true
 	}
@@ -51,15 +51,19 @@ func (v *vocdoniHandler) results(msg *apirest.APIdata, ctx *httprouter.HTTPConte
 	}
 	log.Infow("received results request", "electionID", electionID)
 
-	// validate the frame package to airstack
-	if v.airstack != nil {
-		airstack.ValidateFrameMessage(msg.Data, v.airstack.ApiKey())
-	}
+	// report the frame message to the external validators
+	v.frames.Report(msg.Data)
 
 	electionIDbytes, err := hex.DecodeString(electionID)
 	if err != nil {
 		return errorImageResponse(ctx, fmt.Errorf("failed to decode electionID: %w", err))
 	}
+	// errorResponse records the error as a frame event and sends it as an
+	// image
+	errorResponse := func(err error) error {
+		v.analytics.Record(electionIDbytes, frameanalytics.FrameResults, frameanalytics.EventError)
+		return errorImageResponse(ctx, err)
+	}
 	// check if the election is finished and if so, send the final results as a static PNG
 	if v.checkIfElectionFinishedAndHandle(electionIDbytes, ctx) {
 		return nil
@@ -68,11 +72,11 @@ func (v *vocdoniHandler) results(msg *apirest.APIdata, ctx *httprouter.HTTPConte
 	// get the election from the vochain and create a PNG image with the results
 	election, err := v.cli.Election(electionIDbytes)
 	if err != nil {
-		return errorImageResponse(ctx, fmt.Errorf("failed to fetch election: %w", err))
+		return errorResponse(fmt.Errorf("failed to fetch election: %w", err))
 	}
 	metadata := helpers.UnpackMetadata(election.Metadata)
 	if len(election.Results) == 0 {
-		return errorImageResponse(ctx, fmt.Errorf("election results not ready"))
+		return errorResponse(fmt.Errorf("election results not ready"))
 	}
 
 	electiondb, err := v.db.Election(electionIDbytes)
@@ -84,14 +88,15 @@ func (v *vocdoniHandler) results(msg *apirest.APIdata, ctx *httprouter.HTTPConte
 	if election.FinalResults {
 		id, err := v.finalizeElectionResults(election, electiondb)
 		if err != nil {
-			return errorImageResponse(ctx, fmt.Errorf("failed to create final results: %w", err))
+			return errorResponse(fmt.Errorf("failed to create final results: %w", err))
 		}
-		response := strings.ReplaceAll(frame(frameFinalResults), "{image}", imageLink(id))
-		response = strings.ReplaceAll(response, "{processID}", electionID)
-		response = strings.ReplaceAll(response, "{title}", "Final results")
-
-		ctx.SetResponseContentType("text/html; charset=utf-8")
-		return ctx.Send([]byte(response), http.StatusOK)
+		return v.sendFrame(ctx, electionIDbytes, frametemplates.FinalResults{
+			Page: frametemplates.Page{
+				Image: imageLink(id),
+				Title: "Final results",
+			},
+			ProcessID: electionID,
+		})
 	} else {
 		_, err := v.updateAndFetchResultsFromDatabase(electionIDbytes, election)
 		if err != nil {
@@ -106,11 +111,13 @@ func (v *vocdoniHandler) results(msg *apirest.APIdata, ctx *httprouter.HTTPConte
 	}
 
 	// if not final results, create the dynamic PNG image with the results
-	response := strings.ReplaceAll(frame(frameResults), "{image}", resultsPNGfile(election, electiondb, totalWeightStr))
-	response = strings.ReplaceAll(response, "{title}", metadata.Title["default"])
-	response = strings.ReplaceAll(response, "{processID}", electionID)
-	ctx.SetResponseContentType("text/html; charset=utf-8")
-	return ctx.Send([]byte(response), http.StatusOK)
+	return v.sendFrame(ctx, electionIDbytes, frametemplates.Results{
+		Page: frametemplates.Page{
+			Image: resultsPNGfile(election, electiondb, totalWeightStr),
+			Title: metadata.Title["default"],
+		},
+		ProcessID: electionID,
+	})
 }
 
 // finalizeElectionResults creates the final results image and stores it in the database.
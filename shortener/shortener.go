@@ -6,13 +6,26 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
 )
 
 const (
-	shortenerTimeout  = 10 * time.Second
-	shortenerBase     = "https://frame.vote/"
-	shortenerEndpoint = shortenerBase + "add/%s"
+	shortenerTimeout      = 10 * time.Second
+	shortenerEndpoint     = "add/%s"
+	resolvedURLsCacheSize = 4096
+	defaultShortenerBase  = "https://frame.vote/"
+	maxShortCodeLength    = 64
+)
+
+var (
+	// shortenerBase is the base URL of the short URLs of the shortener service
+	shortenerBase = defaultShortenerBase
+	// resolvedURLs caches the URLs that the short URLs redirect to, since
+	// they never change
+	resolvedURLs, _ = lru.New[string, string](resolvedURLsCacheSize)
 )
 
 // ShortURL returns a shortened version of the provided URL. It uses the
@@ -33,7 +46,7 @@ func ShortURL(ctx context.Context, electionURL string) (string, error) {
 
 	internalCtx, cancel := context.WithTimeout(ctx, shortenerTimeout)
 	defer cancel()
-	endpointURL := fmt.Sprintf(shortenerEndpoint, urlToShort)
+	endpointURL := shortenerBase + fmt.Sprintf(shortenerEndpoint, urlToShort)
 	req, err := http.NewRequestWithContext(internalCtx, http.MethodGet, endpointURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
@@ -54,3 +67,54 @@ func ShortURL(ctx context.Context, electionURL string) (string, error) {
 	}
 	return shortenerBase + shortenerResponse.Link, nil
 }
+
+// IsShortURL returns if the URL provided is a short URL of the vocdoni
+// shortener service.
+func IsShortURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	base, err := url.Parse(shortenerBase)
+	if err != nil || !strings.EqualFold(u.Host, base.Host) {
+		return false
+	}
+	code := strings.TrimPrefix(u.Path, "/")
+	return code != "" && len(code) <= maxShortCodeLength && !strings.Contains(code, "/")
+}
+
+// Resolve returns the URL that the short URL provided redirects to, without
+// following it. The resolved URLs are cached. It returns an error if the URL
+// is not a short URL of the vocdoni shortener service or if it does not
+// redirect anywhere.
+func Resolve(ctx context.Context, shortURL string) (string, error) {
+	if !IsShortURL(shortURL) {
+		return "", fmt.Errorf("not a short URL: %s", shortURL)
+	}
+	if target, ok := resolvedURLs.Get(shortURL); ok {
+		return target, nil
+	}
+	internalCtx, cancel := context.WithTimeout(ctx, shortenerTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(internalCtx, http.MethodGet, shortURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+	location, err := res.Location()
+	if err != nil {
+		return "", fmt.Errorf("short URL does not redirect: %s", res.Status)
+	}
+	target := location.String()
+	resolvedURLs.Add(shortURL, target)
+	return target, nil
+}
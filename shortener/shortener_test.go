@@ -0,0 +1,56 @@
+package shortener
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+)
+
+func TestResolve(t *testing.T) {
+	c := qt.New(t)
+	ctx := context.Background()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/abc":
+			http.Redirect(w, r, "https://farcaster.vote/0xa1b2", http.StatusFound)
+		case "/relative":
+			// the shortener redirects to the URLs shortened without protocol
+			w.Header().Set("Location", "farcaster.vote/0xc3d4")
+			w.WriteHeader(http.StatusMovedPermanently)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	shortenerBase = server.URL + "/"
+	defer func() { shortenerBase = defaultShortenerBase }()
+
+	c.Assert(IsShortURL(server.URL+"/abc"), qt.IsTrue)
+	c.Assert(IsShortURL(server.URL+"/"), qt.IsFalse)
+	c.Assert(IsShortURL(server.URL+"/abc/def"), qt.IsFalse)
+	c.Assert(IsShortURL("https://farcaster.vote/abc"), qt.IsFalse)
+
+	target, err := Resolve(ctx, server.URL+"/abc")
+	c.Assert(err, qt.IsNil)
+	c.Assert(target, qt.Equals, "https://farcaster.vote/0xa1b2")
+	// the resolved URLs are cached
+	target, err = Resolve(ctx, server.URL+"/abc")
+	c.Assert(err, qt.IsNil)
+	c.Assert(target, qt.Equals, "https://farcaster.vote/0xa1b2")
+	c.Assert(requests, qt.Equals, 1)
+
+	target, err = Resolve(ctx, server.URL+"/relative")
+	c.Assert(err, qt.IsNil)
+	c.Assert(target, qt.Equals, server.URL+"/farcaster.vote/0xc3d4")
+
+	_, err = Resolve(ctx, server.URL+"/unknown")
+	c.Assert(err, qt.IsNotNil)
+	_, err = Resolve(ctx, "https://farcaster.vote/abc")
+	c.Assert(err, qt.IsNotNil)
+}
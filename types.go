@@ -3,6 +3,7 @@ package main
 import (
 	"time"
 
+	"github.com/vocdoni/vote-frame/features"
 	"github.com/vocdoni/vote-frame/mongo"
 	"github.com/vocdoni/vote-frame/reputation"
 )
@@ -11,16 +12,22 @@ const (
 	maxElectionDuration = 24 * time.Hour * 15
 	minPaginatedItems   = int64(1)
 	maxPaginatedItems   = int64(100)
+	// reputation history time windows
+	defaultReputationHistoryDays = 30
+	maxReputationHistoryDays     = 365
+	reputationTrendDays          = 7
 )
 
 // VotecasterProfile is the profile of a votecaster user.
 type VotecasterProfile struct {
-	User               *mongo.User             `json:"user"`
-	Polls              []mongo.ElectionRanking `json:"polls"`
-	MutedUsers         []*mongo.User           `json:"mutedUsers"`
-	Delegations        []*mongo.Delegation     `json:"delegations"`
-	Reputation         reputation.Reputation   `json:"reputation"`
-	WarpcastAPIEnabled bool                    `json:"warpcastApiEnabled"`
+	User               *mongo.User                   `json:"user"`
+	Polls              []mongo.ElectionRanking       `json:"polls"`
+	MutedUsers         []*mongo.User                 `json:"mutedUsers"`
+	Delegations        []*mongo.Delegation           `json:"delegations"`
+	Reputation         reputation.Reputation         `json:"reputation"`
+	ReputationTrend    *reputation.ReputationHistory `json:"reputationTrend,omitempty"`
+	Features           []*features.FeatureStatus     `json:"features"`
+	WarpcastAPIEnabled bool                          `json:"warpcastApiEnabled"`
 }
 
 // FarcasterProfile is the profile of a farcaster user.
@@ -49,14 +56,18 @@ type ElectionCreateRequest struct {
 	CommunityID      *string           `json:"community,omitempty"`
 }
 
-// ElectionDescription defines the parameters for a new election.
+// ElectionDescription defines the parameters for a new election. If Overwrite
+// is set, the voters can change their vote MaxOverwrites times, or
+// helpers.DefaultVoteOverwrites times if it is zero.
 type ElectionDescription struct {
 	Question          string        `json:"question"`
 	Options           []string      `json:"options"`
 	Duration          time.Duration `json:"duration"`
 	Overwrite         bool          `json:"overwrite"`
+	MaxOverwrites     uint32        `json:"maxOverwrites,omitempty"`
 	UsersCount        uint32        `json:"usersCount"`
 	UsersCountInitial uint32        `json:"usersCountInitial"`
+	Protocols         []string      `json:"protocols,omitempty"`
 }
 
 // ElectionInfo defines the full details for an election, used by the API.
@@ -266,6 +277,16 @@ type AnnouncementStatus struct {
 	Error       string            `json:"error,omitempty"`
 }
 
+// FeatureThresholdRequest defines the parameters to tune the reputation
+// threshold of a feature. If the community ID is provided, the threshold
+// overrides the global one only for that community. If the reputation is not
+// provided, the override of the community is removed.
+type FeatureThresholdRequest struct {
+	Feature     string  `json:"feature"`
+	CommunityID string  `json:"communityID"`
+	Reputation  *uint32 `json:"reputation"`
+}
+
 // ComposerActionResponse is the response of the composer endpoint, which is a
 // redirection to the composer app to be used to create a new election from the
 // cast form in warpcast.
@@ -287,3 +308,17 @@ type ComposerActionMetadata struct {
 		Type string `json:"type"`
 	} `json:"action"`
 }
+
+// VoteReceipt is the receipt of a vote included in the vochain, with the
+// block and the transaction that include it, the weight of the vote and the
+// link to its shareable receipt image.
+type VoteReceipt struct {
+	ElectionID  string     `json:"electionId"`
+	Nullifier   string     `json:"nullifier"`
+	BlockHeight uint32     `json:"blockHeight"`
+	TxHash      string     `json:"txHash"`
+	Weight      string     `json:"weight"`
+	Date        *time.Time `json:"date,omitempty"`
+	Image       string     `json:"image"`
+	ExplorerURL string     `json:"explorerURL"`
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/vocdoni/vote-frame/features"
 	"github.com/vocdoni/vote-frame/mongo"
 	"github.com/vocdoni/vote-frame/reputation"
 	"go.vocdoni.io/dvote/httprouter"
@@ -61,7 +63,16 @@ func (v *vocdoniHandler) profileHandler(msg *apirest.APIdata, ctx *httprouter.HT
 	if err != nil && !errors.Is(err, mongo.ErrUserUnknown) || rep == nil {
 		log.Warnw("could not get user reputation", "error", err)
 	} else {
-		profile.Reputation = *reputation.ReputationToAPIResponse(rep)
+		profile.Reputation = *reputation.ReputationToAPIResponse(rep, v.repUpdater.Boosters())
+	}
+	// list the features unlocked and locked by the user reputation, using
+	// the overrides of the community provided, if any
+	communityID := ctx.Request.URL.Query().Get("community")
+	profile.Features = features.DefaultRegistry.Status(communityID, accessprofile.Reputation)
+	// get a short trend of the user reputation
+	since := time.Now().Add(-reputationTrendDays * 24 * time.Hour)
+	if profile.ReputationTrend, err = v.repUpdater.UserReputationHistory(auth.UserID, since, 0); err != nil {
+		log.Warnw("could not get user reputation trend", "error", err)
 	}
 	// Marshal the response
 	data, err := json.Marshal(profile)
@@ -163,41 +174,78 @@ func (v *vocdoniHandler) delegateVoteHandler(msg *apirest.APIdata, ctx *httprout
 		return ctx.Send([]byte("missing required fields"), apirest.HTTPstatusBadRequest)
 	}
 	req.From = userFID
+	if err := v.delegateVote(req); err != nil {
+		if isInvalidDelegation(err) {
+			return ctx.Send([]byte(err.Error()), apirest.HTTPstatusBadRequest)
+		}
+		log.Warnw("failed to delegate vote", "from", req.From, "to", req.To, "error", err)
+		return ctx.Send([]byte("could not delegate vote"), apirest.HTTPstatusInternalErr)
+	}
+	return ctx.Send([]byte("Ok"), apirest.HTTPstatusOK)
+}
+
+var (
+	errDelegationToYourself = fmt.Errorf("cannot delegate to yourself")
+	errVoteAlreadyDelegated = fmt.Errorf("vote already delegated")
+	errCircularDelegation   = fmt.Errorf("circular delegation")
+	errDelegateUnknown      = fmt.Errorf("user to delegate to not found")
+	errCommunityUnknown     = fmt.Errorf("community to delegate in not found")
+)
+
+// isInvalidDelegation returns if the error provided, returned by delegateVote,
+// is caused by an invalid delegation, so its message can be sent to the user.
+func isInvalidDelegation(err error) bool {
+	return errors.Is(err, errDelegationToYourself) || errors.Is(err, errVoteAlreadyDelegated) ||
+		errors.Is(err, errCircularDelegation) || errors.Is(err, errDelegateUnknown) ||
+		errors.Is(err, errCommunityUnknown)
+}
+
+// delegateVote stores the delegation provided after checking that it is valid,
+// that is, it is not to the delegator themselves, both the delegate and the
+// community exist, and the delegator has not delegated yet in the community
+// nor the delegation is circular. The errors of the invalid delegations can be
+// identified with isInvalidDelegation, the rest are internal errors.
+func (v *vocdoniHandler) delegateVote(delegation mongo.Delegation) error {
 	// check if the user is trying to delegate to themselves
-	if req.From == req.To {
-		return ctx.Send([]byte("cannot delegate to yourself"), apirest.HTTPstatusBadRequest)
+	if delegation.From == delegation.To {
+		return errDelegationToYourself
 	}
 	// check if the user is trying to delegate to a non-existing user
-	_, err = v.db.User(req.To)
-	if err != nil {
-		return ctx.Send([]byte("failed to get user to delegate to"), apirest.HTTPstatusInternalErr)
+	if _, err := v.db.User(delegation.To); err != nil {
+		if errors.Is(err, mongo.ErrUserUnknown) {
+			return errDelegateUnknown
+		}
+		return fmt.Errorf("failed to get user to delegate to: %w", err)
 	}
 	// check if the user is trying to delegate to a non-existing community
-	_, err = v.db.Community(req.CommuniyID)
+	community, err := v.db.Community(delegation.CommuniyID)
 	if err != nil {
-		return ctx.Send([]byte("failed to get community to delegate to"), apirest.HTTPstatusInternalErr)
+		return fmt.Errorf("failed to get community to delegate to: %w", err)
+	}
+	if community == nil {
+		return errCommunityUnknown
 	}
 	// get current delegations for the community to prevent circular delegations
-	delegations, err := v.db.DelegationsByCommunity(req.CommuniyID, true, false)
+	delegations, err := v.db.DelegationsByCommunity(delegation.CommuniyID, true, false)
 	if err != nil {
-		return ctx.Send([]byte("could not get delegations"), apirest.HTTPstatusInternalErr)
+		return fmt.Errorf("could not get delegations: %w", err)
 	}
 	// check if the delegation would create a circular delegation
-	for _, delegation := range delegations {
+	for _, current := range delegations {
 		// prevent duplicated and overwrite delegations
-		if delegation.From == req.From {
-			return ctx.Send([]byte("vote already delegated"), apirest.HTTPstatusBadRequest)
+		if current.From == delegation.From {
+			return errVoteAlreadyDelegated
 		}
 		// prevent circular delegation
-		if delegation.From == req.To && delegation.To == req.From {
-			return ctx.Send([]byte("circular delegation"), apirest.HTTPstatusBadRequest)
+		if current.From == delegation.To && current.To == delegation.From {
+			return errCircularDelegation
 		}
 	}
 	// delegate the vote
-	if _, err := v.db.SetDelegation(req); err != nil {
-		return ctx.Send([]byte("could not delegate vote"), apirest.HTTPstatusInternalErr)
+	if _, err := v.db.SetDelegation(delegation); err != nil {
+		return fmt.Errorf("could not delegate vote: %w", err)
 	}
-	return ctx.Send([]byte("Ok"), apirest.HTTPstatusOK)
+	return nil
 }
 
 func (v *vocdoniHandler) removeVoteDelegationHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
@@ -289,6 +337,65 @@ func (v *vocdoniHandler) profilePublicHandler(msg *apirest.APIdata, ctx *httprou
 	return ctx.Send(data, apirest.HTTPstatusOK)
 }
 
+// userReputationHistoryHandler returns the reputation history of the user
+// with the FID provided in the URL. The time window can be set with the 'days'
+// query parameter.
+func (v *vocdoniHandler) userReputationHistoryHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	fid, err := strconv.ParseUint(ctx.URLParam("fid"), 10, 64)
+	if err != nil {
+		return ctx.Send([]byte("invalid fid"), apirest.HTTPstatusBadRequest)
+	}
+	since, err := reputationHistorySince(ctx)
+	if err != nil {
+		return ctx.Send([]byte(err.Error()), apirest.HTTPstatusBadRequest)
+	}
+	history, err := v.repUpdater.UserReputationHistory(fid, since, 0)
+	if err != nil {
+		return fmt.Errorf("could not get user reputation history: %v", err)
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("could not marshal response: %v", err)
+	}
+	return ctx.Send(data, apirest.HTTPstatusOK)
+}
+
+// userReputationImprovementsHandler returns the ranked list of actions that
+// the user with the FID provided in the URL can do to improve their
+// reputation, including the missing boosters and the next activity thresholds.
+func (v *vocdoniHandler) userReputationImprovementsHandler(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
+	fid, err := strconv.ParseUint(ctx.URLParam("fid"), 10, 64)
+	if err != nil {
+		return ctx.Send([]byte("invalid fid"), apirest.HTTPstatusBadRequest)
+	}
+	improvements, err := v.repUpdater.ReputationImprovements(fid)
+	if err != nil {
+		if errors.Is(err, mongo.ErrUserUnknown) {
+			return ctx.Send([]byte("user not found"), apirest.HTTPstatusNotFound)
+		}
+		return fmt.Errorf("could not get user reputation improvements: %v", err)
+	}
+	data, err := json.Marshal(map[string]any{"improvements": improvements})
+	if err != nil {
+		return fmt.Errorf("could not marshal response: %v", err)
+	}
+	return ctx.Send(data, apirest.HTTPstatusOK)
+}
+
+// reputationHistorySince returns the start of the reputation history time
+// window based on the 'days' query parameter. If it is not provided, the
+// default number of days is used.
+func reputationHistorySince(ctx *httprouter.HTTPContext) (time.Time, error) {
+	days := defaultReputationHistoryDays
+	if strDays := ctx.Request.URL.Query().Get("days"); strDays != "" {
+		var err error
+		if days, err = strconv.Atoi(strDays); err != nil || days < 1 || days > maxReputationHistoryDays {
+			return time.Time{}, fmt.Errorf("invalid days, it must be between 1 and %d", maxReputationHistoryDays)
+		}
+	}
+	return time.Now().Add(-time.Duration(days) * 24 * time.Hour), nil
+}
+
 func (v *vocdoniHandler) registerWarpcastApiKey(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
 	token := msg.AuthToken
 	if token == "" {
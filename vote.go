@@ -3,18 +3,19 @@ package main
 import (
 	"crypto/ed25519"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
-	"github.com/vocdoni/vote-frame/airstack"
+	"github.com/vocdoni/vote-frame/frameanalytics"
+	"github.com/vocdoni/vote-frame/frametemplates"
+	"github.com/vocdoni/vote-frame/framevalidator"
 	"github.com/vocdoni/vote-frame/helpers"
 	"github.com/vocdoni/vote-frame/imageframe"
 	"go.vocdoni.io/proto/build/go/models"
 
+	"go.vocdoni.io/dvote/api"
 	"go.vocdoni.io/dvote/apiclient"
 	"go.vocdoni.io/dvote/httprouter"
 	"go.vocdoni.io/dvote/httprouter/apirest"
@@ -34,13 +35,26 @@ var (
 	ErrFrameSignature = fmt.Errorf("frame signature verification failed")
 )
 
-// voteData contains the data needed to cast a vote.
+// voteData contains the data needed to cast a vote. If Voted is true, the
+// voter already voted and the vote overwrites their previous vote, which has
+// been overwritten Overwrites times.
 type voteData struct {
-	Nullifier types.HexBytes
-	VoterID   state.VoterID
-	FID       uint64
-	Proof     *apiclient.CensusProof
-	PubKey    ed25519.PublicKey
+	Nullifier  types.HexBytes
+	VoterID    state.VoterID
+	FID        uint64
+	Proof      *apiclient.CensusProof
+	PubKey     ed25519.PublicKey
+	Voted      bool
+	Overwrites uint32
+}
+
+// maxVoteOverwrites returns the number of times that a voter can change their
+// vote in the election provided.
+func maxVoteOverwrites(election *api.Election) uint32 {
+	if election == nil {
+		return 0
+	}
+	return election.TallyMode.GetMaxVoteOverwrites()
 }
 
 func (v *vocdoniHandler) vote(msg *apirest.APIdata, ctx *httprouter.HTTPContext) error {
@@ -63,29 +77,53 @@ func (v *vocdoniHandler) vote(msg *apirest.APIdata, ctx *httprouter.HTTPContext)
 		if err2 != nil {
 			return fmt.Errorf("failed to create image: %w", err2)
 		}
-		response := strings.ReplaceAll(frame(frameError), "{image}", imageLink(png))
-		response = strings.ReplaceAll(response, "{title}", metadata.Title["default"])
-		response = strings.ReplaceAll(response, "{processID}", electionID)
-		ctx.SetResponseContentType("text/html; charset=utf-8")
-		return ctx.Send([]byte(response), http.StatusOK)
+		return v.sendFrame(ctx, electionIDbytes, frametemplates.Error{
+			Page: frametemplates.Page{
+				Image: imageLink(png),
+				Title: metadata.Title["default"],
+			},
+			ProcessID: electionID,
+		})
 	}
 
 	if election.FinalResults {
-		response := strings.ReplaceAll(frame(frameError), "{image}", imageLink(imageframe.NotFoundImage()))
-		response = strings.ReplaceAll(response, "{title}", metadata.Title["default"])
-		response = strings.ReplaceAll(response, "{processID}", electionID)
-		ctx.SetResponseContentType("text/html; charset=utf-8")
-		return ctx.Send([]byte(response), http.StatusOK)
+		return v.sendFrame(ctx, electionIDbytes, frametemplates.Error{
+			Page: frametemplates.Page{
+				Image: imageLink(imageframe.NotFoundImage()),
+				Title: metadata.Title["default"],
+			},
+			ProcessID: electionID,
+		})
 	}
 
-	packet := &FrameSignaturePacket{}
-	if err := json.Unmarshal(msg.Data, packet); err != nil {
-		return fmt.Errorf("failed to unmarshal frame signature packet: %w", err)
+	// the votes of the Open Frames clients cannot be proven on the vochain,
+	// so the eligibility of the signer is checked to show the right frame,
+	// but the vote is rejected
+	if isOpenFrame(msg.Data) {
+		vote, err := v.openFrameVoteData(msg.Data, electionIDbytes, election.Census.CensusRoot,
+			maxVoteOverwrites(election), true)
+		if err == nil {
+			err = ErrOpenFrameVote
+		}
+		response, err := v.handleVoteError(err, vote, electionIDbytes, frameanalytics.FrameVote)
+		if response == nil {
+			return err
+		}
+		ctx.SetResponseContentType("text/html; charset=utf-8")
+		return ctx.Send(response, http.StatusOK)
 	}
 
-	// validate the frame package to airstack
-	if v.airstack != nil {
-		airstack.ValidateFrameMessage(msg.Data, v.airstack.ApiKey())
+	// validate the frame message and check it was signed recently for this
+	// election
+	frameMsg, err := v.frames.Validate(msg.Data)
+	if err == nil {
+		err = checkFrameMessage(frameMsg, electionIDbytes, true)
+	}
+	if err != nil {
+		if response, err := v.handleVoteError(err, nil, electionIDbytes, frameanalytics.FrameVote); err != nil {
+			ctx.SetResponseContentType("text/html; charset=utf-8")
+			return ctx.Send(response, http.StatusOK)
+		}
 	}
 
 	// get the vote count for future check
@@ -95,42 +133,68 @@ func (v *vocdoniHandler) vote(msg *apirest.APIdata, ctx *httprouter.HTTPContext)
 	}
 
 	// cast the vote
-	vote, voteErr := vote(packet, electionIDbytes, election.Census.CensusRoot, v.cli)
+	vote, voteErr := vote(frameMsg, electionIDbytes, election.Census.CensusRoot, maxVoteOverwrites(election), v.cli)
 	if voteErr != nil {
 		// check if the user has delegated their vote, if so, return an error
-		dbElection, _ := v.db.Election(electionIDbytes)
-		if dbElection != nil && dbElection.Community != nil {
-			delegations, err := v.db.DelegationsByCommunityFrom(dbElection.Community.ID, uint64(packet.UntrustedData.FID), true)
-			if err != nil {
-				log.Warnw("failed to fetch delegations", "error", err)
-			}
-			if len(delegations) > 0 {
-				if response, err := handleVoteError(ErrVoteDelegated, vote, electionIDbytes); err != nil {
-					ctx.SetResponseContentType("text/html; charset=utf-8")
-					return ctx.Send([]byte(response), http.StatusOK)
-				}
+		if v.voteDelegated(electionIDbytes, frameMsg.FID) {
+			if response, err := v.handleVoteError(ErrVoteDelegated, vote, electionIDbytes, frameanalytics.FrameVote); err != nil {
+				ctx.SetResponseContentType("text/html; charset=utf-8")
+				return ctx.Send(response, http.StatusOK)
 			}
 		}
 
 		// handle the error (if any)
-		if response, err := handleVoteError(voteErr, vote, electionIDbytes); err != nil {
+		if response, err := v.handleVoteError(voteErr, vote, electionIDbytes, frameanalytics.FrameVote); err != nil {
 			ctx.SetResponseContentType("text/html; charset=utf-8")
-			return ctx.Send([]byte(response), http.StatusOK)
+			return ctx.Send(response, http.StatusOK)
 		}
 	}
 
+	v.recordVote(vote, electionIDbytes, election.Census.CensusRoot, voteCount)
+
+	// wait some time so the vote is processed and the results are updated
+	time.Sleep(2 * time.Second)
+
+	return v.sendFrame(ctx, electionIDbytes, frametemplates.AfterVote{
+		Page: frametemplates.Page{
+			Image: imageLink(imageframe.AfterVoteImage()),
+			Title: metadata.Title["default"],
+		},
+		ProcessID: electionID,
+		Nullifier: fmt.Sprintf("%x", vote.Nullifier),
+	})
+}
+
+// voteDelegated returns true if the user of the FID provided has delegated
+// their vote in the community of the election provided.
+func (v *vocdoniHandler) voteDelegated(electionID types.HexBytes, fid uint64) bool {
+	dbElection, _ := v.db.Election(electionID)
+	if dbElection == nil || dbElection.Community == nil {
+		return false
+	}
+	delegations, err := v.db.DelegationsByCommunityFrom(dbElection.Community.ID, fid, true)
+	if err != nil {
+		log.Warnw("failed to fetch delegations", "error", err)
+	}
+	return len(delegations) > 0
+}
+
+// recordVote updates the voter and the participation stats of the vote
+// provided in the background. It also refreshes the results of the election
+// once the vote count of the election increases over the count provided.
+func (v *vocdoniHandler) recordVote(vote *voteData, electionID types.HexBytes, censusRoot []byte, voteCount uint32) {
 	go func() {
 		if !v.db.UserExists(vote.FID) {
 			if err := v.db.AddUser(vote.FID, "", "", []string{}, []string{}, "", 0); err != nil {
 				log.Errorw(err, "failed to add user to database")
 			}
 		}
-		participation, err := v.db.ParticipantParticipation(election.Census.CensusRoot, vote.FID)
+		participation, err := v.db.ParticipantParticipation(censusRoot, vote.FID)
 		if err != nil {
 			log.Errorw(err, "could not get participant participation from database, fallback to 1")
 			participation = 1
 		}
-		if err := v.db.IncreaseVoteCount(vote.FID, electionIDbytes, vote.Proof.LeafWeight, participation); err != nil {
+		if err := v.db.IncreaseVoteCount(vote.FID, electionID, vote.Proof.LeafWeight, participation); err != nil {
 			log.Errorw(err, "failed to increase vote count")
 		}
 
@@ -139,45 +203,34 @@ func (v *vocdoniHandler) vote(msg *apirest.APIdata, ctx *httprouter.HTTPContext)
 		// TODO: check this is actually useful to increase the cache hit rate
 		for i := 0; i < 10; i++ {
 			time.Sleep(1 * time.Second)
-			c, err := v.cli.ElectionVoteCount(electionIDbytes)
+			c, err := v.cli.ElectionVoteCount(electionID)
 			if err != nil {
 				log.Warnw("failed to fetch vote count", "error", err)
 			}
 			if c > voteCount {
-				election, err := v.election(electionIDbytes)
+				election, err := v.election(electionID)
 				if err != nil {
 					log.Warnw("failed to fetch election", "error", err)
 					break
 				}
-				_, err = v.updateAndFetchResultsFromDatabase(electionIDbytes, election)
+				_, err = v.updateAndFetchResultsFromDatabase(electionID, election)
 				if err != nil {
 					log.Warnw("failed to update results", "error", err)
 				}
 			}
 		}
 	}()
-
-	// wait some time so the vote is processed and the results are updated
-	time.Sleep(2 * time.Second)
-
-	response := strings.ReplaceAll(frame(frameAfterVote), "{nullifier}", fmt.Sprintf("%x", vote.Nullifier))
-	response = strings.ReplaceAll(response, "{title}", metadata.Title["default"])
-	response = strings.ReplaceAll(response, "{processID}", electionID)
-	png := imageframe.AfterVoteImage()
-	response = strings.ReplaceAll(response, "{image}", imageLink(png))
-	ctx.SetResponseContentType("text/html; charset=utf-8")
-	return ctx.Send([]byte(response), http.StatusOK)
 }
 
-func extractVoteDataAndCheckIfEligible(packet *FrameSignaturePacket, electionID types.HexBytes, root []byte, cli *apiclient.HTTPclient) (*voteData, error) {
-	messageBytes, err := hex.DecodeString(packet.TrustedData.MessageBytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode message bytes: %w", err)
-	}
-	actionMessage, pubKey, fid, err := farcasterproof.VerifyFrameSignature(messageBytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to verify frame signature: %w", err)
-	}
+// extractVoteDataAndCheckIfEligible computes the vote data of the voter of the
+// validated frame message provided and checks if they are eligible to vote in
+// the election provided, that is, if they are in the census and they have not
+// voted yet or they can still overwrite their vote, up to the maximum number of
+// overwrites provided.
+func extractVoteDataAndCheckIfEligible(frameMsg *framevalidator.Result, electionID types.HexBytes, root []byte,
+	maxOverwrites uint32, cli *apiclient.HTTPclient,
+) (*voteData, error) {
+	pubKey, fid := frameMsg.PubKey, frameMsg.FID
 
 	// compute the voterID, based on the public key
 	voterID := state.NewFarcasterVoterID(pubKey, fid)
@@ -187,11 +240,25 @@ func extractVoteDataAndCheckIfEligible(packet *FrameSignaturePacket, electionID
 		"voterID", fmt.Sprintf("%x", voterID.Address()),
 		"fid", fid,
 		"pubkey", fmt.Sprintf("%x", pubKey),
-		"button", actionMessage.ButtonIndex,
-		"url", string(actionMessage.Url),
-		"state", string(actionMessage.State),
+		"button", frameMsg.ButtonIndex,
+		"url", frameMsg.URL,
+		"state", string(frameMsg.State),
 	)
 
+	return voterEligibility(pubKey, fid, electionID, root, maxOverwrites, cli)
+}
+
+// voterEligibility computes the vote data of the voter of the app key and the
+// FID provided and checks if they are eligible to vote in the election
+// provided, that is, if they are in the census and they have not voted yet or
+// their vote has been overwritten less than the maximum number of overwrites
+// provided.
+func voterEligibility(pubKey ed25519.PublicKey, fid uint64, electionID types.HexBytes, root []byte,
+	maxOverwrites uint32, cli *apiclient.HTTPclient,
+) (*voteData, error) {
+	// compute the voterID, based on the public key
+	voterID := state.NewFarcasterVoterID(pubKey, fid)
+
 	// compute the nullifier for the vote (a hash of the voterID and the electionID)
 	nullifier := farcasterproof.GenerateNullifier(fid, electionID)
 
@@ -204,17 +271,33 @@ func extractVoteDataAndCheckIfEligible(packet *FrameSignaturePacket, electionID
 	}
 
 	// check if the voter is elegible to vote (in the census)
-	data.Proof, err = cli.CensusGenProof(root, voterID.Address())
+	proof, err := cli.CensusGenProof(root, voterID.Address())
 	if err != nil {
 		return data, ErrNotInCensus
 	}
+	data.Proof = proof
 
 	// check if the voter already voted
-	_, code, err := cli.Request("GET", nil, "votes", "verify", electionID.String(), fmt.Sprintf("%x", nullifier))
+	voted, err := voteIncluded(cli, electionID, nullifier)
 	if err != nil {
 		return data, fmt.Errorf("could not verify vote: %w", err)
 	}
-	if code == http.StatusOK {
+	if !voted {
+		return data, nil
+	}
+	if maxOverwrites == 0 {
+		return data, ErrAlreadyVoted
+	}
+	// check if the voter can still change their vote
+	vote, err := voteByNullifier(cli, nullifier)
+	if err != nil {
+		return data, fmt.Errorf("could not verify vote: %w", err)
+	}
+	data.Voted = true
+	if vote.OverwriteCount != nil {
+		data.Overwrites = *vote.OverwriteCount
+	}
+	if !helpers.CanOverwriteVote(data.Overwrites, maxOverwrites) {
 		return data, ErrAlreadyVoted
 	}
 	return data, nil
@@ -223,7 +306,28 @@ func extractVoteDataAndCheckIfEligible(packet *FrameSignaturePacket, electionID
 // handleVoteError handles the error returned by the extractVoteDataAndCheckIfEligible function.
 // Returns nil if the error is nil, otherwise returns the HTTP error message and the error itself.
 // The error message is a HTML page with an image and a message that can be displayed to the user.
-func handleVoteError(err error, voteData *voteData, electionID types.HexBytes) ([]byte, error) {
+// The error is recorded as an event of the frame provided.
+func (v *vocdoniHandler) handleVoteError(err error, voteData *voteData, electionID types.HexBytes,
+	frame frameanalytics.Frame,
+) ([]byte, error) {
+	if err != nil {
+		v.analytics.Record(electionID, frame, voteErrorEvent(err))
+	}
+	// errorFrame renders the error frame of the kind provided and returns it
+	// with the error provided
+	errorFrame := func(kind frametemplates.ErrorKind, png, nullifier string, err error) ([]byte, error) {
+		response, renderErr := v.renderFrame(electionID, frametemplates.Error{
+			Page:      frametemplates.Page{Image: imageLink(png)},
+			Kind:      kind,
+			ProcessID: electionID.String(),
+			Nullifier: nullifier,
+		})
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		return response, err
+	}
+
 	// handle the vote result
 	if errors.Is(err, ErrNotInCensus) {
 		log.Debugw("participant not in the census",
@@ -233,9 +337,7 @@ func handleVoteError(err error, voteData *voteData, electionID types.HexBytes) (
 			"voterID", fmt.Sprintf("%x", voteData.VoterID.Address()),
 		)
 		png := imageframe.NotElegibleImage()
-		response := strings.ReplaceAll(frame(frameNotElegible), "{image}", imageLink(png))
-		response = strings.ReplaceAll(response, "{processID}", electionID.String())
-		return []byte(response), ErrNotInCensus
+		return errorFrame(frametemplates.ErrorNotEligible, png, "", ErrNotInCensus)
 	}
 
 	if errors.Is(err, ErrAlreadyVoted) {
@@ -246,10 +348,34 @@ func handleVoteError(err error, voteData *voteData, electionID types.HexBytes) (
 			"nullifier", fmt.Sprintf("%x", voteData.Nullifier),
 		)
 		png := imageframe.AlreadyVotedImage()
-		response := strings.ReplaceAll(frame(frameAlreadyVoted), "{image}", imageLink(png))
-		response = strings.ReplaceAll(response, "{nullifier}", fmt.Sprintf("%x", voteData.Nullifier))
-		response = strings.ReplaceAll(response, "{processID}", electionID.String())
-		return []byte(response), ErrAlreadyVoted
+		return errorFrame(frametemplates.ErrorAlreadyVoted, png, fmt.Sprintf("%x", voteData.Nullifier), ErrAlreadyVoted)
+	}
+
+	if errors.Is(err, ErrFrameMessageExpired) || errors.Is(err, ErrFrameStateMismatch) {
+		log.Debugw("outdated frame message",
+			"electionID", electionID.String(),
+			"error", err,
+		)
+		png := imageframe.ExpiredFrameImage()
+		return errorFrame(frametemplates.ErrorOutdated, png, "", err)
+	}
+
+	if errors.Is(err, ErrFrameURLMismatch) {
+		log.Debugw("frame message for another poll",
+			"electionID", electionID.String(),
+			"error", err,
+		)
+		png := imageframe.ForeignFrameImage()
+		return errorFrame(frametemplates.ErrorGeneric, png, "", err)
+	}
+
+	if errors.Is(err, framevalidator.ErrRevokedSigner) {
+		log.Debugw("participant signer revoked",
+			"electionID", electionID.String(),
+			"error", err,
+		)
+		png := imageframe.RevokedSignerImage()
+		return errorFrame(frametemplates.ErrorRevokedSigner, png, "", framevalidator.ErrRevokedSigner)
 	}
 
 	if errors.Is(err, ErrVoteDelegated) {
@@ -258,9 +384,7 @@ func handleVoteError(err error, voteData *voteData, electionID types.HexBytes) (
 			"fid", voteData.FID,
 		)
 		png := imageframe.AlreadyDelegated()
-		response := strings.ReplaceAll(frame(frameDelegatedVote), "{image}", imageLink(png))
-		response = strings.ReplaceAll(response, "{processID}", electionID.String())
-		return []byte(response), ErrVoteDelegated
+		return errorFrame(frametemplates.ErrorDelegated, png, "", ErrVoteDelegated)
 	}
 
 	if err != nil {
@@ -269,24 +393,26 @@ func handleVoteError(err error, voteData *voteData, electionID types.HexBytes) (
 		if err2 != nil {
 			return nil, fmt.Errorf("failed to create image: %w", err2)
 		}
-		response := strings.ReplaceAll(frame(frameError), "{image}", imageLink(png))
-		response = strings.ReplaceAll(response, "{processID}", electionID.String())
-		return []byte(response), err
+		return errorFrame(frametemplates.ErrorGeneric, png, "", err)
 	}
 	return nil, nil
 }
 
 // vote creates a vote transaction, including the frame signature packet and sends it to the vochain.
+// If the voter already voted, the vote overwrites the previous one, up to the maximum number of
+// overwrites provided.
 // It returns the nullifier of the vote (which is the unique identifier of the vote), the voterID and an error.
-func vote(packet *FrameSignaturePacket, electionID types.HexBytes, root []byte, cli *apiclient.HTTPclient) (*voteData, error) {
-	voteData, err := extractVoteDataAndCheckIfEligible(packet, electionID, root, cli)
+func vote(frameMsg *framevalidator.Result, electionID types.HexBytes, root []byte, maxOverwrites uint32,
+	cli *apiclient.HTTPclient,
+) (*voteData, error) {
+	voteData, err := extractVoteDataAndCheckIfEligible(frameMsg, electionID, root, maxOverwrites, cli)
 	if err != nil {
 		return nil, err
 	}
 
 	// build the vote package
 	votePackage := &state.VotePackage{
-		Votes: []int{packet.UntrustedData.ButtonIndex - 1},
+		Votes: []int{int(frameMsg.ButtonIndex) - 1},
 	}
 	votePackageBytes, err := votePackage.Encode()
 	if err != nil {
@@ -300,12 +426,6 @@ func vote(packet *FrameSignaturePacket, electionID types.HexBytes, root []byte,
 		VotePackage: votePackageBytes,
 	}
 
-	// build the proof for the vote transaction
-	frameSignedMessage, err := hex.DecodeString(packet.TrustedData.MessageBytes)
-	if err != nil {
-		return voteData, fmt.Errorf("failed to decode frame signed message: %w", err)
-	}
-
 	arboProof := &models.ProofArbo{
 		Type:            models.ProofArbo_BLAKE2B,
 		Siblings:        voteData.Proof.Proof,
@@ -317,7 +437,7 @@ func vote(packet *FrameSignaturePacket, electionID types.HexBytes, root []byte,
 	vote.Proof = &models.Proof{
 		Payload: &models.Proof_FarcasterFrame{
 			FarcasterFrame: &models.ProofFarcasterFrame{
-				SignedFrameMessageBody: frameSignedMessage,
+				SignedFrameMessageBody: frameMsg.MessageBytes,
 				PublicKey:              voteData.PubKey,
 				CensusProof:            arboProof,
 			},
@@ -335,6 +455,7 @@ func vote(packet *FrameSignaturePacket, electionID types.HexBytes, root []byte,
 		return voteData, fmt.Errorf("failed to sign and send vote transaction: %w", err)
 	}
 
-	log.Infow("vote transaction sent", "txHash", fmt.Sprintf("%x", txHash), "nullifier", fmt.Sprintf("%x", nullifier))
+	log.Infow("vote transaction sent", "txHash", fmt.Sprintf("%x", txHash), "nullifier", fmt.Sprintf("%x", nullifier),
+		"overwrite", voteData.Voted)
 	return voteData, nil
 }
@@ -2,15 +2,18 @@ package main
 
 import (
 	"context"
-	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/vocdoni/vote-frame/bot"
 	"github.com/vocdoni/vote-frame/bot/poll"
 	fapi "github.com/vocdoni/vote-frame/farcasterapi"
 	"github.com/vocdoni/vote-frame/farcasterapi/neynar"
+	"github.com/vocdoni/vote-frame/features"
+	"github.com/vocdoni/vote-frame/mongo"
+	"github.com/vocdoni/vote-frame/quota"
 	"github.com/vocdoni/vote-frame/shortener"
 	"go.vocdoni.io/dvote/httprouter"
 	"go.vocdoni.io/dvote/httprouter/apirest"
@@ -18,18 +21,63 @@ import (
 	"go.vocdoni.io/dvote/types"
 )
 
+// postResultsInterval is the interval to check for ended bot polls to post
+// their final results into the poll thread.
+const postResultsInterval = time.Minute
+
+// maxPostResultsAttempts is the maximum number of failed attempts to post the
+// final results of a bot poll, after which they are not retried anymore, for
+// example if the cast has been deleted.
+const maxPostResultsAttempts = 10
+
+// botCensusTimeout is the maximum time to wait for the census of a bot poll to
+// be created.
+const botCensusTimeout = time.Minute * 10
+
+// botMentionsCursor is the name of the event cursor of the bot mentions
+// stream stored in the database.
+const botMentionsCursor = "botMentions"
+
+// botCursorStore struct implements the bot.CursorStore interface storing the
+// event cursor with the name provided in the database.
+type botCursorStore struct {
+	db   *mongo.MongoStorage
+	name string
+}
+
+// Load returns the event cursor stored in the database.
+func (s *botCursorStore) Load() (uint64, error) {
+	return s.db.EventCursor(s.name)
+}
+
+// Save stores the event cursor provided in the database.
+func (s *botCursorStore) Save(eventID uint64) error {
+	return s.db.SetEventCursor(s.name, eventID)
+}
+
 // initBot helper function initializes the bot and starts listening for new polls
-// to create elections
+// to create elections. If stream is true, the bot streams its mentions from
+// the API events, resuming from the cursor stored in the database.
 func initBot(ctx context.Context, handler *vocdoniHandler, api fapi.API,
-	defaultCensus *CensusInfo,
+	defaultCensus *CensusInfo, stream bool,
 ) (*bot.Bot, error) {
-	voteBot, err := bot.New(bot.BotConfig{
+	config := bot.BotConfig{
 		API: api,
-	})
+	}
+	if stream {
+		if _, ok := api.(fapi.MentionsStreamer); !ok {
+			return nil, fmt.Errorf("the bot API does not support streaming mentions")
+		}
+		config.Cursor = &botCursorStore{db: handler.db, name: botMentionsCursor}
+	}
+	voteBot, err := bot.New(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create bot: %w", err)
 	}
 	voteBot.Start(ctx)
+	commands := botCommands(handler)
+	// post the final results of the bot polls in background
+	go postFinalResultsAtBackground(ctx, handler, voteBot)
 	// handle new messages in background
 	go func() {
 		for {
@@ -37,28 +85,47 @@ func initBot(ctx context.Context, handler *vocdoniHandler, api fapi.API,
 			case <-ctx.Done():
 				return
 			case msg := <-voteBot.Messages:
+				// check if the message is a command and handle it
+				isCommand, err := voteBot.CommandHandler(ctx, msg, commands)
+				if isCommand {
+					if err != nil {
+						log.Errorf("error handling command: %s", err)
+					}
+					continue
+				}
 				// check if the message is a poll and create an election
 				user, poll, isPool, err := voteBot.PollMessageHandler(ctx, msg, maxElectionDuration)
-				if err == nil && isPool {
-					if err := pollToCast(ctx, handler, poll, user, msg, voteBot, defaultCensus); err != nil {
-						log.Errorf("error creating election: %s", err)
-						continue
+				// if the message is a poll with a mistake, reply with it
+				if reason, ok := bot.PollParseErrorReason(err); ok {
+					if err := voteBot.ReplyWithPollError(ctx, msg, reason); err != nil {
+						log.Errorf("error replying to poll: %s", err)
 					}
-					log.Debugw("poll created and reply sent",
-						"poll", poll,
-						"userdata", user,
-						"msg-hash", msg.Hash)
 					continue
 				}
-				// check if the message is a mute request and mute the user
-				user, parentMsg, isMuteRequest, err := voteBot.MuteRequestHandler(ctx, msg)
-				if err == nil && isMuteRequest {
-					if err := mutePollCreator(handler, user, parentMsg); err != nil {
-						log.Errorf("error muting user: %s", err)
+				if err == nil && isPool {
+					// check if the user has exceeded the quota of polls
+					if err := handler.allowAction(user.FID, quota.PollCreation); err != nil {
+						var errExceeded *quota.ExceededError
+						if errors.As(err, &errExceeded) {
+							if err := voteBot.ReplyWithQuotaExceeded(ctx, msg, errExceeded.RetryAfter); err != nil {
+								log.Errorf("error replying to poll: %s", err)
+							}
+						}
+						log.Infow("poll quota exceeded", "fid", user.FID, "error", err)
+						continue
 					}
-					log.Debugw("poll creator muted",
-						"user", user,
-						"poll", parentMsg.Embeds)
+					// create the poll in background, since creating its census
+					// can take a while
+					go func() {
+						if err := pollToCast(ctx, handler, poll, user, msg, voteBot, defaultCensus); err != nil {
+							log.Errorf("error creating election: %s", err)
+							return
+						}
+						log.Debugw("poll created and reply sent",
+							"poll", poll,
+							"userdata", user,
+							"msg-hash", msg.Hash)
+					}()
 					continue
 				}
 			}
@@ -86,8 +153,43 @@ func pollToCast(ctx context.Context, handler *vocdoniHandler, poll *poll.Poll,
 		Custody:       user.CustodyAddress,
 		Verifications: user.VerificationsAddresses,
 	}
+	// get the community of the poll, based on the directives and the channel
+	// of the cast, and check if the poll can last that long and the voters
+	// can be notified before building the census, if the author made a
+	// mistake, reply with it
+	community, err := botPollCommunity(handler, poll, user, msg)
+	if err == nil {
+		err = botPollDurationAllowed(handler, poll, user.FID, community)
+	}
+	if err == nil && poll.Notify {
+		err = botPollNotifyAllowed(handler, user.FID, community)
+	}
+	if err != nil {
+		var cmdErr *bot.CommandError
+		if errors.As(err, &cmdErr) {
+			if err := voteBot.ReplyWithPollError(ctx, msg, cmdErr.Message); err != nil {
+				log.Warnw("error replying to poll", "error", err)
+			}
+		}
+		return fmt.Errorf("error getting poll community: %w", err)
+	}
+	census, communityID, err := botPollCensus(ctx, handler, poll, user, msg, community, defaultCensus)
+	if err != nil {
+		var cmdErr *bot.CommandError
+		if errors.As(err, &cmdErr) {
+			if err := voteBot.ReplyWithPollError(ctx, msg, cmdErr.Message); err != nil {
+				log.Warnw("error replying to poll", "error", err)
+			}
+		}
+		return fmt.Errorf("error getting poll census: %w", err)
+	}
+	description.UsersCount = census.FarcasterParticipantCount
+	if description.UsersCount == 0 {
+		description.UsersCount = uint32(len(census.Usernames))
+	}
+	description.UsersCountInitial = uint32(census.FromTotalAddresses)
 	electionID, err := handler.createAndSaveElectionAndProfile(description,
-		defaultCensus, profile, true, false, "", ElectionSourceBot, nil)
+		census, profile, true, poll.Notify, "", ElectionSourceBot, communityID)
 	if err != nil {
 		return fmt.Errorf("error creating election: %w", err)
 	}
@@ -97,44 +199,257 @@ func pollToCast(ctx context.Context, handler *vocdoniHandler, poll *poll.Poll,
 		// if shortening fails, use the original url
 		shortenedUrl = frameUrl
 	}
-	if err := voteBot.ReplyWithPollURL(ctx, msg, shortenedUrl); err != nil {
-		return fmt.Errorf("error replying to poll: %s", err)
+	replyHash, replyErr := voteBot.ReplyWithPollURL(ctx, msg, shortenedUrl)
+	// store the bot cast that published the poll to post the final results
+	// into its thread when the poll ends, unless the author opted out; if the
+	// bot could not reply, fall back to the cast that created the poll
+	cast := &mongo.ElectionCast{
+		FID:         msg.Author,
+		Hash:        msg.Hash,
+		PostResults: !poll.SkipResults,
+	}
+	if replyErr == nil && replyHash != "" {
+		cast.FID = voteBot.UserData.FID
+		cast.Hash = replyHash
+	}
+	if err := handler.db.SetElectionCast(electionID, cast); err != nil {
+		log.Warnw("failed to store election cast", "electionID", electionID.String(), "error", err)
+	}
+	if replyErr != nil {
+		return fmt.Errorf("error replying to poll: %s", replyErr)
 	}
 	return nil
 }
 
-// mutePollCreator helper function mutes the poll creator user from notifications.
-// It extracts the election ID from the parent message embeds (expecting that
-// the election URL is included as a frame) and mutes the user that created the
-// election for the given user. If something goes wrong it returns an error.
-func mutePollCreator(handler *vocdoniHandler, user *fapi.Userdata, parent *fapi.APIMessage) error {
-	var electionID string
-	for _, embed := range parent.Embeds {
-		if strings.HasPrefix(embed, serverURL) {
-			// get the election ID from the embed URL in the parent message
-			// removing the server URL prefix, including the slash separator
-			electionID = strings.TrimPrefix(embed, serverURL+"/")
-			break
+// botPollCommunity helper function returns the community of the poll
+// provided, if any. If the poll includes the community directive, that
+// community is returned if the author is an admin of it and it is enabled. If
+// the poll does not include the census directive and it includes the channel
+// directive or it was cast in a channel, the community of the author for that
+// channel is returned. The errors caused by the author are returned as
+// *bot.CommandError to be replied to them.
+func botPollCommunity(handler *vocdoniHandler, p *poll.Poll, user *fapi.Userdata,
+	msg *fapi.APIMessage,
+) (*mongo.Community, error) {
+	if p.Community != "" {
+		community, err := handler.db.Community(p.Community)
+		if err != nil || community == nil {
+			return nil, bot.NewCommandError("community %s not found", p.Community)
+		}
+		if !handler.db.IsCommunityAdmin(user.FID, community.ID) {
+			return nil, bot.NewCommandError("only the admins of the community can create polls for it")
+		}
+		if community.Disabled {
+			return nil, bot.NewCommandError("the community is disabled")
+		}
+		return community, nil
+	}
+	if p.Census != "" {
+		return nil, nil
+	}
+	channelID := p.Channel
+	if channelID == "" {
+		channelID = msg.Channel
+	}
+	if channelID == "" {
+		return nil, nil
+	}
+	// use the community of the channel if the author is an admin of it
+	communities, _, err := handler.db.ListCommunitiesByAdminFID(user.FID, -1, 0)
+	if err != nil {
+		log.Warnw("failed to list communities of the poll author", "fid", user.FID, "error", err)
+	}
+	for _, community := range communities {
+		if community.Census.Type == mongo.TypeCommunityCensusChannel &&
+			community.Census.Channel == channelID && !community.Disabled {
+			return &community, nil
+		}
+	}
+	return nil, nil
+}
+
+// botPollCensus helper function returns the census to use in the poll
+// provided and the ID of its community, if any. If a community is provided,
+// its census is used. If the poll includes the census directive, the default
+// census or a new census of the followers of the author is used. If the poll
+// includes the channel directive or it was cast in a channel, a new
+// channel-gated census is created. Otherwise, the default census is used.
+// The errors caused by the author are returned as *bot.CommandError to be
+// replied to them.
+func botPollCensus(ctx context.Context, handler *vocdoniHandler, p *poll.Poll,
+	user *fapi.Userdata, msg *fapi.APIMessage, community *mongo.Community,
+	defaultCensus *CensusInfo,
+) (*CensusInfo, *string, error) {
+	ctx, cancel := context.WithTimeout(ctx, botCensusTimeout)
+	defer cancel()
+	if community != nil {
+		return botCommunityCensus(ctx, handler, community, user.FID)
+	}
+	switch p.Census {
+	case poll.CensusFarcaster:
+		return defaultCensus, nil, nil
+	case poll.CensusFollowers:
+		if err := handler.allowAction(user.FID, quota.CensusCreation); err != nil {
+			return nil, nil, bot.NewCommandError("you have created too many censuses recently, try it again later")
+		}
+		data, err := handler.censusFollowers(user.FID, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create followers census: %w", err)
+		}
+		census, err := handler.waitForCensus(ctx, data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create followers census: %w", err)
 		}
+		return census, nil, nil
+	}
+	channelID := p.Channel
+	if channelID == "" {
+		channelID = msg.Channel
+	}
+	if channelID == "" {
+		return defaultCensus, nil, nil
+	}
+	// create a channel-gated census
+	exists, err := handler.fcapi.ChannelExists(ctx, channelID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check channel: %w", err)
+	}
+	if !exists {
+		return nil, nil, bot.NewCommandError("channel /%s not found", channelID)
 	}
-	if electionID == "" {
-		return fmt.Errorf("election not found in embeds")
+	if err := handler.allowAction(user.FID, quota.CensusCreation); err != nil {
+		return nil, nil, bot.NewCommandError("you have created too many censuses recently, try it again later")
 	}
-	// get election from the database decoding the election ID from string
-	bElectionID, err := hex.DecodeString(electionID)
+	data, err := handler.censusWarpcastChannel(channelID, user.FID, nil)
 	if err != nil {
-		return fmt.Errorf("error decoding election ID: %w", err)
+		return nil, nil, fmt.Errorf("failed to create channel census: %w", err)
 	}
-	election, err := handler.db.Election(types.HexBytes(bElectionID))
+	census, err := handler.waitForCensus(ctx, data)
 	if err != nil {
-		return fmt.Errorf("error getting election from the database: %w", err)
+		return nil, nil, fmt.Errorf("failed to create channel census: %w", err)
 	}
-	if err := handler.db.AddNotificationMutedUser(user.FID, election.UserID); err != nil {
-		return fmt.Errorf("error muting user: %w", err)
+	return census, nil, nil
+}
+
+// botCommunityCensus helper function creates the census of the community
+// provided for a poll of the author provided, and returns it with the ID of
+// the community. The community must be ready.
+func botCommunityCensus(ctx context.Context, handler *vocdoniHandler, community *mongo.Community,
+	authorFID uint64,
+) (*CensusInfo, *string, error) {
+	// check if the community is ready (soft check, if it fails, continue)
+	ready, _, err := handler.CommunityStatus(community)
+	if err != nil {
+		log.Warnw("error getting community status", "err", err, "community", community.ID)
+	}
+	if !ready {
+		return nil, nil, bot.NewCommandError("the community is not ready yet")
+	}
+	data, err := handler.communityCensus(community, authorFID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create community census: %w", err)
+	}
+	census, err := handler.waitForCensus(ctx, data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create community census: %w", err)
+	}
+	communityID := community.ID
+	return census, &communityID, nil
+}
+
+// botPollDurationAllowed helper function checks if the author provided can
+// create a poll as long as the poll provided in the community provided, if
+// any. It returns a *bot.CommandError with the reason if they cannot.
+func botPollDurationAllowed(handler *vocdoniHandler, p *poll.Poll, authorFID uint64,
+	community *mongo.Community,
+) error {
+	if p.Duration <= features.LongPollsMinDuration {
+		return nil
+	}
+	communityID := ""
+	if community != nil {
+		communityID = community.ID
+	}
+	if !handler.featureAllowed(features.LONG_POLLS, communityID, authorFID) {
+		return bot.NewCommandError("you do not have enough reputation to create polls longer than 7 days")
+	}
+	return nil
+}
+
+// botPollNotifyAllowed helper function checks if the author provided can
+// notify the voters of a poll of the community provided. It returns a
+// *bot.CommandError with the reason if they cannot.
+func botPollNotifyAllowed(handler *vocdoniHandler, authorFID uint64, community *mongo.Community) error {
+	if community == nil {
+		return bot.NewCommandError("notifications are only available for community polls")
+	}
+	if !handler.featureAllowed(features.NOTIFY_USERS, community.ID, authorFID) {
+		return bot.NewCommandError("you do not have enough reputation to notify voters")
+	}
+	if !handler.db.CommunityAllowNotifications(community.ID) {
+		return bot.NewCommandError("the community does not allow notifications")
 	}
 	return nil
 }
 
+// postFinalResultsAtBackground helper function checks periodically for ended
+// bot polls whose final results have not been posted yet, and replies to the
+// bot cast that published them with a results summary and the final results
+// image. The polls whose final results are not ready yet are retried in the
+// next iteration, and the polls whose reply fails are retried up to
+// maxPostResultsAttempts times. It must run in the background.
+func postFinalResultsAtBackground(ctx context.Context, handler *vocdoniHandler, voteBot *bot.Bot) {
+	ticker := time.NewTicker(postResultsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			elections, err := handler.db.ElectionsPendingToPostResults()
+			if err != nil {
+				if mongo.IsDBClosed(err) {
+					log.Warn("database client is disconnected")
+					return
+				}
+				log.Errorw(err, "failed to get elections pending to post results")
+				continue
+			}
+			for _, election := range elections {
+				electionID := types.HexStringToHexBytes(election.ElectionID)
+				// the final results image is created when the results are
+				// finalized
+				if handler.db.FinalResultsPNG(electionID) == nil {
+					continue
+				}
+				results, err := handler.db.Results(electionID)
+				if err != nil {
+					log.Warnw("failed to get election results", "electionID", election.ElectionID, "error", err)
+					continue
+				}
+				summary := bot.ResultsSummary(election.Question, results.Choices, results.Votes)
+				imageURL := imageLink(election.ElectionID + "_final")
+				if err := voteBot.ReplyWithResults(ctx, election.Cast.FID, election.Cast.Hash,
+					summary, imageURL); err != nil {
+					log.Warnw("failed to post final results", "electionID", election.ElectionID, "error", err)
+					failed, err := handler.db.AddElectionResultsAttempt(electionID, maxPostResultsAttempts)
+					if err != nil {
+						log.Warnw("failed to register the results attempt", "electionID", election.ElectionID, "error", err)
+					} else if failed {
+						log.Warnw("giving up posting final results", "electionID", election.ElectionID,
+							"attempts", maxPostResultsAttempts)
+					}
+					continue
+				}
+				if err := handler.db.SetElectionResultsPosted(electionID); err != nil {
+					log.Warnw("failed to mark results as posted", "electionID", election.ElectionID, "error", err)
+				}
+				log.Infow("final results posted", "electionID", election.ElectionID, "castHash", election.Cast.Hash)
+			}
+		}
+	}
+}
+
 // neynarWebhook helper function returns a function that handles neynar webhooks.
 // It verifies the request and handles the webhook using the neynar client.
 func neynarWebhook(neynarcli *neynar.NeynarAPI, webhookSecret string) func(*apirest.APIdata, *httprouter.HTTPContext) error {
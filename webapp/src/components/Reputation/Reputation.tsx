@@ -209,10 +209,10 @@ export const ReputationTable = ({ username }: { username?: string }) => {
           </Td>
           <Td>
             {reputation.boosters.isVocdoniFarcasterFollower
-              ? reputation.boostersInfo.vocdoniFarcasterFollowerPuntuaction
+              ? reputation.boostersInfo.isVocdoniFarcasterFollower
               : 0}
           </Td>
-          <Td>{reputation.boostersInfo.vocdoniFarcasterFollowerPuntuaction}</Td>
+          <Td>{reputation.boostersInfo.isVocdoniFarcasterFollower}</Td>
         </Tr>
         <Tr>
           <Td>
@@ -220,22 +220,22 @@ export const ReputationTable = ({ username }: { username?: string }) => {
           </Td>
           <Td>
             {reputation.boosters.isVotecasterFarcasterFollower
-              ? reputation.boostersInfo.votecasterFarcasterFollowerPuntuaction
+              ? reputation.boostersInfo.isVotecasterFarcasterFollower
               : 0}
           </Td>
-          <Td>{reputation.boostersInfo.votecasterFarcasterFollowerPuntuaction}</Td>
+          <Td>{reputation.boostersInfo.isVotecasterFarcasterFollower}</Td>
         </Tr>
         <Tr>
           <Td>Own a votecaster NFT pass</Td>
-          <Td>{reputation.boosters.hasVotecasterNFTPass ? reputation.boostersInfo.votecasterNFTPassPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.votecasterNFTPassPuntuaction}</Td>
+          <Td>{reputation.boosters.hasVotecasterNFTPass ? reputation.boostersInfo.hasVotecasterNFTPass : 0}</Td>
+          <Td>{reputation.boostersInfo.hasVotecasterNFTPass}</Td>
         </Tr>
         <Tr>
           <Td>Own the votecaster launch NFT</Td>
           <Td>
-            {reputation.boosters.hasVotecasterLaunchNFT ? reputation.boostersInfo.votecasterLaunchNFTPuntuaction : 0}
+            {reputation.boosters.hasVotecasterLaunchNFT ? reputation.boostersInfo.hasVotecasterLaunchNFT : 0}
           </Td>
-          <Td>{reputation.boostersInfo.votecasterLaunchNFTPuntuaction}</Td>
+          <Td>{reputation.boostersInfo.hasVotecasterLaunchNFT}</Td>
         </Tr>
         <Tr>
           <Td>
@@ -243,32 +243,32 @@ export const ReputationTable = ({ username }: { username?: string }) => {
           </Td>
           <Td>
             {reputation.boosters.votecasterAnnouncementRecasted
-              ? reputation.boostersInfo.votecasterAnnouncementRecastedPuntuaction
+              ? reputation.boostersInfo.votecasterAnnouncementRecasted
               : 0}
           </Td>
-          <Td>{reputation.boostersInfo.votecasterAnnouncementRecastedPuntuaction}</Td>
+          <Td>{reputation.boostersInfo.votecasterAnnouncementRecasted}</Td>
         </Tr>
         <Tr>
           <Td>Hold Farcaster OG NFT</Td>
-          <Td>{reputation.boosters.hasFarcasterOGNFT ? reputation.boostersInfo.farcasterOGNFTPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.farcasterOGNFTPuntuaction}</Td>
+          <Td>{reputation.boosters.hasFarcasterOGNFT ? reputation.boostersInfo.hasFarcasterOGNFT : 0}</Td>
+          <Td>{reputation.boostersInfo.hasFarcasterOGNFT}</Td>
         </Tr>
         <Tr>
           <Td>Hold +10k $degen</Td>
-          <Td>{reputation.boosters.has10kDegenAtLeast ? reputation.boostersInfo.degenAtLeast10kPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.degenAtLeast10kPuntuaction}</Td>
+          <Td>{reputation.boosters.has10kDegenAtLeast ? reputation.boostersInfo.has10kDegenAtLeast : 0}</Td>
+          <Td>{reputation.boostersInfo.has10kDegenAtLeast}</Td>
         </Tr>
         <Tr>
           <Td>Hold DegenDAO NFT</Td>
-          <Td>{reputation.boosters.hasDegenDAONFT ? reputation.boostersInfo.degenDAONFTPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.degenDAONFTPuntuaction}</Td>
+          <Td>{reputation.boosters.hasDegenDAONFT ? reputation.boostersInfo.hasDegenDAONFT : 0}</Td>
+          <Td>{reputation.boostersInfo.hasDegenDAONFT}</Td>
         </Tr>
         <Tr>
           <Td>
             Hold <OpenSeaCollection slug='degen-haberdashers'>Haberdashery NFT</OpenSeaCollection>
           </Td>
-          <Td>{reputation.boosters.hasHaberdasheryNFT ? reputation.boostersInfo.haberdasheryNFTPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.haberdasheryNFTPuntuaction}</Td>
+          <Td>{reputation.boosters.hasHaberdasheryNFT ? reputation.boostersInfo.hasHaberdasheryNFT : 0}</Td>
+          <Td>{reputation.boostersInfo.hasHaberdasheryNFT}</Td>
         </Tr>
         <Tr>
           <Td>
@@ -277,37 +277,37 @@ export const ReputationTable = ({ username }: { username?: string }) => {
               🥝Kiwi NFT
             </Link>
           </Td>
-          <Td>{reputation.boosters.hasKIWI ? reputation.boostersInfo.kiwiPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.kiwiPuntuaction}</Td>
+          <Td>{reputation.boosters.hasKIWI ? reputation.boostersInfo.hasKIWI : 0}</Td>
+          <Td>{reputation.boostersInfo.hasKIWI}</Td>
         </Tr>
         <Tr>
           <Td>
             Hold <WarpcastLink path='betashop.eth/0xd375d45d'>Ⓜ️oxie Pass</WarpcastLink>
           </Td>
-          <Td>{reputation.boosters.hasMoxiePass ? reputation.boostersInfo.moxiePassPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.moxiePassPuntuaction}</Td>
+          <Td>{reputation.boosters.hasMoxiePass ? reputation.boostersInfo.hasMoxiePass : 0}</Td>
+          <Td>{reputation.boostersInfo.hasMoxiePass}</Td>
         </Tr>
         <Tr>
           <Td>Hold .degen NFT</Td>
-          <Td>{reputation.boosters.hasNameDegen ? reputation.boostersInfo.nameDegenPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.nameDegenPuntuaction}</Td>
+          <Td>{reputation.boosters.hasNameDegen ? reputation.boostersInfo.hasNameDegen : 0}</Td>
+          <Td>{reputation.boostersInfo.hasNameDegen}</Td>
         </Tr>
         <Tr>
           <Td>Hold ProxyStudio NFT</Td>
-          <Td>{reputation.boosters.hasProxyStudioNFT ? reputation.boostersInfo.proxyStudioNFTPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.proxyStudioNFTPuntuaction}</Td>
+          <Td>{reputation.boosters.hasProxyStudioNFT ? reputation.boostersInfo.hasProxyStudioNFT : 0}</Td>
+          <Td>{reputation.boostersInfo.hasProxyStudioNFT}</Td>
         </Tr>
         <Tr>
           <Td>Hold +5 $PROXY</Td>
-          <Td>{reputation.boosters.has5ProxyAtLeast ? reputation.boostersInfo.proxyAtLeast5Puntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.proxyAtLeast5Puntuaction}</Td>
+          <Td>{reputation.boosters.has5ProxyAtLeast ? reputation.boostersInfo.has5ProxyAtLeast : 0}</Td>
+          <Td>{reputation.boostersInfo.has5ProxyAtLeast}</Td>
         </Tr>
         <Tr>
           <Td>
             Hold <OpenSeaCollection slug='tokyo-dao-1'>TokyoDAO NFT</OpenSeaCollection>
           </Td>
-          <Td>{reputation.boosters.hasTokyoDAONFT ? reputation.boostersInfo.tokyoDAONFTPuntuaction : 0}</Td>
-          <Td>{reputation.boostersInfo.tokyoDAONFTPuntuaction}</Td>
+          <Td>{reputation.boosters.hasTokyoDAONFT ? reputation.boostersInfo.hasTokyoDAONFT : 0}</Td>
+          <Td>{reputation.boostersInfo.hasTokyoDAONFT}</Td>
         </Tr>
         <Tr fontWeight='bold'>
           <Td>Totals</Td>
@@ -20,22 +20,22 @@ const baseRep = {
     hasMoxiePass: false,
   },
   boostersInfo: {
-    degenAtLeast10kPuntuaction: 0,
-    degenDAONFTPuntuaction: 0,
-    farcasterOGNFTPuntuaction: 0,
-    haberdasheryNFTPuntuaction: 0,
-    kiwiPuntuaction: 0,
-    moxiePassPuntuaction: 0,
-    nameDegenPuntuaction: 0,
-    proxyAtLeast5Puntuaction: 0,
-    proxyStudioNFTPuntuaction: 0,
-    tokyoDAONFTPuntuaction: 0,
-    vocdoniFarcasterFollowerPuntuaction: 0,
-    votecasterAlphafrensFollowerPuntuaction: 0,
-    votecasterAnnouncementRecastedPuntuaction: 0,
-    votecasterFarcasterFollowerPuntuaction: 0,
-    votecasterLaunchNFTPuntuaction: 0,
-    votecasterNFTPassPuntuaction: 0,
+    has10kDegenAtLeast: 0,
+    hasDegenDAONFT: 0,
+    hasFarcasterOGNFT: 0,
+    hasHaberdasheryNFT: 0,
+    hasKIWI: 0,
+    hasMoxiePass: 0,
+    hasNameDegen: 0,
+    has5ProxyAtLeast: 0,
+    hasProxyStudioNFT: 0,
+    hasTokyoDAONFT: 0,
+    isVocdoniFarcasterFollower: 0,
+    isVotecasterAlphafrensFollower: 0,
+    votecasterAnnouncementRecasted: 0,
+    isVotecasterFarcasterFollower: 0,
+    hasVotecasterLaunchNFT: 0,
+    hasVotecasterNFTPass: 0,
   },
   activityPoints: {
     followersPoints: 0,